@@ -10,6 +10,7 @@ import (
 	"github.com/nevalang/neva/internal/builder"
 	"github.com/nevalang/neva/internal/compiler"
 	"github.com/nevalang/neva/internal/compiler/analyzer"
+	"github.com/nevalang/neva/internal/compiler/linter"
 	"github.com/nevalang/neva/internal/compiler/parser"
 	src "github.com/nevalang/neva/internal/compiler/sourcecode"
 )
@@ -17,9 +18,12 @@ import (
 type Indexer struct {
 	fe       compiler.Frontend
 	analyzer analyzer.Analyzer
+	linter   linter.Linter
 	logger   commonlog.Logger
 }
 
+// FullScan returns analyzed build of the workspace module.
+// If build is valid, returned error contains lint warnings, if there are any.
 func (i Indexer) FullScan(
 	ctx context.Context,
 	workspacePath string,
@@ -41,10 +45,10 @@ func (i Indexer) FullScan(
 
 	aBuild, err := i.analyzer.AnalyzeBuild(feResult.ParsedBuild)
 	if err != nil {
-		return src.Build{}, true, err.Unwrap() // use only deepest compiler error for now
+		return src.Build{}, true, err
 	}
 
-	return aBuild, true, nil
+	return aBuild, true, i.linter.Lint(feResult.ParsedBuild, aBuild)
 }
 
 func isParentPath(parent, child string) bool {
@@ -71,6 +75,7 @@ func New(
 	return Indexer{
 		fe:       compiler.NewFrontend(builder, parser),
 		analyzer: analyzer,
+		linter:   linter.New(),
 		logger:   logger,
 	}
 }
@@ -34,7 +34,7 @@ func main() {
 	terminator := typesystem.Terminator{}
 	checker := typesystem.MustNewSubtypeChecker(terminator)
 	resolver := typesystem.MustNewResolver(typesystem.Validator{}, checker, terminator)
-	builder := builder.MustNew(p)
+	builder := builder.MustNew(p).WithTests() // test files must be checked too
 
 	indexer := indexer.New(builder, p, analyzer.MustNew(resolver), logger)
 
@@ -3,13 +3,21 @@ package server
 import (
 	"github.com/tliron/glsp"
 	protocol "github.com/tliron/glsp/protocol_3_16"
+
+	"github.com/nevalang/neva/internal/compiler"
 )
 
 func (s *Server) Initialize(glspCtx *glsp.Context, params *protocol.InitializeParams) (any, error) {
 	s.workspacePath = *params.RootPath
 
+	capabilities := s.handler.CreateServerCapabilities()
+	// formatting needs current content of the document so we ask for the whole document on every change
+	if syncOpts, ok := capabilities.TextDocumentSync.(*protocol.TextDocumentSyncOptions); ok {
+		syncOpts.Change = compiler.Pointer(protocol.TextDocumentSyncKindFull)
+	}
+
 	return protocol.InitializeResult{
-		Capabilities: s.handler.CreateServerCapabilities(),
+		Capabilities: capabilities,
 		ServerInfo: &protocol.InitializeResultServerInfo{
 			Name:    s.name,
 			Version: &s.version,
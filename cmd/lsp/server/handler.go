@@ -58,6 +58,8 @@ func BuildHandler(logger commonlog.Logger, serverName string, indexer indexer.In
 		problemFiles:    make(map[string]struct{}),
 		activeFile:      "",
 		activeFileMutex: &sync.Mutex{},
+		documents:       make(map[string]string),
+		documentsMutex:  &sync.Mutex{},
 	}
 
 	// Basic
@@ -120,9 +122,7 @@ func BuildHandler(logger commonlog.Logger, serverName string, indexer indexer.In
 		return nil
 	}
 
-	h.TextDocumentDidOpen = func(glpsCtx *glsp.Context, params *protocol.DidOpenTextDocumentParams) error {
-		return nil
-	}
+	h.TextDocumentDidOpen = s.TextDocumentDidOpen
 	h.TextDocumentDidChange = s.TextDocumentDidChange
 	h.TextDocumentWillSave = func(context *glsp.Context, params *protocol.WillSaveTextDocumentParams) error {
 		return nil
@@ -131,9 +131,7 @@ func BuildHandler(logger commonlog.Logger, serverName string, indexer indexer.In
 		return nil, nil
 	}
 	h.TextDocumentDidSave = s.TextDocumentDidSave
-	h.TextDocumentDidClose = func(context *glsp.Context, params *protocol.DidCloseTextDocumentParams) error {
-		return nil
-	}
+	h.TextDocumentDidClose = s.TextDocumentDidClose
 
 	h.TextDocumentCompletion = s.TextDocumentCompletion
 	h.CompletionItemResolve = nil
@@ -154,7 +152,7 @@ func BuildHandler(logger commonlog.Logger, serverName string, indexer indexer.In
 	h.DocumentLinkResolve = nil
 	h.TextDocumentColor = nil
 	h.TextDocumentColorPresentation = nil
-	h.TextDocumentFormatting = nil
+	h.TextDocumentFormatting = s.TextDocumentFormatting
 	h.TextDocumentRangeFormatting = nil
 	h.TextDocumentOnTypeFormatting = nil
 	h.TextDocumentRename = nil
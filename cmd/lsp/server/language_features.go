@@ -1,8 +1,15 @@
 package server
 
 import (
+	"net/url"
+	"os"
+	"strings"
+	"unicode/utf16"
+
 	"github.com/tliron/glsp"
 	protocol "github.com/tliron/glsp/protocol_3_16"
+
+	"github.com/nevalang/neva/internal/compiler/formatter"
 )
 
 func (s *Server) TextDocumentCompletion(
@@ -23,3 +30,68 @@ func (s *Server) TextDocumentCompletion(
 	// 	},
 	// }, nil
 }
+
+func (s *Server) TextDocumentFormatting(
+	glspCtx *glsp.Context,
+	params *protocol.DocumentFormattingParams,
+) ([]protocol.TextEdit, error) {
+	s.logger.Info("TextDocumentFormatting")
+
+	content, err := s.documentContent(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, compilerErr := formatter.Format([]byte(content))
+	if compilerErr != nil {
+		// syntax errors are reported as diagnostics, document is left as is
+		s.logger.Info("document not formatted", "err", compilerErr)
+		return nil, nil
+	}
+
+	if string(formatted) == content {
+		return nil, nil
+	}
+
+	return []protocol.TextEdit{
+		{
+			Range:   wholeDocumentRange(content),
+			NewText: string(formatted),
+		},
+	}, nil
+}
+
+// documentContent returns content of the opened document or reads the file if it's not opened.
+func (s *Server) documentContent(uri string) (string, error) {
+	s.documentsMutex.Lock()
+	content, ok := s.documents[uri]
+	s.documentsMutex.Unlock()
+	if ok {
+		return content, nil
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+
+	bb, err := os.ReadFile(parsed.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return string(bb), nil
+}
+
+// wholeDocumentRange returns range from the beginning to the end of the document.
+// Characters are counted in UTF-16 code units as protocol requires.
+func wholeDocumentRange(content string) protocol.Range {
+	lastLine := content[strings.LastIndex(content, "\n")+1:]
+	return protocol.Range{
+		Start: protocol.Position{Line: 0, Character: 0},
+		End: protocol.Position{
+			Line:      uint32(strings.Count(content, "\n")),
+			Character: uint32(len(utf16.Encode([]rune(lastLine)))),
+		},
+	}
+}
@@ -31,10 +31,14 @@ type Server struct {
 
 	activeFile      string
 	activeFileMutex *sync.Mutex
+
+	documents      map[string]string // content of opened documents by uri, may contain unsaved changes
+	documentsMutex *sync.Mutex
 }
 
 // indexAndNotifyProblems does full scan of the workspace
-// and sends diagnostics if there are any problems
+// and sends diagnostics for every file with problems.
+// Diagnostics of files that no longer have problems are cleared.
 func (s *Server) indexAndNotifyProblems(notify glsp.NotifyFunc) error {
 	build, found, compilerErr := s.indexer.FullScan(
 		context.Background(),
@@ -48,76 +52,105 @@ func (s *Server) indexAndNotifyProblems(notify glsp.NotifyFunc) error {
 	s.index = &build
 	s.indexMutex.Unlock()
 
-	if compilerErr == nil {
-		// clear problems
-		s.problemsMutex.Lock()
-		for uri := range s.problemFiles {
-			notify(
-				protocol.ServerTextDocumentPublishDiagnostics,
-				protocol.PublishDiagnosticsParams{
-					URI:         uri,
-					Diagnostics: []protocol.Diagnostic{},
-				},
-			)
+	// group deepest errors by files they belong to
+	problems := map[string][]compiler.Error{}
+	if compilerErr != nil {
+		for _, err := range compilerErr.Errors() {
+			var uri string
+			if err.Meta != nil {
+				uri = filepath.Join(s.workspacePath, err.Meta.Location.String())
+			} else {
+				uri = s.workspacePath
+			}
+			problems[uri] = append(problems[uri], *err)
 		}
-		s.problemFiles = make(map[string]struct{})
-		s.logger.Info("full index without problems, sent empty diagnostics")
-		s.problemsMutex.Unlock()
-		return nil
 	}
 
-	// remember problem and send diagnostic
 	s.problemsMutex.Lock()
-	uri := filepath.Join(s.workspacePath, compilerErr.Meta.Location.String()) // we assume compilerErr is deepest child (for now)
-	s.problemFiles[uri] = struct{}{}
-	notify(
-		protocol.ServerTextDocumentPublishDiagnostics,
-		s.createDiagnostics(*compilerErr, uri),
-	)
-	s.logger.Info("diagnostic sent:", "err", compilerErr)
-	s.problemsMutex.Unlock()
+	defer s.problemsMutex.Unlock()
+
+	// clear problems of files that are fixed
+	for uri := range s.problemFiles {
+		if _, ok := problems[uri]; ok {
+			continue
+		}
+		notify(
+			protocol.ServerTextDocumentPublishDiagnostics,
+			protocol.PublishDiagnosticsParams{
+				URI:         uri,
+				Diagnostics: []protocol.Diagnostic{},
+			},
+		)
+	}
+
+	// remember problems and send diagnostics
+	s.problemFiles = make(map[string]struct{}, len(problems))
+	for uri, errs := range problems {
+		s.problemFiles[uri] = struct{}{}
+		notify(
+			protocol.ServerTextDocumentPublishDiagnostics,
+			s.createDiagnostics(errs, uri),
+		)
+	}
+
+	if compilerErr == nil {
+		s.logger.Info("full index without problems, sent empty diagnostics")
+	} else {
+		s.logger.Info("diagnostics sent:", "err", compilerErr)
+	}
 
 	return nil
 }
 
 func (s *Server) createDiagnostics(
-	compilerErr compiler.Error, // deepest child (for now) compiler error
+	compilerErrs []compiler.Error, // deepest compiler errors of the file
 	uri string,
 ) protocol.PublishDiagnosticsParams {
-	var startStopRange protocol.Range
-	if compilerErr.Meta != nil {
-		// If stop is 0 0, set it to the same as start but with character incremented by 1
-		if compilerErr.Meta.Stop.Line == 0 && compilerErr.Meta.Stop.Column == 0 {
-			compilerErr.Meta.Stop = compilerErr.Meta.Start
-			compilerErr.Meta.Stop.Column++
+	diagnostics := make([]protocol.Diagnostic, 0, len(compilerErrs))
+
+	for _, compilerErr := range compilerErrs {
+		var startStopRange protocol.Range
+		if compilerErr.Meta != nil {
+			meta := *compilerErr.Meta
+
+			// If stop is 0 0, set it to the same as start but with character incremented by 1
+			if meta.Stop.Line == 0 && meta.Stop.Column == 0 {
+				meta.Stop = meta.Start
+				meta.Stop.Column++
+			}
+
+			startStopRange = protocol.Range{
+				Start: protocol.Position{
+					Line:      uint32(meta.Start.Line),
+					Character: uint32(meta.Start.Column),
+				},
+				End: protocol.Position{
+					Line:      uint32(meta.Stop.Line),
+					Character: uint32(meta.Stop.Column),
+				},
+			}
+
+			// Adjust for 0-based indexing
+			startStopRange.Start.Line--
+			startStopRange.End.Line--
 		}
 
-		startStopRange = protocol.Range{
-			Start: protocol.Position{
-				Line:      uint32(compilerErr.Meta.Start.Line),
-				Character: uint32(compilerErr.Meta.Start.Column),
-			},
-			End: protocol.Position{
-				Line:      uint32(compilerErr.Meta.Stop.Line),
-				Character: uint32(compilerErr.Meta.Stop.Column),
-			},
+		severity := protocol.DiagnosticSeverityError
+		if compilerErr.Severity == compiler.SeverityWarning {
+			severity = protocol.DiagnosticSeverityWarning
 		}
 
-		// Adjust for 0-based indexing
-		startStopRange.Start.Line--
-		startStopRange.End.Line--
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range:    startStopRange,
+			Severity: &severity,
+			Source:   compiler.Pointer("compiler"),
+			Message:  compilerErr.Message, // we don't use Error() because it will duplicate location
+			Data:     time.Now(),
+		})
 	}
 
 	return protocol.PublishDiagnosticsParams{
-		URI: uri, // uri must be full path to the file, make sure all compiler errors include full location
-		Diagnostics: []protocol.Diagnostic{
-			{
-				Range:    startStopRange,
-				Severity: compiler.Pointer(protocol.DiagnosticSeverityError),
-				Source:   compiler.Pointer("compiler"),
-				Message:  compilerErr.Message, // we don't use Error() because it will duplicate location
-				Data:     time.Now(),
-			},
-		},
+		URI:         uri, // uri must be full path to the file, make sure all compiler errors include full location
+		Diagnostics: diagnostics,
 	}
 }
@@ -12,6 +12,11 @@ func (s *Server) TextDocumentDidOpen(
 	s.activeFileMutex.Lock()
 	s.activeFile = params.TextDocument.URI
 	s.activeFileMutex.Unlock()
+
+	s.documentsMutex.Lock()
+	s.documents[params.TextDocument.URI] = params.TextDocument.Text
+	s.documentsMutex.Unlock()
+
 	return nil
 }
 
@@ -22,6 +27,16 @@ func (s *Server) TextDocumentDidChange(
 	s.activeFileMutex.Lock()
 	s.activeFile = params.TextDocument.URI
 	s.activeFileMutex.Unlock()
+
+	// server asks for full sync so every change contains whole document
+	s.documentsMutex.Lock()
+	for _, change := range params.ContentChanges {
+		if whole, ok := change.(protocol.TextDocumentContentChangeEventWhole); ok {
+			s.documents[params.TextDocument.URI] = whole.Text
+		}
+	}
+	s.documentsMutex.Unlock()
+
 	return nil
 }
 
@@ -32,3 +47,13 @@ func (s *Server) TextDocumentDidSave(
 	s.logger.Info("TextDocumentDidSave")
 	return s.indexAndNotifyProblems(glspCtx.Notify)
 }
+
+func (s *Server) TextDocumentDidClose(
+	glspCtx *glsp.Context,
+	params *protocol.DidCloseTextDocumentParams,
+) error {
+	s.documentsMutex.Lock()
+	delete(s.documents, params.TextDocument.URI)
+	s.documentsMutex.Unlock()
+	return nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/nevalang/neva/internal/builder"
 	"github.com/nevalang/neva/internal/cli"
@@ -13,6 +14,7 @@ import (
 	"github.com/nevalang/neva/internal/compiler/backend/golang/native"
 	"github.com/nevalang/neva/internal/compiler/backend/golang/wasm"
 	"github.com/nevalang/neva/internal/compiler/backend/json"
+	"github.com/nevalang/neva/internal/compiler/cache"
 	"github.com/nevalang/neva/internal/compiler/desugarer"
 	"github.com/nevalang/neva/internal/compiler/irgen"
 	"github.com/nevalang/neva/internal/compiler/parser"
@@ -32,6 +34,14 @@ func main() {
 	prsr := parser.New()
 	bldr := builder.MustNew(prsr)
 
+	// results of parsing and analysis are reused between builds unless disabled
+	var cch compiler.Cache
+	if dir, ok := cache.Dir(); ok {
+		diskCache := cache.New(dir)
+		cch = diskCache
+		defer diskCache.Trim(time.Now()) // it is trimmed next time if it fails
+	}
+
 	desugarer := desugarer.New()
 	analyzer := analyzer.MustNew(resolver)
 	irgen := irgen.New()
@@ -45,7 +55,7 @@ func main() {
 		analyzer,
 		irgen,
 		golang.NewBackend(),
-	)
+	).WithCache(cch)
 
 	nativeCompiler := compiler.New(
 		bldr,
@@ -54,7 +64,7 @@ func main() {
 		analyzer,
 		irgen,
 		native.NewBackend(golangBackend),
-	)
+	).WithCache(cch)
 
 	wasmCompiler := compiler.New(
 		bldr,
@@ -63,7 +73,16 @@ func main() {
 		analyzer,
 		irgen,
 		wasm.NewBackend(golangBackend),
-	)
+	).WithCache(cch)
+
+	wasiCompiler := compiler.New(
+		bldr,
+		prsr,
+		&desugarer,
+		analyzer,
+		irgen,
+		wasm.NewWASIBackend(golangBackend),
+	).WithCache(cch)
 
 	jsonCompiler := compiler.New(
 		bldr,
@@ -72,7 +91,7 @@ func main() {
 		analyzer,
 		irgen,
 		json.NewBackend(),
-	)
+	).WithCache(cch)
 
 	dotCompiler := compiler.New(
 		bldr,
@@ -81,7 +100,35 @@ func main() {
 		analyzer,
 		irgen,
 		dot.NewBackend(),
-	)
+	).WithCache(cch)
+
+	svgCompiler := compiler.New(
+		bldr,
+		prsr,
+		&desugarer,
+		analyzer,
+		irgen,
+		dot.NewSVGBackend(),
+	).WithCache(cch)
+
+	htmlCompiler := compiler.New(
+		bldr,
+		prsr,
+		&desugarer,
+		analyzer,
+		irgen,
+		dot.NewHTMLBackend(),
+	).WithCache(cch)
+
+	// tests are executed in-process, backend is only used for the ones that depend on native code
+	testCompiler := compiler.New(
+		bldr.WithTests(),
+		prsr,
+		&desugarer,
+		analyzer,
+		irgen,
+		native.NewBackend(golangBackend),
+	).WithCache(cch)
 
 	// command-line app that can compile and interpret neva code
 	app := cli.NewApp(
@@ -90,8 +137,12 @@ func main() {
 		goCompiler,
 		nativeCompiler,
 		wasmCompiler,
+		wasiCompiler,
 		jsonCompiler,
 		dotCompiler,
+		svgCompiler,
+		htmlCompiler,
+		testCompiler,
 	)
 
 	// run CLI app
@@ -18,7 +18,11 @@ func Test(t *testing.T) {
 	require.Contains(
 		t,
 		string(out),
-		"main/main.neva:4:1: array inport 'printf:args' is used incorrectly: slot 1 is missing\n",
+		"error: array inport 'printf:args' is used incorrectly: slot 1 is missing\n"+
+			" --> main/main.neva:4:1\n"+
+			"  |\n"+
+			"4 |     fmt.Printf\n"+
+			"  |     ^^^^^^^^^^\n",
 	)
 
 	require.Equal(t, 0, cmd.ProcessState.ExitCode())
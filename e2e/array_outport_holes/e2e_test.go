@@ -18,7 +18,11 @@ func Test(t *testing.T) {
 	require.Contains(
 		t,
 		string(out),
-		"main/main.neva:4:1: array outport 'fanOut:data' is used incorrectly: slot 1 is missing\n",
+		"error: array outport 'fanOut:data' is used incorrectly: slot 1 is missing\n"+
+			" --> main/main.neva:4:1\n"+
+			"  |\n"+
+			"4 |     FanOut\n"+
+			"  |     ^^^^^^\n",
 	)
 
 	require.Equal(t, 0, cmd.ProcessState.ExitCode())
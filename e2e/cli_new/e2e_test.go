@@ -3,8 +3,12 @@ package test
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
+	"time"
 
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,3 +32,107 @@ func Test(t *testing.T) {
 
 	require.Equal(t, 0, cmd.ProcessState.ExitCode())
 }
+
+func TestBuiltinTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	out, err := exec.Command("neva", "new", "--template", "pipeline", filepath.Join(dir, "pipeline")).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	cmd := exec.Command("neva", "run", "src")
+	cmd.Dir = filepath.Join(dir, "pipeline")
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(t, "220\n", string(out))
+
+	out, err = exec.Command("neva", "new", "--template", "lib", filepath.Join(dir, "lib")).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	cmd = exec.Command("neva", "test", "lib")
+	cmd.Dir = filepath.Join(dir, "lib")
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Contains(t, string(out), "--- PASS: TestGreet")
+
+	out, _ = exec.Command("neva", "new", "--template", "unknown", filepath.Join(dir, "unknown")).CombinedOutput()
+	require.Contains(t, string(out), "template not found: unknown")
+}
+
+func TestLocalTemplate(t *testing.T) {
+	tmpl := t.TempDir()
+	writeTemplate(t, tmpl)
+
+	dir := filepath.Join(t.TempDir(), "project")
+	out, err := exec.Command(
+		"neva", "new",
+		"--template", tmpl,
+		"--module", "demo",
+		"--author", "Ann",
+		dir,
+	).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	cmd := exec.Command("neva", "run", "demo")
+	cmd.Dir = dir
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(t, "Ann made demo\n", string(out))
+
+	// existing files are not overwritten
+	cmd = exec.Command("neva", "new", "--template", tmpl, "--module", "demo")
+	cmd.Dir = dir
+	out, _ = cmd.CombinedOutput()
+	require.Contains(t, string(out), "file already exists")
+}
+
+func TestGitTemplate(t *testing.T) {
+	tmpl := t.TempDir()
+	writeTemplate(t, tmpl)
+
+	repo, err := git.PlainInit(tmpl, false)
+	require.NoError(t, err)
+	tree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = tree.Add(".")
+	require.NoError(t, err)
+	_, err = tree.Commit("template", &git.CommitOptions{
+		Author: &object.Signature{Name: "Ann", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "project")
+	out, err := exec.Command(
+		"neva", "new",
+		"--template", "file://"+tmpl,
+		"--module", "demo",
+		"--author", "Bob",
+		dir,
+	).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	_, err = os.Stat(filepath.Join(dir, ".git"))
+	require.True(t, os.IsNotExist(err))
+
+	cmd := exec.Command("neva", "run", "demo")
+	cmd.Dir = dir
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(t, "Bob made demo\n", string(out))
+}
+
+// writeTemplate writes template without manifest and with variable in the package path.
+func writeTemplate(t *testing.T, dir string) {
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "{{module}}"), 0755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "{{module}}", "main.neva"),
+		[]byte(`import { fmt }
+
+def Main(start any) (stop any) {
+	fmt.Println
+	---
+	:start -> '{{author}} made {{module}}' -> println -> :stop
+}
+`),
+		0644,
+	))
+}
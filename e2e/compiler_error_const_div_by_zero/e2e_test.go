@@ -0,0 +1,27 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test(t *testing.T) {
+	cmd := exec.Command("neva", "run", "main")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		"error: Constant division by zero: (x / (x - 10))\n"+
+			" --> main/main.neva:8:16\n"+
+			"  |\n"+
+			"8 |     :start -> { ($x / ($x - 10)) -> println -> :stop }\n"+
+			"  |                 ^^^^^^^^^^^^^^^^\n",
+		string(out),
+	)
+
+	require.Equal(t, 0, cmd.ProcessState.ExitCode())
+}
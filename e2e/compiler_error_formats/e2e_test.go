@@ -0,0 +1,102 @@
+package test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestText(t *testing.T) {
+	cmd := exec.Command("neva", "run", "main")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		"error: Port not found 'del:foo'\n"+
+			" --> main/main.neva:4:14\n"+
+			"  |\n"+
+			"4 |     :start -> del:foo\n"+
+			"  |               ^^^^^^^\n"+
+			"note: ports of node 'del' are declared here\n"+
+			" --> main/main.neva:8:4\n"+
+			"  |\n"+
+			"8 | def Del(data any) (sig any) {\n"+
+			"  |     ^^^^^^^^^^^^^^^^^^^^^^^\n",
+		string(out),
+	)
+
+	require.Equal(t, 0, cmd.ProcessState.ExitCode())
+}
+
+func TestJSON(t *testing.T) {
+	cmd := exec.Command("neva", "run", "--error-format", "json", "main")
+
+	out, err := cmd.Output()
+	require.NoError(t, err)
+
+	var diagnostics []struct {
+		Severity string
+		Message  string
+		Location struct {
+			File        string
+			Start, Stop struct{ Line, Column int }
+		}
+		Notes []struct {
+			Message  string
+			Location struct{ File string }
+		}
+	}
+	require.NoError(t, json.Unmarshal(out, &diagnostics))
+
+	require.Len(t, diagnostics, 1)
+	require.Equal(t, "error", diagnostics[0].Severity)
+	require.Equal(t, "Port not found 'del:foo'", diagnostics[0].Message)
+	require.Equal(t, "main/main.neva", diagnostics[0].Location.File)
+	require.Equal(t, 4, diagnostics[0].Location.Start.Line)
+	require.Equal(t, 14, diagnostics[0].Location.Start.Column)
+	require.Equal(t, 21, diagnostics[0].Location.Stop.Column)
+	require.Len(t, diagnostics[0].Notes, 1)
+	require.Equal(t, "main/main.neva", diagnostics[0].Notes[0].Location.File)
+}
+
+func TestSARIF(t *testing.T) {
+	cmd := exec.Command("neva", "run", "--error-format", "sarif", "main")
+
+	out, err := cmd.Output()
+	require.NoError(t, err)
+
+	var log struct {
+		Version string
+		Runs    []struct {
+			Results []struct {
+				Level     string
+				Message   struct{ Text string }
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+						Region           struct{ StartLine, StartColumn, EndColumn int }
+					}
+				}
+				RelatedLocations []struct{ Message struct{ Text string } }
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(out, &log))
+
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	require.Len(t, log.Runs[0].Results, 1)
+
+	result := log.Runs[0].Results[0]
+	require.Equal(t, "error", result.Level)
+	require.Equal(t, "Port not found 'del:foo'", result.Message.Text)
+	require.Equal(t, "main/main.neva", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.Equal(t, 4, result.Locations[0].PhysicalLocation.Region.StartLine)
+	require.Equal(t, 15, result.Locations[0].PhysicalLocation.Region.StartColumn)
+	require.Equal(t, 22, result.Locations[0].PhysicalLocation.Region.EndColumn)
+	require.Len(t, result.RelatedLocations, 1)
+}
@@ -0,0 +1,26 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Errors of main package are reported along with independent errors of the build.
+func Test(t *testing.T) {
+	cmd := exec.Command("neva", "run", "--error-format", "short", "main")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		"main/main.neva:3:4: Main component must have exactly 1 inport: got 2\n"+
+			"main/main.neva:5:4: entity not found: Nope\n"+
+			"main/main.neva:15:4: port 'add:res' is used twice\n",
+		string(out),
+	)
+
+	require.Equal(t, 0, cmd.ProcessState.ExitCode())
+}
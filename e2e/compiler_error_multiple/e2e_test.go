@@ -0,0 +1,25 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test(t *testing.T) {
+	cmd := exec.Command("neva", "run", "--error-format", "short", "main")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		"main/main.neva:6:14: Port not found 'println:foo'\n"+
+			"main/main.neva:7:4: Port not found 'println:bar'\n"+
+			"main/main.neva:15:4: port 'add:res' is used twice\n",
+		string(out),
+	)
+
+	require.Equal(t, 0, cmd.ProcessState.ExitCode())
+}
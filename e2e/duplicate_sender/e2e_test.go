@@ -18,7 +18,11 @@ func Test(t *testing.T) {
 	require.Contains(
 		t,
 		string(out),
-		"main/main.neva:7:1: port 'in:start' is used twice\n",
+		"error: port 'in:start' is used twice\n"+
+			" --> main/main.neva:7:1\n"+
+			"  |\n"+
+			"7 |     :start -> panic\n"+
+			"  |     ^^^^^^\n",
 	)
 
 	require.Equal(t, 0, cmd.ProcessState.ExitCode())
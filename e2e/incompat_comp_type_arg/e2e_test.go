@@ -15,7 +15,11 @@ func Test(t *testing.T) {
 	require.Contains(
 		t,
 		string(out),
-		"main/main.neva:2:1: Subtype must be either union or literal: want int | float, got any\n",
+		"error: Subtype must be either union or literal: want int | float, got any\n"+
+			" --> main/main.neva:2:1\n"+
+			"  |\n"+
+			"2 |     Dec<any>\n"+
+			"  |     ^^^^^^^^\n",
 	)
 
 	require.Equal(t, 0, cmd.ProcessState.ExitCode())
@@ -0,0 +1,26 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Node with unused outports is only reported by linter, it doesn't prevent compilation.
+func Test(t *testing.T) {
+	cmd := exec.Command("neva", "lint", "--error-format", "short")
+
+	out, _ := cmd.CombinedOutput()
+	require.Equal(
+		t,
+		"main/main.neva:5:4: warning: All outports of node are unused: sub2 (unused-node)\n",
+		string(out),
+	)
+
+	require.Equal(t, 1, cmd.ProcessState.ExitCode())
+
+	out, err := exec.Command("neva", "check", "main").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Empty(t, string(out))
+}
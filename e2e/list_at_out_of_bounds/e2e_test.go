@@ -12,10 +12,9 @@ func Test(t *testing.T) {
 
 	out, err := cmd.CombinedOutput()
 	require.NoError(t, err)
-
 	require.Equal(
 		t,
-		"main/main.neva:5:4: All node's outports are unused: sub2\n",
+		"{\"text\": \"index out of bounds\"}\n",
 		string(out),
 	)
 
@@ -0,0 +1,39 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test(t *testing.T) {
+	cmd := exec.Command("neva", "run", "main")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err)
+	require.Equal(t, "olleh\n", string(out))
+	require.Equal(t, 0, cmd.ProcessState.ExitCode())
+}
+
+// Tests and benchmarks that depend on native code can't be executed in-process,
+// they are compiled to executables instead.
+func TestNevaTest(t *testing.T) {
+	cmd := exec.Command("neva", "test", "strs")
+
+	out, _ := cmd.CombinedOutput()
+	require.Contains(t, string(out), "--- PASS: TestReverse")
+	require.Contains(t, string(out), "--- FAIL: TestWrongReverse")
+	require.Contains(t, string(out), "strs/strs_test.neva:14:1: expected hello, got olleh")
+	require.Contains(t, string(out), "FAIL\tstrs\t")
+
+	require.Equal(t, 1, cmd.ProcessState.ExitCode())
+}
+
+func TestNevaBench(t *testing.T) {
+	cmd := exec.Command("neva", "bench", "--runs", "3", "strs")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Regexp(t, `BenchReverse +3 runs +p50 \S+ +p90 \S+ +p99 \S+`, string(out))
+	require.Contains(t, string(out), "ok  \tstrs\t")
+}
@@ -0,0 +1,45 @@
+package native
+
+import (
+	"context"
+
+	"github.com/nevalang/neva/pkg/runtime"
+)
+
+func Registry() runtime.Registry {
+	return runtime.Registry{
+		"reverse": reverse{},
+	}
+}
+
+type reverse struct{}
+
+func (reverse) Create(io runtime.IO, _ runtime.Msg) (func(context.Context), error) {
+	dataIn, err := io.In.Single("data")
+	if err != nil {
+		return nil, err
+	}
+
+	resOut, err := io.Out.Single("res")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) {
+		for {
+			dataMsg, ok := dataIn.Receive(ctx)
+			if !ok {
+				return
+			}
+
+			rr := []rune(dataMsg.Str())
+			for i, j := 0, len(rr)-1; i < j; i, j = i+1, j-1 {
+				rr[i], rr[j] = rr[j], rr[i]
+			}
+
+			if !resOut.Send(ctx, runtime.NewStringMsg(string(rr))) {
+				return
+			}
+		}
+	}, nil
+}
@@ -0,0 +1,21 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test(t *testing.T) {
+	cmd := exec.Command("neva", "run", "main")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err)
+	require.Contains(
+		t,
+		string(out),
+		"runtime function revers is declared neither by native code of the module nor by the standard library",
+	)
+	require.Contains(t, string(out), "main/main.neva:5:4")
+	require.Equal(t, 0, cmd.ProcessState.ExitCode())
+}
@@ -0,0 +1,10 @@
+package native
+
+import "github.com/nevalang/neva/pkg/runtime"
+
+const reverse = "reverse"
+
+// Registry is never called, because the program doesn't compile.
+func Registry() runtime.Registry {
+	return runtime.Registry{reverse: nil}
+}
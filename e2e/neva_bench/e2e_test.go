@@ -0,0 +1,56 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNevaBench(t *testing.T) {
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+
+	cmd := exec.Command("neva", "bench", "--run", "BenchSum", "--runs", "5", "--save", baseline, "bench")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Regexp(t, `BenchSum +5 runs +\d+ msgs/s +\d+ allocs/run +\d+ B/run +p50 \S+ +p90 \S+ +p99 \S+`, string(out))
+	require.NotContains(t, string(out), "BenchWrongSum")
+	require.Contains(t, string(out), "ok  \tbench\t")
+
+	data, err := os.ReadFile(baseline)
+	require.NoError(t, err)
+
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal(data, &results))
+	require.Len(t, results, 1)
+	require.Equal(t, "bench", results[0]["pkg"])
+	require.Equal(t, "BenchSum", results[0]["name"])
+	require.Greater(t, results[0]["msgsPerSec"], 0.0)
+
+	// pretend that the program used to be much faster
+	results[0]["msgsPerSec"] = results[0]["msgsPerSec"].(float64) * 1000
+	data, err = json.Marshal(results)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(baseline, data, 0644))
+
+	cmd = exec.Command("neva", "bench", "--run", "BenchSum", "--runs", "5", "--baseline", baseline, "bench")
+
+	out, _ = cmd.CombinedOutput()
+	require.Regexp(t, `bench/BenchSum .* REGRESSION`, string(out))
+	require.Equal(t, 1, cmd.ProcessState.ExitCode())
+}
+
+func TestNevaBenchFail(t *testing.T) {
+	cmd := exec.Command("neva", "bench", "--run", "BenchWrongSum", "--runs", "5", "bench")
+
+	out, _ := cmd.CombinedOutput()
+	require.Contains(t, string(out), "--- FAIL: BenchWrongSum")
+	require.Contains(t, string(out), "bench/bench_test.neva:14:1: expected 5051, got 5050")
+	require.Contains(t, string(out), "FAIL\tbench\t")
+
+	require.Equal(t, 1, cmd.ProcessState.ExitCode())
+}
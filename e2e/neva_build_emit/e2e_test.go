@@ -0,0 +1,46 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/internal/compiler/ir"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+)
+
+func TestNevaBuildEmit(t *testing.T) {
+	output := t.TempDir()
+
+	cmd := exec.Command(
+		"neva", "build",
+		"--target", "json",
+		"--emit", "parsed,desugared,ir",
+		"--output", output,
+		"main",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	for _, stage := range []string{"parsed", "desugared"} {
+		data, err := os.ReadFile(filepath.Join(output, stage+".json"))
+		require.NoError(t, err)
+
+		var build src.Build
+		require.NoError(t, json.Unmarshal(data, &build))
+		require.Contains(t, build.Modules[build.EntryModRef].Packages, "main")
+	}
+
+	require.NoFileExists(t, filepath.Join(output, "analyzed.json"))
+
+	data, err := os.ReadFile(filepath.Join(output, "ir.json"))
+	require.NoError(t, err)
+
+	var prog ir.Program
+	require.NoError(t, json.Unmarshal(data, &prog))
+	require.Equal(t, ir.PortAddr{Path: "out", Port: "stop"}, prog.Connections[ir.PortAddr{Path: "in", Port: "start"}])
+}
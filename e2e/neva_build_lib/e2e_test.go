@@ -0,0 +1,69 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestNevaBuildLib checks that generated library compiles and can be used from another go module.
+func TestNevaBuildLib(t *testing.T) {
+	lib := t.TempDir()
+
+	out, err := exec.Command(
+		"neva", "build",
+		"--target", "go",
+		"--lib", "Adder",
+		"--go-module", "example.com/adder",
+		"--output", lib,
+		"adder",
+	).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = lib
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	app := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(app, "go.mod"), []byte(`module example.com/app
+
+go 1.23
+
+require example.com/adder v0.0.0
+
+replace example.com/adder => `+lib+`
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(app, "main.go"), []byte(`package main
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/adder"
+)
+
+func main() {
+	ctx := context.Background()
+
+	c, err := adder.NewAdder(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+
+	c.SendData(ctx, 41)
+	v, _ := c.ReceiveData(ctx)
+	fmt.Println(v)
+}
+`), 0644))
+
+	cmd = exec.Command("go", "run", ".")
+	cmd.Dir = app
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(t, "42\n", string(out))
+}
@@ -0,0 +1,44 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestNevaCache checks that package is analyzed again when the package it imports changes.
+func TestNevaCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	modDir := t.TempDir()
+	require.NoError(t, os.CopyFS(modDir, os.DirFS(".")))
+
+	check := func() (string, error) {
+		cmd := exec.Command("neva", "check", "main")
+		cmd.Dir = modDir
+		cmd.Env = append(os.Environ(), "NEVACACHE="+cacheDir)
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+
+	for range 2 {
+		out, err := check()
+		require.NoError(t, err, out)
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	// main package itself is unchanged but it's no longer valid
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "greeting", "greeting.neva"), []byte(`pub def Greet(sig any) (res int) {
+    :sig -> 42 -> :res
+}
+`), 0644))
+
+	out, err := check()
+	require.Error(t, err)
+	require.Contains(t, out, "Incompatible types")
+}
@@ -0,0 +1,30 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNevaCheckValid(t *testing.T) {
+	cmd := exec.Command("neva", "check")
+	cmd.Dir = "valid"
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Empty(t, string(out))
+
+	require.Equal(t, 0, cmd.ProcessState.ExitCode())
+}
+
+// TestNevaCheckInvalid checks that packages are checked even if they are not imported by main package.
+func TestNevaCheckInvalid(t *testing.T) {
+	cmd := exec.Command("neva", "check", "main")
+	cmd.Dir = "invalid"
+
+	out, _ := cmd.CombinedOutput()
+	require.Contains(t, string(out), "lib/lib.neva")
+
+	require.Equal(t, 1, cmd.ProcessState.ExitCode())
+}
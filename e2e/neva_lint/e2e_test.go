@@ -0,0 +1,30 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNevaLint(t *testing.T) {
+	cmd := exec.Command("neva", "lint", "--error-format", "short")
+
+	out, _ := cmd.CombinedOutput()
+	require.Equal(
+		t,
+		"main/main.neva:1:14: warning: Unused import: strings (unused-import)\n",
+		string(out),
+	)
+
+	require.Equal(t, 1, cmd.ProcessState.ExitCode())
+}
+
+// TestNevaLintDoesNotPreventRun checks that warnings are not reported as errors by other commands.
+func TestNevaLintDoesNotPreventRun(t *testing.T) {
+	cmd := exec.Command("neva", "run", "main")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(t, "{}\n", string(out))
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNevaLock checks that dependency is pinned by neva.lock after the first build,
+// that build fails if the commit of the downloaded dependency no longer matches it
+// and that neva mod verify detects changed contents.
+func TestNevaLock(t *testing.T) {
+	home := t.TempDir()
+	modDir := t.TempDir()
+
+	// dependency is put into the deps directory, so it's never downloaded
+	depDir := filepath.Join(home, "neva", "deps", "example.com", "greeter_v1.0.0")
+	greetFile := filepath.Join(depDir, "greet", "greet.neva")
+	writeFile(t, filepath.Join(depDir, "neva.yml"), "neva: 0.30.1")
+	writeFile(t, greetFile, `pub def Greet(sig any) (res string) {
+	:sig -> 'hello' -> :res
+}
+`)
+
+	repo, err := git.PlainInit(depDir, false)
+	require.NoError(t, err)
+	commit(t, repo, true)
+
+	writeFile(t, filepath.Join(modDir, "neva.yml"), `neva: 0.30.1
+deps:
+  example.com/greeter:
+    path: example.com/greeter
+    version: v1.0.0
+`)
+	writeFile(t, filepath.Join(modDir, "main", "main.neva"), `import {
+	fmt
+	example.com/greeter:greet
+}
+
+def Main(start any) (stop any) {
+	greet.Greet
+	fmt.Println
+	---
+	:start -> greet -> println -> :stop
+}
+`)
+
+	neva := func(args ...string) string {
+		cmd := exec.Command("neva", args...)
+		cmd.Dir = modDir
+		cmd.Env = append(os.Environ(), "HOME="+home)
+		out, _ := cmd.CombinedOutput()
+		return string(out)
+	}
+	run := func() string { return neva("run", "main") }
+
+	require.Equal(t, "hello\n", run())
+
+	lock, err := os.ReadFile(filepath.Join(modDir, "neva.lock"))
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	require.Contains(t, string(lock), "path: example.com/greeter")
+	require.Contains(t, string(lock), "version: v1.0.0")
+	require.Contains(t, string(lock), "commit: "+head.Hash().String())
+	require.Contains(t, string(lock), "hash: sha256:")
+
+	// lock file is only written when dependencies change
+	require.Equal(t, "hello\n", run())
+	again, err := os.ReadFile(filepath.Join(modDir, "neva.lock"))
+	require.NoError(t, err)
+	require.Equal(t, string(lock), string(again))
+	require.Equal(t, "all modules verified\n", neva("mod", "verify"))
+
+	// contents of the module changed, builds don't hash modules that were downloaded before
+	original, err := os.ReadFile(greetFile)
+	require.NoError(t, err)
+	writeFile(t, greetFile, `pub def Greet(sig any) (res string) {
+	:sig -> 'pwned' -> :res
+}
+`)
+	require.Equal(t, "pwned\n", run())
+	require.Contains(t, neva("mod", "verify"), "checksum mismatch for example.com/greeter@v1.0.0: hash")
+
+	// contents are the same but the tag points to another commit now
+	writeFile(t, greetFile, string(original))
+	commit(t, repo, false)
+	out := run()
+	require.Contains(t, out, "checksum mismatch for example.com/greeter@v1.0.0: commit "+head.Hash().String())
+
+	// removing the entry from the lock file trusts the module again
+	require.NoError(t, os.Remove(filepath.Join(modDir, "neva.lock")))
+	require.Equal(t, "hello\n", run())
+}
+
+func commit(t *testing.T, repo *git.Repository, addAll bool) {
+	tree, err := repo.Worktree()
+	require.NoError(t, err)
+	if addAll {
+		_, err = tree.Add(".")
+		require.NoError(t, err)
+	}
+	_, err = tree.Commit("v1.0.0", &git.CommitOptions{
+		Author:            &object.Signature{Name: "Ann", When: time.Now()},
+		AllowEmptyCommits: true,
+	})
+	require.NoError(t, err)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestNevaMod checks that the maximum required version of each dependency is selected
+// and requirement graph can be inspected.
+func TestNevaMod(t *testing.T) {
+	home := t.TempDir()
+	modDir := t.TempDir()
+
+	// modules are put into the deps directory, so they're never downloaded
+	writeDep(t, home, "example.com/a", "v1.0.0", map[string]string{"example.com/c": "v1.1.0"})
+	writeDep(t, home, "example.com/b", "v1.0.0", map[string]string{"example.com/c": "v1.2.0"})
+	writeDep(t, home, "example.com/c", "v1.0.0", nil)
+	writeDep(t, home, "example.com/c", "v1.1.0", nil)
+	writeDep(t, home, "example.com/c", "v1.2.0", nil)
+	writeDep(t, home, "example.com/c", "v2.0.0", nil)
+	writeDep(t, home, "example.com/d", "v1.0.0", map[string]string{"example.com/c": "v2.0.0"})
+
+	writeManifest(t, modDir, map[string]string{
+		"example.com/a": "v1.0.0",
+		"example.com/b": "v1.0.0",
+		"example.com/c": "v1.0.0",
+	})
+	writeFile(t, filepath.Join(modDir, "main", "main.neva"), `import {
+	fmt
+	example.com/c:greet
+}
+
+def Main(start any) (stop any) {
+	greet.Greet
+	fmt.Println
+	---
+	:start -> greet -> println -> :stop
+}
+`)
+
+	neva := func(args ...string) string {
+		cmd := exec.Command("neva", args...)
+		cmd.Dir = modDir
+		cmd.Env = append(os.Environ(), "HOME="+home)
+		out, _ := cmd.CombinedOutput()
+		return string(out)
+	}
+
+	require.Equal(t, "example.com/c@v1.2.0\n", neva("run", "main"))
+
+	require.Equal(t, `@ example.com/a@v1.0.0
+@ example.com/b@v1.0.0
+@ example.com/c@v1.0.0
+example.com/a@v1.0.0 example.com/c@v1.1.0
+example.com/b@v1.0.0 example.com/c@v1.2.0
+`, neva("mod", "graph"))
+
+	require.Equal(t, `# example.com/c
+@
+example.com/c@v1.0.0
+
+# example.com/d
+(module is not used by current module)
+`, neva("mod", "why", "example.com/c", "example.com/d"))
+
+	// direct requirement is updated but other deps still require newer version
+	out := neva("get", "example.com/c", "v1.1.0")
+	require.Contains(t, out, filepath.Join("example.com", "c_v1.2.0"))
+	manifest, err := os.ReadFile(filepath.Join(modDir, "neva.yml"))
+	require.NoError(t, err)
+	require.Contains(t, string(manifest), "path: example.com/c\n        version: v1.1.0")
+
+	// new dependency requires another major version
+	out = neva("get", "example.com/d", "v1.0.0")
+	require.Contains(t, out, `conflicting major versions of example.com/c, selected v2.0.0:
+	@ requires example.com/c@v1.1.0
+	example.com/a@v1.0.0 requires example.com/c@v1.1.0
+	example.com/b@v1.0.0 requires example.com/c@v1.2.0
+	example.com/d@v1.0.0 requires example.com/c@v2.0.0`)
+	after, err := os.ReadFile(filepath.Join(modDir, "neva.yml"))
+	require.NoError(t, err)
+	require.Equal(t, string(manifest), string(after))
+
+	require.Equal(t, "example.com/c@v1.2.0\n", neva("run", "main"))
+}
+
+// writeDep writes module with greet package that returns module's reference.
+func writeDep(t *testing.T, home, path, version string, deps map[string]string) {
+	dir := filepath.Join(home, "neva", "deps", path+"_"+version)
+	writeManifest(t, dir, deps)
+	writeFile(t, filepath.Join(dir, "greet", "greet.neva"), fmt.Sprintf(`pub def Greet(sig any) (res string) {
+	:sig -> '%s@%s' -> :res
+}
+`, path, version))
+}
+
+func writeManifest(t *testing.T, dir string, deps map[string]string) {
+	var sb strings.Builder
+	sb.WriteString("neva: 0.30.1\n")
+	if len(deps) > 0 {
+		sb.WriteString("deps:\n")
+	}
+	for path, version := range deps {
+		fmt.Fprintf(&sb, "  %s:\n    path: %s\n    version: %s\n", path, path, version)
+	}
+	writeFile(t, filepath.Join(dir, "neva.yml"), sb.String())
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNevaRepl(t *testing.T) {
+	cmd := exec.Command("neva", "repl")
+	cmd.Stdin = strings.NewReader(`add Add<int>
+neg Neg<int>
+add -> neg
+1 -> add:left; 2 -> add:right
+-5 -> neg
+foo Bar
+:undo
+10 -> add:left
+:quit
+`)
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(
+		t,
+		`add:res = 3
+neg:res = -3
+error: port 'neg:data' is used twice
+error: foo: entity not found: Bar
+error: Unused node inport: add:right
+`,
+		string(out),
+	)
+}
+
+// TestNevaReplKeywordPrefix checks that nodes with names that start with a keyword aren't taken for imports.
+func TestNevaReplKeywordPrefix(t *testing.T) {
+	cmd := exec.Command("neva", "repl")
+	cmd.Stdin = strings.NewReader(`importer Add<int>
+1 -> importer:left; 2 -> importer:right
+:quit
+`)
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(t, "importer:res = 3\n", string(out))
+}
+
+// TestNevaReplNative checks that programs that depend on native code,
+// which can't be executed in-process, are compiled and executed as binaries.
+func TestNevaReplNative(t *testing.T) {
+	home := t.TempDir()
+	modDir := t.TempDir()
+
+	// module with native code of the native_extern test is put into the deps directory, so it's never downloaded
+	depDir := filepath.Join(home, "neva", "deps", "example.com", "native_extern_v1.0.0")
+	require.NoError(t, os.CopyFS(depDir, os.DirFS("../native_extern")))
+
+	repo, err := git.PlainInit(depDir, false)
+	require.NoError(t, err)
+	tree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = tree.Add(".")
+	require.NoError(t, err)
+	_, err = tree.Commit("v1.0.0", &git.CommitOptions{
+		Author: &object.Signature{Name: "Ann", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "neva.yml"), []byte(`neva: 0.30.1
+deps:
+  example.com/native_extern:
+    path: example.com/native_extern
+    version: v1.0.0
+`), 0644))
+
+	cmd := exec.Command("neva", "repl")
+	cmd.Dir = modDir
+	cmd.Env = append(os.Environ(), "HOME="+home)
+	cmd.Stdin = strings.NewReader(`import { example.com/native_extern:strs }
+rev strs.Reverse
+'hello' -> rev
+:quit
+`)
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Equal(t, "rev:res = \"olleh\"\n", string(out))
+}
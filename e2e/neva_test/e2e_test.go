@@ -0,0 +1,34 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNevaTestPass(t *testing.T) {
+	cmd := exec.Command("neva", "test", "--run", "TestDouble|TestParseError", "math")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Contains(t, string(out), "--- PASS: TestDouble")
+	require.Contains(t, string(out), "--- PASS: TestParseError")
+	require.NotContains(t, string(out), "TestWrong")
+	require.Contains(t, string(out), "ok  \tmath\t")
+
+	require.Equal(t, 0, cmd.ProcessState.ExitCode())
+}
+
+func TestNevaTestFail(t *testing.T) {
+	cmd := exec.Command("neva", "test", "--run", "TestWrong", "math")
+
+	out, _ := cmd.CombinedOutput()
+	require.Contains(t, string(out), "--- FAIL: TestWrongDouble")
+	require.Contains(t, string(out), "math/math_test.neva:24:1: expected 43, got 42")
+	require.Contains(t, string(out), "--- FAIL: TestWrongParse")
+	require.Contains(t, string(out), "math/math_test.neva:33:1: expected error, got 42")
+	require.Contains(t, string(out), "FAIL\tmath\t")
+
+	require.Equal(t, 1, cmd.ProcessState.ExitCode())
+}
@@ -0,0 +1,128 @@
+package test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// new "release" of the compiler is a script so we can tell whether it was installed
+var newBinary = []byte("#!/bin/sh\necho upgraded\n")
+
+var binaryName = fmt.Sprintf("neva-%s-%s", runtime.GOOS, runtime.GOARCH)
+
+func TestUpgradeFromArchive(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("release binary is a shell script")
+	}
+
+	for _, archive := range []func(t *testing.T, dir string, files map[string][]byte) string{writeTarGz, writeZip} {
+		neva := copyNeva(t)
+		release := archive(t, t.TempDir(), releaseFiles(newBinary))
+
+		out, err := exec.Command(neva, "upgrade", "--from", release).CombinedOutput()
+		require.NoError(t, err, string(out))
+		require.Contains(t, string(out), "Installed Nevalang from "+release)
+
+		out, err = exec.Command(neva).CombinedOutput()
+		require.NoError(t, err, string(out))
+		require.Equal(t, "upgraded\n", string(out))
+	}
+}
+
+func TestUpgradeChecksumMismatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("release binary is a shell script")
+	}
+
+	neva := copyNeva(t)
+
+	files := releaseFiles(newBinary)
+	files[binaryName] = []byte("#!/bin/sh\necho tampered\n")
+
+	dir := t.TempDir()
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0644))
+	}
+
+	out, _ := exec.Command(neva, "upgrade", "--from", dir).CombinedOutput()
+	require.Contains(t, string(out), binaryName+": checksum mismatch")
+
+	// compiler stays the same
+	out, err := exec.Command(neva, "version").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NotContains(t, string(out), "tampered")
+}
+
+func copyNeva(t *testing.T) string {
+	path, err := exec.LookPath("neva")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	neva := filepath.Join(t.TempDir(), "neva")
+	require.NoError(t, os.WriteFile(neva, data, 0755))
+
+	return neva
+}
+
+func releaseFiles(bin []byte) map[string][]byte {
+	return map[string][]byte{
+		binaryName:      bin,
+		"checksums.txt": []byte(fmt.Sprintf("%x  %s\n", sha256.Sum256(bin), binaryName)),
+	}
+}
+
+func writeTarGz(t *testing.T, dir string, files map[string][]byte) string {
+	path := filepath.Join(dir, "release.tar.gz")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     "release/" + name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return path
+}
+
+func writeZip(t *testing.T, dir string, files map[string][]byte) string {
+	path := filepath.Join(dir, "release.zip")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	return path
+}
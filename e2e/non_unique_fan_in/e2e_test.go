@@ -18,7 +18,11 @@ func Test(t *testing.T) {
 	require.Contains(
 		t,
 		string(out),
-		"main/main.neva:2:19: port 'out:stop' is used twice\n",
+		"error: port 'out:stop' is used twice\n"+
+			" --> main/main.neva:2:19\n"+
+			"  |\n"+
+			"2 |     :start -> [:stop, :stop]\n"+
+			"  |                       ^^^^^\n",
 	)
 
 	require.Equal(t, 0, cmd.ProcessState.ExitCode())
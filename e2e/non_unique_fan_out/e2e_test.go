@@ -18,7 +18,11 @@ func Test(t *testing.T) {
 	require.Contains(
 		t,
 		string(out),
-		"main/main.neva:2:10: port 'in:start' is used twice\n",
+		"error: port 'in:start' is used twice\n"+
+			" --> main/main.neva:2:10\n"+
+			"  |\n"+
+			"2 |     [:start, :start] -> :stop\n"+
+			"  |              ^^^^^^\n",
 	)
 
 	require.Equal(t, 0, cmd.ProcessState.ExitCode())
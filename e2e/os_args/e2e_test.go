@@ -0,0 +1,30 @@
+package test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test(t *testing.T) {
+	output := t.TempDir()
+
+	cmd := exec.Command("neva", "build", "--output", output, "main")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	bin := filepath.Join(output, "output")
+	cmd = exec.Command(bin, "foo", "bar")
+
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		bin+"\nfoo\nbar\n",
+		string(out),
+	)
+
+	require.Equal(t, 0, cmd.ProcessState.ExitCode())
+}
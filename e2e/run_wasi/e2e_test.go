@@ -0,0 +1,21 @@
+package test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test(t *testing.T) {
+	cmd := exec.Command("neva", "run", "--target", "wasi", "main")
+	cmd.Stdin = strings.NewReader("hello\n")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err)
+
+	require.Equal(t, "hello\n", string(out))
+
+	require.Equal(t, 0, cmd.ProcessState.ExitCode())
+}
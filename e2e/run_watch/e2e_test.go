@@ -0,0 +1,176 @@
+package test
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestWatchNative checks that editing native code of the module restarts the program.
+func TestWatchNative(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "neva.yml", "neva: 0.30.1")
+	writeFile(t, dir, "main/main.neva", `import { fmt }
+
+#extern(greet)
+def Greet(data string) (res string)
+
+def Main(start any) (stop any) {
+	greet Greet, println fmt.Println
+	---
+	:start -> { 'world' -> greet -> println -> :stop }
+}`)
+	writeFile(t, dir, "native/greet.go", nativeGreet("hello"))
+
+	w := startWatch(t, dir)
+	w.waitFor(t, "hello world")
+	w.waitFor(t, "Waiting for changes")
+
+	writeFile(t, dir, "native/greet.go", nativeGreet("goodbye"))
+	w.waitFor(t, "Changes detected, restarting")
+	w.waitFor(t, "goodbye world")
+}
+
+// TestWatchWASI checks that long-running wasi program is stopped on restart and on interrupt.
+func TestWatchWASI(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "neva.yml", "neva: 0.30.1")
+	writeFile(t, dir, "main/main.neva", sleeper("hello"))
+
+	w := startWatch(t, dir, "--target", "wasi")
+	w.waitFor(t, "hello")
+
+	writeFile(t, dir, "main/main.neva", sleeper("goodbye"))
+	w.waitFor(t, "Changes detected, restarting")
+	w.waitFor(t, "goodbye")
+}
+
+// sleeper returns program that prints greeting and then sleeps for an hour.
+func sleeper(greeting string) string {
+	return `import { time, fmt }
+
+def Main(start any) (stop any) {
+	fmt.Println<string>, time.Delay<string>
+	---
+	:start -> { '` + greeting + `' -> println }
+	println -> delay:data
+	$time.hour -> delay:dur
+	delay -> :stop
+}`
+}
+
+func nativeGreet(greeting string) string {
+	return `package native
+
+import (
+	"context"
+
+	"github.com/nevalang/neva/pkg/runtime"
+)
+
+func Registry() runtime.Registry {
+	return runtime.Registry{"greet": greet{}}
+}
+
+type greet struct{}
+
+func (greet) Create(io runtime.IO, _ runtime.Msg) (func(context.Context), error) {
+	dataIn, err := io.In.Single("data")
+	if err != nil {
+		return nil, err
+	}
+	resOut, err := io.Out.Single("res")
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) {
+		for {
+			msg, ok := dataIn.Receive(ctx)
+			if !ok {
+				return
+			}
+			if !resOut.Send(ctx, runtime.NewStringMsg("` + greeting + ` "+msg.Str())) {
+				return
+			}
+		}
+	}, nil
+}
+`
+}
+
+type watcher struct {
+	lines chan string
+}
+
+// startWatch runs the main package of the module in watch mode and stops it at the end of the test.
+func startWatch(t *testing.T, dir string, args ...string) watcher {
+	t.Helper()
+
+	cmd := exec.Command("neva", append(append([]string{"run", "--watch"}, args...), "main")...)
+	cmd.Dir = dir
+
+	r, w := io.Pipe()
+	cmd.Stdout = w
+	cmd.Stderr = w
+	require.NoError(t, cmd.Start())
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		w.Close()
+		close(exited)
+	}()
+
+	lines := make(chan string, 100)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	t.Cleanup(func() {
+		require.NoError(t, cmd.Process.Signal(os.Interrupt))
+		select {
+		case <-exited:
+		case <-time.After(10 * time.Second):
+			_ = cmd.Process.Kill()
+			t.Error("watch mode didn't stop on interrupt")
+		}
+	})
+
+	return watcher{lines: lines}
+}
+
+// waitFor reads output until a line containing s is printed.
+func (w watcher) waitFor(t *testing.T, s string) {
+	t.Helper()
+
+	timeout := time.After(time.Minute)
+	for {
+		select {
+		case line, ok := <-w.lines:
+			require.True(t, ok, "neva exited before printing %q", s)
+			if strings.Contains(line, s) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for %q", s)
+		}
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
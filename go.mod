@@ -8,6 +8,7 @@ require (
 	github.com/go-git/go-git/v5 v5.11.0
 	github.com/golang/mock v1.6.0
 	github.com/stretchr/testify v1.8.4
+	github.com/tetratelabs/wazero v1.9.0
 	github.com/tliron/commonlog v0.2.10
 	github.com/tliron/glsp v0.2.0
 	golang.org/x/exp v0.0.0-20230515195305-f3d0a9c9a5cc
@@ -1,8 +1,11 @@
 package builder
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 
@@ -17,6 +20,14 @@ type Builder struct {
 	manifestParser ManifestParser
 	thirdPartyPath string
 	stdLibPath     string
+	includeTests   bool
+}
+
+// WithTests returns builder that also loads test files of the entry module.
+// Test files are never loaded for dependencies.
+func (b Builder) WithTests() Builder {
+	b.includeTests = true
+	return b
 }
 
 type ManifestParser interface {
@@ -28,7 +39,7 @@ func (b Builder) Build(
 	wd string,
 ) (compiler.RawBuild, string, *compiler.Error) {
 	// load entry module from disk
-	entryMod, entryModRootPath, err := b.LoadModuleByPath(ctx, wd)
+	entryMod, entryModRootPath, err := b.loadModule(ctx, wd, b.includeTests)
 	if err != nil {
 		return compiler.RawBuild{}, "", &compiler.Error{
 			Message: "build entry mod: " + err.Error(),
@@ -66,28 +77,25 @@ func (b Builder) Build(
 	}
 	defer release()
 
-	q := newQueue(entryMod.Manifest.Deps)
-
-	for !q.empty() {
-		depModRef := q.dequeue()
-
-		if _, ok := mods[depModRef]; ok {
-			continue
+	res, err := b.resolve(ctx, entryMod.Manifest.Deps)
+	if err != nil {
+		return compiler.RawBuild{}, "", &compiler.Error{
+			Message: "resolve deps: " + err.Error(),
 		}
+	}
 
-		depWD, _, err := b.downloadDep(depModRef)
-		if err != nil {
-			return compiler.RawBuild{}, "", &compiler.Error{
-				Message: "download dep: " + err.Error(),
-			}
+	if err := lockDeps(entryModRootPath, res); err != nil {
+		return compiler.RawBuild{}, "", &compiler.Error{
+			Message: "verify deps: " + err.Error(),
 		}
+	}
 
-		depMod, _, err := b.LoadModuleByPath(ctx, depWD)
-		if err != nil {
-			return compiler.RawBuild{}, "", &compiler.Error{
-				Message: "build dep mod: " + err.Error(),
-			}
-		}
+	// every module refers to the selected versions of its deps
+	useSelectedVersions(entryMod.Manifest.Deps, res.graph.Selected)
+
+	for _, depModRef := range res.graph.Selected {
+		depMod := res.mods[depModRef]
+		useSelectedVersions(depMod.Manifest.Deps, res.graph.Selected)
 
 		// inject stdlib dep into every downloaded dep mod
 		depMod.Manifest.Deps["std"] = core.ModuleRef{
@@ -96,8 +104,6 @@ func (b Builder) Build(
 		}
 
 		mods[depModRef] = depMod
-
-		q.enqueue(depMod.Manifest.Deps)
 	}
 
 	return compiler.RawBuild{
@@ -106,6 +112,69 @@ func (b Builder) Build(
 	}, entryModRootPath, nil
 }
 
+// Graph downloads dependencies of the module that the wd belongs to and returns their requirement graph.
+func (b Builder) Graph(ctx context.Context, wd string) (Graph, error) {
+	manifest, _, err := b.getNearestManifest(wd)
+	if err != nil {
+		return Graph{}, fmt.Errorf("retrieve manifest: %w", err)
+	}
+
+	release, err := acquireLockFile()
+	if err != nil {
+		return Graph{}, fmt.Errorf("failed to acquire lock file: %w", err)
+	}
+	defer release()
+
+	res, err := b.resolve(ctx, manifest.Deps)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	return res.graph, nil
+}
+
+func useSelectedVersions(deps map[string]core.ModuleRef, selected map[string]core.ModuleRef) {
+	for alias, dep := range deps {
+		if ref, ok := selected[dep.Path]; ok {
+			deps[alias] = ref
+		}
+	}
+}
+
+// SourcePath returns path to the source code file of the given location on disk.
+// Entry module is looked up the same way as in Build, starting from the wd.
+// Location must refer to a file.
+func (b Builder) SourcePath(wd string, location core.Location) (string, error) {
+	var modRoot string
+
+	switch location.ModRef.Path {
+	case "@":
+		_, path, err := lookupManifestFile(wd, 0)
+		if err != nil {
+			return "", err
+		}
+		modRoot = path
+	case "std":
+		modRoot = b.stdLibPath
+	default:
+		modRoot = fmt.Sprintf("%s/%s_%s", b.thirdPartyPath, location.ModRef.Path, location.ModRef.Version)
+	}
+
+	return filepath.Join(modRoot, location.Package, location.Filename+".neva"), nil
+}
+
+// ModuleRoot returns path to the root of the module that the wd belongs to.
+// Module is looked up the same way as in Build.
+func (b Builder) ModuleRoot(wd string) (string, error) {
+	_, path, err := lookupManifestFile(wd, 0)
+	return path, err
+}
+
+// StdlibPath returns path to the stdlib module on disk.
+func (b Builder) StdlibPath() string {
+	return b.stdLibPath
+}
+
 func getThirdPartyPath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -122,8 +191,9 @@ func getThirdPartyPath() (string, error) {
 	return path, nil
 }
 
-// rewriteStdlibOntoDisk erases stdlib on the disk if it's there and writes it again.
-func rewriteStdlibOntoDisk() (string, error) {
+// writeStdlibOntoDisk makes sure stdlib on the disk is the same as the one embedded into the compiler.
+// It's only rewritten if it differs, e.g. after upgrade or if it was edited by hand.
+func writeStdlibOntoDisk() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
@@ -131,53 +201,68 @@ func rewriteStdlibOntoDisk() (string, error) {
 
 	path := filepath.Join(home, "neva", "std")
 
-	// TODO replace this dirty hack with good solution of https://github.com/nevalang/neva/issues/563
-	err = os.RemoveAll(path)
+	embedded, err := readStdlib(std.FS)
 	if err != nil {
 		return "", err
 	}
 
-	// _, err = os.Stat(path)
-	// if err == nil {
-	// 	return path, nil
-	// }
+	if onDisk, err := readStdlib(os.DirFS(path)); err == nil && maps.EqualFunc(embedded, onDisk, bytes.Equal) {
+		return path, nil
+	}
 
-	// if !os.IsNotExist(err) {
-	// 	return "", err
-	// }
+	// stdlib is written to temporary directory first so concurrent builds never see it half-written
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return "", err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(path), "std.tmp*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
 
-	// Inject missing stdlib files into user's home directory
-	stdFS := std.FS
-	err = fs.WalkDir(stdFS, ".", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+	for name, data := range embedded {
+		targetPath := filepath.Join(tmp, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(targetPath), os.ModePerm); err != nil {
+			return "", err
 		}
-		if d.IsDir() {
-			return nil
+		if err := os.WriteFile(targetPath, data, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		// another build could write it in the meantime
+		if onDisk, readErr := readStdlib(os.DirFS(path)); readErr == nil && maps.EqualFunc(embedded, onDisk, bytes.Equal) {
+			return path, nil
 		}
-		data, err := fs.ReadFile(stdFS, path)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// readStdlib returns contents of every file of the stdlib by path.
+func readStdlib(fsys fs.FS) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		targetPath := filepath.Join(home, "neva", "std", path)
-		dir := filepath.Dir(targetPath)
-		if _, err := os.Stat(dir); os.IsNotExist(err) {
-			err = os.MkdirAll(dir, os.ModePerm)
-			if err != nil {
-				return err
-			}
+		if d.IsDir() {
+			return nil
 		}
-		err = os.WriteFile(targetPath, data, 0644)
+		data, err := fs.ReadFile(fsys, path)
 		if err != nil {
 			return err
 		}
+		files[path] = data
 		return nil
 	})
-	if err != nil {
-		return "", err
-	}
-
-	return path, nil
+	return files, err
 }
 
 func New(parser ManifestParser) (Builder, error) {
@@ -186,7 +271,7 @@ func New(parser ManifestParser) (Builder, error) {
 		return Builder{}, err
 	}
 
-	stdlibPath, err := rewriteStdlibOntoDisk()
+	stdlibPath, err := writeStdlibOntoDisk()
 	if err != nil {
 		return Builder{}, err
 	}
@@ -1,13 +1,18 @@
 package builder
 
 import (
-	"errors"
+	"context"
 	"fmt"
 
 	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
 )
 
-func (b Builder) Get(wd, path, version string) (string, error) {
+// Get downloads the dependency and adds it to the manifest of the module that the wd belongs to,
+// replacing the version that was required before. Requirements are resolved again,
+// so it fails without touching the manifest if the new version conflicts with other dependencies.
+// It returns path where the selected version of the dependency is downloaded to,
+// which is newer than the requested one if other dependencies require so.
+func (b Builder) Get(ctx context.Context, wd, path, version string) (string, error) {
 	ref := core.ModuleRef{
 		Path:    path,
 		Version: version,
@@ -19,31 +24,36 @@ func (b Builder) Get(wd, path, version string) (string, error) {
 	}
 	defer release()
 
-	downloadPath, actualVersion, err := b.downloadDep(ref)
+	_, actualVersion, fresh, err := b.downloadDep(ref)
 	if err != nil {
 		return "", err
 	}
 
-	manifest, _, err := b.getNearestManifest(wd)
+	manifest, modRootPath, err := b.getNearestManifest(wd)
 	if err != nil {
 		return "", fmt.Errorf("Retrieve manifest: %w", err)
 	}
 
-	existing, ok := manifest.Deps[path]
-	if ok && existing.Version != actualVersion {
-		return "", errors.New(
-			"Several versions of the same dependency not yet supported.",
-		)
-	}
-
 	manifest.Deps[path] = core.ModuleRef{
 		Path:    path,
 		Version: actualVersion,
 	}
 
+	res, err := b.resolve(ctx, manifest.Deps)
+	if err != nil {
+		return "", fmt.Errorf("resolve deps: %w", err)
+	}
+	if fresh { // it was downloaded before the resolution
+		res.fresh[manifest.Deps[path]] = true
+	}
+
+	if err := lockDeps(modRootPath, res); err != nil {
+		return "", err
+	}
+
 	if err := b.writeManifest(manifest, wd); err != nil {
 		return "", err
 	}
 
-	return downloadPath, nil
+	return res.dirs[res.graph.Selected[path]], nil
 }
@@ -10,9 +10,10 @@ import (
 	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
 )
 
-// downloadDep returns path where it downloaded dependency
-// and its downloaded version in case version wasn't specified.
-func (p Builder) downloadDep(depModRef core.ModuleRef) (string, string, error) {
+// downloadDep returns path where it downloaded dependency,
+// its downloaded version in case version wasn't specified
+// and whether it was downloaded now rather than found on disk.
+func (p Builder) downloadDep(depModRef core.ModuleRef) (string, string, bool, error) {
 	fsPath := fmt.Sprintf(
 		"%s/%s_%s",
 		p.thirdPartyPath,
@@ -22,11 +23,11 @@ func (p Builder) downloadDep(depModRef core.ModuleRef) (string, string, error) {
 
 	_, err := os.Stat(fsPath)
 	if err == nil {
-		return fsPath, depModRef.Version, nil
+		return fsPath, depModRef.Version, false, nil
 	}
 
 	if !os.IsNotExist(err) {
-		return "", "", fmt.Errorf("os stat: %w", err)
+		return "", "", false, fmt.Errorf("os stat: %w", err)
 	}
 
 	var ref plumbing.ReferenceName
@@ -39,27 +40,27 @@ func (p Builder) downloadDep(depModRef core.ModuleRef) (string, string, error) {
 		ReferenceName: ref,
 	})
 	if err != nil {
-		return "", "", err
+		return "", "", false, err
 	}
 
 	if ref != "" {
-		return fsPath, "", nil
+		return fsPath, depModRef.Version, true, nil
 	}
 
 	latestTagHash, tagName, err := getLatestTagHash(repo)
 	if err != nil {
-		return "", "", err
+		return "", "", false, err
 	}
 
 	tree, err := repo.Worktree()
 	if err != nil {
-		return "", "", err
+		return "", "", false, err
 	}
 
 	if err := tree.Checkout(&git.CheckoutOptions{
 		Hash: latestTagHash,
 	}); err != nil {
-		return "", "", err
+		return "", "", false, err
 	}
 
 	// Append the latest tag to the directory name
@@ -68,16 +69,16 @@ func (p Builder) downloadDep(depModRef core.ModuleRef) (string, string, error) {
 	// Remove the directory if it already exists
 	if _, err := os.Stat(newFsPath); err == nil {
 		if err := os.RemoveAll(newFsPath); err != nil {
-			return "", "", fmt.Errorf("os.RemoveAll: %w", err)
+			return "", "", false, fmt.Errorf("os.RemoveAll: %w", err)
 		}
 	}
 
 	// Finally rename new directory
 	if err := os.Rename(fsPath, newFsPath); err != nil {
-		return "", "", fmt.Errorf("os rename: %w", err)
+		return "", "", false, fmt.Errorf("os rename: %w", err)
 	}
 
-	return newFsPath, tagName, nil
+	return newFsPath, tagName, true, nil
 }
 
 func getLatestTagHash(repository *git.Repository) (plumbing.Hash, string, error) {
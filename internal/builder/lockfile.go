@@ -0,0 +1,300 @@
+package builder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+// lockFileName is a file in the root of the entry module
+// that pins every dependency module to the commit and content it was first built with.
+const lockFileName = "neva.lock"
+
+// lockFile is the contents of the neva.lock file.
+type lockFile struct {
+	Deps []lockedModule `yaml:"deps"`
+}
+
+// lockedModule is a dependency module as it was resolved when it was added to the lock file.
+type lockedModule struct {
+	Path    string `yaml:"path"`
+	Version string `yaml:"version"`
+	// Commit is empty if the module is not a git repository.
+	Commit string `yaml:"commit,omitempty"`
+	// Hash is sha256 of the module's files, see hashModule.
+	Hash string `yaml:"hash"`
+}
+
+func (l lockFile) find(ref core.ModuleRef) (lockedModule, bool) {
+	for _, dep := range l.Deps {
+		if dep.Path == ref.Path && dep.Version == ref.Version {
+			return dep, true
+		}
+	}
+	return lockedModule{}, false
+}
+
+func readLockFile(modRootPath string) (lockFile, error) {
+	data, err := os.ReadFile(filepath.Join(modRootPath, lockFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return lockFile{}, nil
+	}
+	if err != nil {
+		return lockFile{}, err
+	}
+
+	var lock lockFile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return lockFile{}, fmt.Errorf("parse %s: %w", lockFileName, err)
+	}
+
+	return lock, nil
+}
+
+// writeLockFile writes dependencies sorted by path and version, so the file only changes when they do.
+func writeLockFile(modRootPath string, lock lockFile) error {
+	slices.SortFunc(lock.Deps, func(a, b lockedModule) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Version, b.Version)
+	})
+
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", lockFileName, err)
+	}
+
+	return os.WriteFile(filepath.Join(modRootPath, lockFileName), data, 0644)
+}
+
+// lockModule returns current commit and hash of the downloaded dependency module.
+func lockModule(ref core.ModuleRef, depWD string) (lockedModule, error) {
+	commit, err := headCommit(depWD)
+	if err != nil {
+		return lockedModule{}, fmt.Errorf("%s@%s: %w", ref.Path, ref.Version, err)
+	}
+
+	hash, err := hashModule(depWD)
+	if err != nil {
+		return lockedModule{}, fmt.Errorf("%s@%s: %w", ref.Path, ref.Version, err)
+	}
+
+	return lockedModule{
+		Path:    ref.Path,
+		Version: ref.Version,
+		Commit:  commit,
+		Hash:    hash,
+	}, nil
+}
+
+// verify returns error describing the difference if actual module is not the same as the locked one.
+func (l lockedModule) verify(actual lockedModule, depWD string) error {
+	var mismatch string
+	switch {
+	case l.Commit != actual.Commit:
+		mismatch = fmt.Sprintf("commit %s in %s, got %s", l.Commit, lockFileName, actual.Commit)
+	case l.Hash != actual.Hash:
+		mismatch = fmt.Sprintf("hash %s in %s, got %s", l.Hash, lockFileName, actual.Hash)
+	default:
+		return nil
+	}
+
+	return fmt.Errorf(
+		"checksum mismatch for %s@%s: %s (module at %s was modified or its tag was moved; "+
+			"remove it to download again or remove its entry from %s to trust the new contents)",
+		l.Path,
+		l.Version,
+		mismatch,
+		depWD,
+		lockFileName,
+	)
+}
+
+// headCommit returns hash of the commit checked out in the dir, or empty string if it's not a git repository.
+func headCommit(dir string) (string, error) {
+	repo, err := git.PlainOpen(dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return head.Hash().String(), nil
+}
+
+// hashModule returns sha256 over paths and contents of every file in the dir except git metadata.
+func hashModule(dir string) (string, error) {
+	fsys := os.DirFS(dir)
+
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return "", err
+		}
+		fileHash := sha256.Sum256(data)
+		fmt.Fprintf(h, "%x  %s\n", fileHash, path)
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// add adds the downloaded module to the updated lock file. If the module is already locked,
+// it's only hashed and verified if it was just downloaded or its commit differs from the locked one,
+// like go.sum is only checked on download. Use Builder.Verify to check contents of all dependencies.
+func (l lockFile) add(updated *lockFile, ref core.ModuleRef, depWD string, fresh bool) error {
+	updated.Deps = slices.DeleteFunc(updated.Deps, func(dep lockedModule) bool {
+		return dep.Path == ref.Path && dep.Version == ref.Version
+	})
+
+	locked, ok := l.find(ref)
+	if ok && !fresh {
+		commit, err := headCommit(depWD)
+		if err != nil {
+			return fmt.Errorf("%s@%s: %w", ref.Path, ref.Version, err)
+		}
+		if commit == locked.Commit {
+			updated.Deps = append(updated.Deps, locked)
+			return nil
+		}
+	}
+
+	actual, err := lockModule(ref, depWD)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		if err := locked.verify(actual, depWD); err != nil {
+			return err
+		}
+	}
+
+	updated.Deps = append(updated.Deps, actual)
+
+	return nil
+}
+
+// lockDeps checks resolved dependencies against the lock file of the entry module.
+// Dependencies missing in the lock file are added to it, the ones that are no longer used are removed.
+func lockDeps(modRootPath string, res resolution) error {
+	lock, err := readLockFile(modRootPath)
+	if err != nil {
+		return err
+	}
+
+	updated := lockFile{Deps: make([]lockedModule, 0, len(res.dirs))}
+	for ref, depWD := range res.dirs {
+		if err := lock.add(&updated, ref, depWD, res.fresh[ref]); err != nil {
+			return err
+		}
+	}
+
+	if sameLockedDeps(lock.Deps, updated.Deps) {
+		return nil
+	}
+
+	return writeLockFile(modRootPath, updated)
+}
+
+// Verify hashes every dependency of the module that the wd belongs to
+// and checks that it matches its entry in the lock file.
+// Builds only do that for dependencies that were just downloaded or whose commit changed.
+func (b Builder) Verify(ctx context.Context, wd string) error {
+	manifest, modRootPath, err := b.getNearestManifest(wd)
+	if err != nil {
+		return fmt.Errorf("retrieve manifest: %w", err)
+	}
+
+	release, err := acquireLockFile()
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock file: %w", err)
+	}
+	defer release()
+
+	res, err := b.resolve(ctx, manifest.Deps)
+	if err != nil {
+		return fmt.Errorf("resolve deps: %w", err)
+	}
+
+	lock, err := readLockFile(modRootPath)
+	if err != nil {
+		return err
+	}
+
+	refs := slices.SortedFunc(maps.Keys(res.dirs), compareModuleRefs)
+
+	var errs []error
+	for _, ref := range refs {
+		locked, ok := lock.find(ref)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s@%s is missing in %s", ref.Path, ref.Version, lockFileName))
+			continue
+		}
+
+		actual, err := lockModule(ref, res.dirs[ref])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := locked.verify(actual, res.dirs[ref]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func sameLockedDeps(a, b []lockedModule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, dep := range b {
+		if !slices.Contains(a, dep) {
+			return false
+		}
+	}
+	return true
+}
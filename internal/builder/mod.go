@@ -2,6 +2,7 @@ package builder
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -15,6 +16,14 @@ import (
 func (p Builder) LoadModuleByPath(
 	ctx context.Context,
 	wd string,
+) (compiler.RawModule, string, error) {
+	return p.loadModule(ctx, wd, false)
+}
+
+func (p Builder) loadModule(
+	ctx context.Context,
+	wd string,
+	includeTests bool,
 ) (compiler.RawModule, string, error) {
 	manifest, modRootPath, err := p.getNearestManifest(wd)
 	if err != nil {
@@ -22,18 +31,63 @@ func (p Builder) LoadModuleByPath(
 	}
 
 	pkgs := map[string]compiler.RawPackage{}
-	if err := retrieveSourceCode(modRootPath, pkgs); err != nil {
+	if err := retrieveSourceCode(modRootPath, pkgs, includeTests); err != nil {
 		return compiler.RawModule{}, "", fmt.Errorf("walk: %w", err)
 	}
 
+	native, err := retrieveNativeCode(modRootPath)
+	if err != nil {
+		return compiler.RawModule{}, "", fmt.Errorf("native: %w", err)
+	}
+
 	return compiler.RawModule{
 		Manifest: manifest,
 		Packages: pkgs,
+		Native:   native,
 	}, modRootPath, nil
 }
 
-// retrieveSourceCode recursively walks the given tree and fills given pkgs with neva files
-func retrieveSourceCode(rootPath string, pkgs map[string]compiler.RawPackage) error {
+// nativeDir is a directory in the module root with go implementations of module's externs.
+const nativeDir = "native"
+
+// retrieveNativeCode reads go files (except tests) from module's native directory, if there is one.
+func retrieveNativeCode(modRootPath string) (compiler.RawPackage, error) {
+	entries, err := os.ReadDir(filepath.Join(modRootPath, nativeDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	native := compiler.RawPackage{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		bb, err := os.ReadFile(filepath.Join(modRootPath, nativeDir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		native[name] = bb
+	}
+
+	if len(native) == 0 {
+		return nil, nil
+	}
+
+	return native, nil
+}
+
+// testFileSuffix is a suffix of the files that are only loaded by `neva test`.
+const testFileSuffix = "_test.neva"
+
+// retrieveSourceCode recursively walks the given tree and fills given pkgs with neva files.
+// Test files are skipped unless includeTests is set.
+func retrieveSourceCode(rootPath string, pkgs map[string]compiler.RawPackage, includeTests bool) error {
 	fsys := os.DirFS(rootPath)
 	return fs.WalkDir(fsys, ".", func(filePath string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -49,6 +103,10 @@ func retrieveSourceCode(rootPath string, pkgs map[string]compiler.RawPackage) er
 			return nil
 		}
 
+		if !includeTests && strings.HasSuffix(d.Name(), testFileSuffix) {
+			return nil
+		}
+
 		file, err := fsys.Open(filePath)
 		if err != nil {
 			return err
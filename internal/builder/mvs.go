@@ -0,0 +1,244 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+var entryModRef = core.ModuleRef{Path: "@"}
+
+// Graph is the requirement graph of the entry module with versions selected by minimal version selection.
+type Graph struct {
+	// Reqs are direct requirements of every module reachable from the entry module,
+	// including versions that were not selected. Entry module is referenced as "@".
+	Reqs map[core.ModuleRef][]core.ModuleRef
+	// Selected is the version of each module path that is used in the build.
+	Selected map[string]core.ModuleRef
+}
+
+// Modules returns every module in the graph sorted by path and version, entry module goes first.
+func (g Graph) Modules() []core.ModuleRef {
+	refs := make([]core.ModuleRef, 0, len(g.Reqs))
+	for ref := range g.Reqs {
+		refs = append(refs, ref)
+	}
+	slices.SortFunc(refs, compareModuleRefs)
+	return refs
+}
+
+// Why returns the shortest chain of requirements from the entry module to any version of the module.
+// It returns false if the module isn't in the graph.
+func (g Graph) Why(path string) ([]core.ModuleRef, bool) {
+	if _, ok := g.Selected[path]; !ok {
+		return nil, false
+	}
+
+	prev := map[core.ModuleRef]core.ModuleRef{}
+	visited := map[core.ModuleRef]bool{entryModRef: true}
+	queue := []core.ModuleRef{entryModRef}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		if cur.Path == path {
+			chain := []core.ModuleRef{cur}
+			for cur != entryModRef {
+				cur = prev[cur]
+				chain = append(chain, cur)
+			}
+			slices.Reverse(chain)
+			return chain, true
+		}
+
+		for _, req := range g.Reqs[cur] {
+			if visited[req] {
+				continue
+			}
+			visited[req] = true
+			prev[req] = cur
+			queue = append(queue, req)
+		}
+	}
+
+	return nil, false
+}
+
+// resolution is the result of resolving dependencies of the entry module.
+type resolution struct {
+	graph Graph
+	// mods are loaded modules of every version in the graph.
+	mods map[core.ModuleRef]compiler.RawModule
+	// dirs are paths every version in the graph was downloaded to.
+	dirs map[core.ModuleRef]string
+	// fresh are versions that were downloaded during the resolution rather than found on disk.
+	fresh map[core.ModuleRef]bool
+}
+
+// resolve downloads dependencies of the entry module recursively and selects a single version of each module.
+// Like with Go modules, the selected version is the maximum of versions required anywhere in the graph.
+// Requirements of versions that end up not being selected are still taken into account.
+func (b Builder) resolve(ctx context.Context, entryDeps map[string]core.ModuleRef) (resolution, error) {
+	res := resolution{
+		graph: Graph{
+			Reqs:     map[core.ModuleRef][]core.ModuleRef{},
+			Selected: map[string]core.ModuleRef{},
+		},
+		mods:  map[core.ModuleRef]compiler.RawModule{},
+		dirs:  map[core.ModuleRef]string{},
+		fresh: map[core.ModuleRef]bool{},
+	}
+
+	reqs, err := requirements(entryModRef, entryDeps)
+	if err != nil {
+		return resolution{}, err
+	}
+	res.graph.Reqs[entryModRef] = reqs
+
+	queue := slices.Clone(reqs)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+
+		if _, ok := res.graph.Reqs[ref]; ok {
+			continue
+		}
+
+		depWD, _, fresh, err := b.downloadDep(ref)
+		if err != nil {
+			return resolution{}, fmt.Errorf("download %v: %w", ref, err)
+		}
+
+		depMod, _, err := b.LoadModuleByPath(ctx, depWD)
+		if err != nil {
+			return resolution{}, fmt.Errorf("load %v: %w", ref, err)
+		}
+
+		reqs, err := requirements(ref, depMod.Manifest.Deps)
+		if err != nil {
+			return resolution{}, err
+		}
+
+		res.graph.Reqs[ref] = reqs
+		res.mods[ref] = depMod
+		res.dirs[ref] = depWD
+		res.fresh[ref] = fresh
+
+		queue = append(queue, reqs...)
+	}
+
+	res.graph.Selected = selectVersions(res.graph.Reqs)
+
+	if err := checkConflicts(res.graph); err != nil {
+		return resolution{}, err
+	}
+
+	return res, nil
+}
+
+// requirements returns sorted unique dependencies of the module, except for stdlib.
+func requirements(requirer core.ModuleRef, deps map[string]core.ModuleRef) ([]core.ModuleRef, error) {
+	reqs := make([]core.ModuleRef, 0, len(deps))
+	for _, dep := range deps {
+		if dep.Path == "std" || slices.Contains(reqs, dep) {
+			continue
+		}
+		if _, err := semver.NewVersion(dep.Version); err != nil {
+			return nil, fmt.Errorf("%v requires %v: invalid version %q: %w", requirer, dep.Path, dep.Version, err)
+		}
+		reqs = append(reqs, dep)
+	}
+	slices.SortFunc(reqs, compareModuleRefs)
+	return reqs, nil
+}
+
+// selectVersions returns the maximum required version of each module path.
+// Versions are expected to be validated by requirements.
+func selectVersions(reqs map[core.ModuleRef][]core.ModuleRef) map[string]core.ModuleRef {
+	selected := map[string]core.ModuleRef{}
+	versions := map[string]*semver.Version{}
+
+	for _, requirer := range (Graph{Reqs: reqs}).Modules() {
+		for _, req := range reqs[requirer] {
+			v := semver.MustParse(req.Version)
+			if prev, ok := versions[req.Path]; ok && !v.GreaterThan(prev) {
+				continue
+			}
+			versions[req.Path] = v
+			selected[req.Path] = req
+		}
+	}
+
+	return selected
+}
+
+// checkConflicts returns error if a module in the build requires major version of a dependency
+// other than the selected one, because different major versions are not compatible.
+// Only requirements of the entry module and selected versions are checked.
+func checkConflicts(g Graph) error {
+	requirers := map[string][]string{}
+	conflicts := []string{}
+
+	for _, requirer := range g.Modules() {
+		if requirer != entryModRef && g.Selected[requirer.Path] != requirer {
+			continue
+		}
+		for _, req := range g.Reqs[requirer] {
+			requirers[req.Path] = append(requirers[req.Path], fmt.Sprintf("%v requires %v", requirer, req))
+
+			selected := g.Selected[req.Path]
+			if semver.MustParse(req.Version).Major() != semver.MustParse(selected.Version).Major() &&
+				!slices.Contains(conflicts, req.Path) {
+				conflicts = append(conflicts, req.Path)
+			}
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	slices.Sort(conflicts)
+
+	var sb strings.Builder
+	for i, path := range conflicts {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "conflicting major versions of %s, selected %s:", path, g.Selected[path].Version)
+		for _, requirement := range requirers[path] {
+			fmt.Fprintf(&sb, "\n\t%s", requirement)
+		}
+	}
+
+	return errors.New(sb.String())
+}
+
+// compareModuleRefs orders entry module first and then other modules by path and version.
+func compareModuleRefs(a, b core.ModuleRef) int {
+	if a == entryModRef || b == entryModRef {
+		switch {
+		case a == b:
+			return 0
+		case a == entryModRef:
+			return -1
+		default:
+			return 1
+		}
+	}
+	if c := strings.Compare(a.Path, b.Path); c != 0 {
+		return c
+	}
+	if c := semver.MustParse(a.Version).Compare(semver.MustParse(b.Version)); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Version, b.Version)
+}
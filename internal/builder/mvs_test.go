@@ -0,0 +1,80 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+func ref(path, version string) core.ModuleRef {
+	return core.ModuleRef{Path: path, Version: version}
+}
+
+func TestSelectVersions(t *testing.T) {
+	// a@1.1.0 is not selected but its requirement of c still counts
+	reqs := map[core.ModuleRef][]core.ModuleRef{
+		entryModRef:        {ref("a", "1.0.0"), ref("b", "1.0.0")},
+		ref("a", "1.0.0"):  {ref("c", "1.0.0")},
+		ref("a", "1.2.0"):  {},
+		ref("b", "1.0.0"):  {ref("a", "1.1.0"), ref("a", "1.2.0")},
+		ref("a", "1.1.0"):  {ref("c", "v1.3.0")},
+		ref("c", "1.0.0"):  {},
+		ref("c", "v1.3.0"): {},
+	}
+
+	require.Equal(t, map[string]core.ModuleRef{
+		"a": ref("a", "1.2.0"),
+		"b": ref("b", "1.0.0"),
+		"c": ref("c", "v1.3.0"),
+	}, selectVersions(reqs))
+}
+
+func TestGraphWhy(t *testing.T) {
+	reqs := map[core.ModuleRef][]core.ModuleRef{
+		entryModRef:       {ref("a", "1.0.0"), ref("b", "1.0.0")},
+		ref("a", "1.0.0"): {ref("c", "1.0.0")},
+		ref("b", "1.0.0"): {ref("a", "1.1.0")},
+		ref("a", "1.1.0"): {ref("d", "1.0.0")},
+		ref("c", "1.0.0"): {},
+		ref("d", "1.0.0"): {},
+	}
+	g := Graph{Reqs: reqs, Selected: selectVersions(reqs)}
+
+	chain, ok := g.Why("d")
+	require.True(t, ok)
+	require.Equal(t, []core.ModuleRef{entryModRef, ref("b", "1.0.0"), ref("a", "1.1.0"), ref("d", "1.0.0")}, chain)
+
+	// c is only required by the version of a that is not selected, but it's still in the build
+	chain, ok = g.Why("c")
+	require.True(t, ok)
+	require.Equal(t, []core.ModuleRef{entryModRef, ref("a", "1.0.0"), ref("c", "1.0.0")}, chain)
+
+	_, ok = g.Why("e")
+	require.False(t, ok)
+}
+
+func TestCheckConflicts(t *testing.T) {
+	reqs := map[core.ModuleRef][]core.ModuleRef{
+		entryModRef:       {ref("a", "1.0.0"), ref("b", "1.0.0")},
+		ref("a", "1.0.0"): {ref("c", "1.0.0")},
+		ref("b", "1.0.0"): {ref("c", "2.0.0")},
+		ref("c", "1.0.0"): {},
+		ref("c", "2.0.0"): {},
+	}
+	g := Graph{Reqs: reqs, Selected: selectVersions(reqs)}
+
+	err := checkConflicts(g)
+	require.EqualError(t, err, `conflicting major versions of c, selected 2.0.0:
+	a@1.0.0 requires c@1.0.0
+	b@1.0.0 requires c@2.0.0`)
+
+	// requirements of versions that are not selected are not checked
+	reqs[entryModRef] = append(reqs[entryModRef], ref("a", "1.1.0"))
+	reqs[ref("a", "1.1.0")] = []core.ModuleRef{ref("c", "2.1.0")}
+	reqs[ref("c", "2.1.0")] = []core.ModuleRef{}
+	g = Graph{Reqs: reqs, Selected: selectVersions(reqs)}
+
+	require.NoError(t, checkConflicts(g))
+}
@@ -0,0 +1,492 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	goruntime "runtime"
+	"sort"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/interpreter"
+	nevaruntime "github.com/nevalang/neva/internal/runtime"
+	pkgruntime "github.com/nevalang/neva/pkg/runtime"
+)
+
+func newBenchCmd(workdir string, cmplr compiler.Compiler) *cli.Command {
+	return &cli.Command{
+		Name:  "bench",
+		Usage: "Run benchmark components from *_test.neva files",
+		Args:  true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "run",
+				Usage: "Run only benchmarks with names matching the regular expression",
+			},
+			&cli.IntFlag{
+				Name:  "runs",
+				Usage: "Number of times to run each benchmark",
+				Value: 100,
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Fail benchmark if a single run takes longer than this",
+				Value: time.Minute,
+			},
+			&cli.StringFlag{
+				Name:  "save",
+				Usage: "Save results to the file to use it as a baseline later",
+			},
+			&cli.StringFlag{
+				Name:  "baseline",
+				Usage: "Compare results with the ones saved to the file before",
+			},
+			&cli.Float64Flag{
+				Name:  "threshold",
+				Usage: "Percentage by which benchmark can be worse than baseline before it's reported as regression",
+				Value: 10,
+			},
+		},
+		ArgsUsage: "Provide paths to packages to benchmark, path/... benchmarks all packages inside. Defaults to ./...",
+		Action: func(cliCtx *cli.Context) error {
+			var filter *regexp.Regexp
+			if cliCtx.IsSet("run") {
+				var err error
+				filter, err = regexp.Compile(cliCtx.String("run"))
+				if err != nil {
+					return fmt.Errorf("invalid run flag: %w", err)
+				}
+			}
+
+			runs := cliCtx.Int("runs")
+			if runs < 1 {
+				return fmt.Errorf("runs must be positive: %d", runs)
+			}
+
+			var baseline []benchResult
+			if path := cliCtx.String("baseline"); path != "" {
+				var err error
+				baseline, err = loadBenchResults(path)
+				if err != nil {
+					return err
+				}
+			}
+
+			args := cliCtx.Args().Slice()
+			if len(args) == 0 {
+				args = []string{"./..."}
+			}
+
+			pkgs, err := testPkgPaths(workdir, args)
+			if err != nil {
+				return err
+			}
+
+			runner := benchRunner{
+				filter:  filter,
+				runs:    runs,
+				timeout: cliCtx.Duration("timeout"),
+				out:     os.Stdout,
+			}
+
+			var results []benchResult
+			failed := false
+			for _, pkg := range pkgs {
+				pkgResults, ok, err := runner.runPkg(cliCtx.Context, cmplr, pkg)
+				if err != nil {
+					return err
+				}
+				results = append(results, pkgResults...)
+				failed = failed || !ok
+			}
+
+			if path := cliCtx.String("save"); path != "" {
+				if err := saveBenchResults(path, results); err != nil {
+					return err
+				}
+			}
+
+			if baseline != nil {
+				if regressed := compareBenchResults(os.Stdout, baseline, results, cliCtx.Float64("threshold")); regressed {
+					failed = true
+				}
+			}
+
+			if failed {
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+type benchRunner struct {
+	filter  *regexp.Regexp
+	runs    int
+	timeout time.Duration
+	out     io.Writer
+}
+
+// benchResult is the outcome of a single benchmark, it's also the format of the baseline file.
+// Benchmarks with native code are executed as separate processes,
+// so their messages and allocations are not measured and left zero.
+type benchResult struct {
+	Pkg          string  `json:"pkg"`
+	Name         string  `json:"name"`
+	Runs         int     `json:"runs"`
+	MsgsPerSec   float64 `json:"msgsPerSec"`
+	AllocsPerRun float64 `json:"allocsPerRun"`
+	BytesPerRun  float64 `json:"bytesPerRun"`
+	// Percentiles of duration of a single run.
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+}
+
+// runPkg compiles and runs benchmarks of the package one by one so they don't affect each other.
+// It returns results of successful benchmarks and reports whether all of them succeeded.
+func (r benchRunner) runPkg(ctx context.Context, cmplr compiler.Compiler, pkg string) ([]benchResult, bool, error) {
+	start := time.Now()
+
+	benchmarks, err := cmplr.CompileBenchmarks(ctx, pkg)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tw := tabwriter.NewWriter(r.out, 0, 8, 2, ' ', 0)
+
+	var results []benchResult
+	ok := true
+	for _, bench := range benchmarks {
+		if r.filter != nil && !r.filter.MatchString(bench.Name) {
+			continue
+		}
+
+		var (
+			result benchResult
+			err    error
+		)
+		if len(bench.IR.Natives) > 0 {
+			result, err = r.runBenchBinary(ctx, cmplr, bench)
+		} else {
+			result, err = r.runBench(ctx, bench)
+		}
+		if err != nil {
+			ok = false
+			fmt.Fprintf(tw, "--- FAIL: %s\n    %v\n", bench.Name, err)
+			continue
+		}
+		result.Pkg = pkg
+
+		if result.MsgsPerSec == 0 {
+			fmt.Fprintf(
+				tw,
+				"%s\t%d runs\tp50 %v\tp90 %v\tp99 %v\n",
+				result.Name,
+				result.Runs,
+				result.P50,
+				result.P90,
+				result.P99,
+			)
+		} else {
+			fmt.Fprintf(
+				tw,
+				"%s\t%d runs\t%.0f msgs/s\t%.0f allocs/run\t%.0f B/run\tp50 %v\tp90 %v\tp99 %v\n",
+				result.Name,
+				result.Runs,
+				result.MsgsPerSec,
+				result.AllocsPerRun,
+				result.BytesPerRun,
+				result.P50,
+				result.P90,
+				result.P99,
+			)
+		}
+		results = append(results, result)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return nil, false, err
+	}
+
+	status := "ok  "
+	if !ok {
+		status = "FAIL"
+	}
+	fmt.Fprintf(r.out, "%s\t%s\t%.3fs\n", status, pkg, time.Since(start).Seconds())
+
+	return results, ok, nil
+}
+
+// runBench runs the benchmark once to warm up and then the given number of times.
+// Only execution of the program is measured: program and registry are constructed once before the runs,
+// channels and function calls of every run are created before its measurement starts.
+func (r benchRunner) runBench(ctx context.Context, bench compiler.Test) (benchResult, error) {
+	prog, err := interpreter.Program(bench.IR)
+	if err != nil {
+		return benchResult{}, testFailure{bench.Meta, err.Error()}
+	}
+
+	registry := pkgruntime.NewRegistry()
+	counter := &benchInterceptor{}
+
+	if _, err := r.runOnce(ctx, bench, prog, registry, counter); err != nil {
+		return benchResult{}, err
+	}
+	counter.sent.Store(0)
+
+	durations := make([]time.Duration, r.runs)
+	var (
+		total          time.Duration
+		mallocs, bytes uint64
+	)
+
+	goruntime.GC()
+
+	for i := range durations {
+		run, err := r.runOnce(ctx, bench, prog, registry, counter)
+		if err != nil {
+			return benchResult{}, err
+		}
+		durations[i] = run.duration
+		total += run.duration
+		mallocs += run.mallocs
+		bytes += run.bytes
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	return benchResult{
+		Name:         bench.Name,
+		Runs:         r.runs,
+		MsgsPerSec:   float64(counter.sent.Load()) / total.Seconds(),
+		AllocsPerRun: float64(mallocs) / float64(r.runs),
+		BytesPerRun:  float64(bytes) / float64(r.runs),
+		P50:          percentile(durations, 50),
+		P90:          percentile(durations, 90),
+		P99:          percentile(durations, 99),
+	}, nil
+}
+
+// benchRun is the measurement of a single run of the benchmark.
+type benchRun struct {
+	duration       time.Duration
+	mallocs, bytes uint64
+}
+
+func (r benchRunner) runOnce(
+	ctx context.Context,
+	bench compiler.Test,
+	prog pkgruntime.Program,
+	registry pkgruntime.Registry,
+	counter *benchInterceptor,
+) (benchRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	reporter := &testReporter{test: bench}
+	counter.stopped.Store(false)
+
+	prepared, err := pkgruntime.Prepare(
+		prog,
+		pkgruntime.WithRegistry(registry),
+		pkgruntime.WithInterceptor(counter),
+	)
+	if err != nil {
+		return benchRun{}, testFailure{bench.Meta, err.Error()}
+	}
+
+	var before, after goruntime.MemStats
+	goruntime.ReadMemStats(&before)
+
+	start := time.Now()
+	err = prepared.Run(nevaruntime.WithTestReporter(ctx, reporter))
+	duration := time.Since(start)
+
+	goruntime.ReadMemStats(&after)
+
+	if err != nil {
+		return benchRun{}, testFailure{bench.Meta, err.Error()}
+	}
+	if failures := reporter.reported(); len(failures) > 0 {
+		return benchRun{}, failures[0]
+	}
+	if !counter.stopped.Load() {
+		return benchRun{}, testFailure{bench.Meta, fmt.Sprintf("benchmark timed out after %v", r.timeout)}
+	}
+
+	return benchRun{
+		duration: duration,
+		mallocs:  after.Mallocs - before.Mallocs,
+		bytes:    after.TotalAlloc - before.TotalAlloc,
+	}, nil
+}
+
+// runBenchBinary is like runBench but for benchmarks with native code.
+// Every run executes the binary built for the benchmark, so durations include start of the process.
+func (r benchRunner) runBenchBinary(
+	ctx context.Context,
+	cmplr compiler.Compiler,
+	bench compiler.Test,
+) (benchResult, error) {
+	bin, cleanup, err := buildBinary(cmplr, bench.IR, false)
+	if err != nil {
+		return benchResult{}, err
+	}
+	defer cleanup()
+
+	if _, err := r.runBinaryOnce(ctx, bench, bin); err != nil {
+		return benchResult{}, err
+	}
+
+	durations := make([]time.Duration, r.runs)
+	for i := range durations {
+		durations[i], err = r.runBinaryOnce(ctx, bench, bin)
+		if err != nil {
+			return benchResult{}, err
+		}
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	return benchResult{
+		Name: bench.Name,
+		Runs: r.runs,
+		P50:  percentile(durations, 50),
+		P90:  percentile(durations, 90),
+		P99:  percentile(durations, 99),
+	}, nil
+}
+
+func (r benchRunner) runBinaryOnce(ctx context.Context, bench compiler.Test, bin string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	failures, err := runBinary(ctx, bin)
+	duration := time.Since(start)
+
+	switch {
+	case len(failures) > 0:
+		return 0, testFailure{nodeMeta(bench, failures[0].node), failures[0].msg}
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return 0, testFailure{bench.Meta, fmt.Sprintf("benchmark timed out after %v", r.timeout)}
+	case err != nil:
+		return 0, testFailure{bench.Meta, err.Error()}
+	}
+
+	return duration, nil
+}
+
+// percentile returns the duration below which given percent of sorted durations fall.
+func percentile(sorted []time.Duration, percent int) time.Duration {
+	idx := (len(sorted)*percent+99)/100 - 1
+	return sorted[max(idx, 0)].Round(time.Microsecond)
+}
+
+// benchInterceptor counts messages sent by the program and remembers whether it stopped.
+type benchInterceptor struct {
+	sent    atomic.Int64
+	stopped atomic.Bool
+}
+
+func (b *benchInterceptor) Sent(_ pkgruntime.PortSlotAddr, msg pkgruntime.Msg) pkgruntime.Msg {
+	b.sent.Add(1)
+	return msg
+}
+
+func (b *benchInterceptor) Received(addr pkgruntime.PortSlotAddr, msg pkgruntime.Msg) pkgruntime.Msg {
+	if addr.PortAddr == pkgruntime.StopAddr.PortAddr {
+		b.stopped.Store(true)
+	}
+	return msg
+}
+
+func loadBenchResults(path string) ([]benchResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read baseline: %w", err)
+	}
+
+	var results []benchResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+
+	return results, nil
+}
+
+func saveBenchResults(path string, results []benchResult) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// compareBenchResults prints how results changed since the baseline and reports whether any of them regressed,
+// i.e. got fewer messages per second, more allocations or slower median run by more than threshold percent.
+// Benchmarks that are missing in the baseline are skipped.
+func compareBenchResults(w io.Writer, baseline, results []benchResult, threshold float64) bool {
+	type key struct{ pkg, name string }
+	old := make(map[key]benchResult, len(baseline))
+	for _, result := range baseline {
+		old[key{result.Pkg, result.Name}] = result
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nbenchmark\tmsgs/s\tallocs/run\tp50\t")
+
+	regressed := false
+	for _, cur := range results {
+		prev, ok := old[key{cur.Pkg, cur.Name}]
+		if !ok {
+			continue
+		}
+
+		msgs := delta(prev.MsgsPerSec, cur.MsgsPerSec)
+		allocs := delta(prev.AllocsPerRun, cur.AllocsPerRun)
+		p50 := delta(float64(prev.P50), float64(cur.P50))
+
+		status := ""
+		if -msgs > threshold || allocs > threshold || p50 > threshold {
+			status = "REGRESSION"
+			regressed = true
+		}
+
+		fmt.Fprintf(
+			tw,
+			"%s/%s\t%.0f -> %.0f (%+.1f%%)\t%.0f -> %.0f (%+.1f%%)\t%v -> %v (%+.1f%%)\t%s\n",
+			cur.Pkg, cur.Name,
+			prev.MsgsPerSec, cur.MsgsPerSec, msgs,
+			prev.AllocsPerRun, cur.AllocsPerRun, allocs,
+			prev.P50, cur.P50, p50,
+			status,
+		)
+	}
+
+	_ = tw.Flush()
+
+	return regressed
+}
+
+// delta returns change from prev to cur in percent.
+func delta(prev, cur float64) float64 {
+	if prev == 0 {
+		if cur == 0 {
+			return 0
+		}
+		return 100
+	}
+	return (cur - prev) / prev * 100
+}
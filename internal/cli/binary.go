@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/ir"
+)
+
+// buildBinary compiles program to executable in a new temporary directory.
+// It's used for programs with native code because such programs can't be executed in-process.
+// Returns path to the executable and function that removes the directory.
+func buildBinary(cmplr compiler.Compiler, prog *ir.Program, trace bool) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "neva-bin-*")
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanup := func() {
+		if err := os.RemoveAll(dir); err != nil {
+			fmt.Println("failed to remove output directory:", err)
+		}
+	}
+
+	if err := cmplr.EmitIR(dir, prog, trace); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return filepath.Join(dir, binaryName()), cleanup, nil
+}
+
+// binaryName returns name of the executable that native backend produces.
+func binaryName() string {
+	if runtime.GOOS == "windows" {
+		return "output.exe"
+	}
+	return "output"
+}
+
+// binaryFailure is a failure of the test function that binary printed to stderr.
+type binaryFailure struct {
+	node string // Path of the failed node, empty if unknown.
+	msg  string
+}
+
+// runBinary runs executable until it exits or ctx is done
+// and returns failures of test functions that it printed to stderr.
+// The rest of the output is passed through.
+func runBinary(ctx context.Context, bin string) ([]binaryFailure, error) {
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	var failures []binaryFailure
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "fail: "):
+			// e.g. fail: assert: expected 42, got 43
+			node, msg, _ := strings.Cut(strings.TrimPrefix(line, "fail: "), ": ")
+			failures = append(failures, binaryFailure{node: node, msg: msg})
+		case strings.HasPrefix(line, "panic: "):
+			failures = append(failures, binaryFailure{msg: line})
+		default:
+			fmt.Fprintln(os.Stderr, line)
+		}
+	}
+
+	return failures, cmd.Wait()
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/nevalang/neva/internal/compiler"
 
@@ -14,8 +15,11 @@ func newBuildCmd(
 	compilerToGo compiler.Compiler,
 	compilerToNative compiler.Compiler,
 	compilerToWASM compiler.Compiler,
+	compilerToWASI compiler.Compiler,
 	compilerToJSON compiler.Compiler,
 	compilerToDOT compiler.Compiler,
+	compilerToSVG compiler.Compiler,
+	compilerToHTML compiler.Compiler,
 ) *cli.Command {
 	return &cli.Command{
 		Name:  "build",
@@ -32,15 +36,35 @@ func newBuildCmd(
 			},
 			&cli.StringFlag{
 				Name:  "target",
-				Usage: "Target platform for build (options: go, wasm, native, json, dot). For 'native' target, 'target-os' and 'target-arch' flags can be used, but if used, they must be used together.",
+				Usage: "Target platform for build (options: go, wasm, wasi, native, json, dot, svg, html). For 'native' target, 'target-os' and 'target-arch' flags can be used, but if used, they must be used together.",
 				Action: func(ctx *cli.Context, s string) error {
 					switch s {
-					case "go", "wasm", "native", "json", "dot":
+					case "go", "wasm", "wasi", "native", "json", "dot", "svg", "html":
 						return nil
 					}
 					return fmt.Errorf("Unknown target %s", s)
 				},
 			},
+			&cli.StringFlag{
+				Name:  "lib",
+				Usage: "Name of the component to export as a Go library package instead of building executable. Path argument must point to the package with this component. Only supported for go target.",
+			},
+			&cli.StringFlag{
+				Name:  "go-module",
+				Usage: "Module path of the generated Go library. Defaults to lowercased component name. Must be combined with 'lib'.",
+			},
+			&cli.StringSliceFlag{
+				Name:  "emit",
+				Usage: "Comma-separated compiler stages to write to the output directory as JSON (options: parsed, analyzed, desugared, ir). Stages that succeeded are written even if the build fails.",
+				Action: func(ctx *cli.Context, stages []string) error {
+					for _, stage := range stages {
+						if !slices.Contains(compiler.Stages, compiler.Stage(stage)) {
+							return fmt.Errorf("Unknown stage %s", stage)
+						}
+					}
+					return nil
+				},
+			},
 			&cli.StringFlag{
 				Name:  "target-os",
 				Usage: "Target operating system for native build. See 'neva osarch' for supported combinations. Only supported for native target. Not needed if building for the current platform. Must be combined properly with 'target-arch'.",
@@ -60,7 +84,7 @@ func newBuildCmd(
 			}
 
 			switch target {
-			case "go", "wasm", "json", "dot", "native":
+			case "go", "wasm", "wasi", "json", "dot", "svg", "html", "native":
 			default:
 				return fmt.Errorf("Unknown target %s", target)
 			}
@@ -79,6 +103,19 @@ func newBuildCmd(
 				return fmt.Errorf("target-os and target-arch are only supported when target is native")
 			}
 
+			var library *compiler.LibraryInput
+			if cliCtx.IsSet("lib") {
+				if target != "go" {
+					return fmt.Errorf("lib is only supported when target is go")
+				}
+				library = &compiler.LibraryInput{
+					Component:  cliCtx.String("lib"),
+					ModulePath: cliCtx.String("go-module"),
+				}
+			} else if cliCtx.IsSet("go-module") {
+				return fmt.Errorf("go-module must be combined with lib")
+			}
+
 			mainPkg, err := mainPkgPathFromArgs(cliCtx)
 			if err != nil {
 				return err
@@ -94,10 +131,17 @@ func newBuildCmd(
 				isTraceEnabled = true
 			}
 
+			var emit []compiler.Stage
+			for _, stage := range cliCtx.StringSlice("emit") {
+				emit = append(emit, compiler.Stage(stage))
+			}
+
 			compilerInput := compiler.CompilerInput{
-				Main:   mainPkg,
-				Output: outputDirPath,
-				Trace:  isTraceEnabled,
+				Main:    mainPkg,
+				Output:  outputDirPath,
+				Trace:   isTraceEnabled,
+				Library: library,
+				Emit:    emit,
 			}
 
 			var compilerToUse compiler.Compiler
@@ -106,10 +150,16 @@ func newBuildCmd(
 				compilerToUse = compilerToGo
 			case "wasm":
 				compilerToUse = compilerToWASM
+			case "wasi":
+				compilerToUse = compilerToWASI
 			case "json":
 				compilerToUse = compilerToJSON
 			case "dot":
 				compilerToUse = compilerToDOT
+			case "svg":
+				compilerToUse = compilerToSVG
+			case "html":
+				compilerToUse = compilerToHTML
 			case "native":
 				compilerToUse = compilerToNative
 			}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/nevalang/neva/internal/compiler"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+func newCheckCmd(cmplr compiler.Compiler, diags diagnostics) *cli.Command {
+	return &cli.Command{
+		Name:      "check",
+		Usage:     "Check source code for errors without generating any code",
+		Args:      true,
+		Flags:     []cli.Flag{diags.flag()},
+		ArgsUsage: "Provide paths to packages, every package of their modules is checked. Defaults to current directory",
+		Action: func(cliCtx *cli.Context) error {
+			var errs problems
+
+			for _, path := range modulePathsFromArgs(cliCtx) {
+				_, _, err := cmplr.Analyze(cliCtx.Context, path)
+				if err := errs.add(err); err != nil {
+					return err
+				}
+			}
+
+			if len(errs.list) == 0 {
+				return nil
+			}
+
+			if err := diags.print(cliCtx, compiler.Join(errs.list...)); err != nil {
+				return err
+			}
+
+			return cli.Exit("", 1)
+		},
+	}
+}
+
+// modulePathsFromArgs returns paths of packages to check, "path/..." is the same as "path"
+// because packages are always checked together with the whole module.
+func modulePathsFromArgs(cliCtx *cli.Context) []string {
+	args := cliCtx.Args().Slice()
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		path := strings.TrimSuffix(strings.TrimSuffix(arg, "..."), "/")
+		if path == "" {
+			path = "."
+		}
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+// problems collects compiler errors of several packages.
+// Packages of the same module produce the same errors so duplicates are skipped.
+type problems struct {
+	list     []*compiler.Error
+	reported map[string]bool
+}
+
+// add adds deepest errors of the given one, errors of other kinds are returned as is.
+func (p *problems) add(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var compilerErr *compiler.Error
+	if !errors.As(err, &compilerErr) {
+		return err
+	}
+
+	if p.reported == nil {
+		p.reported = map[string]bool{}
+	}
+
+	for _, deepest := range compilerErr.Errors() {
+		if msg := deepest.Error(); !p.reported[msg] {
+			p.reported[msg] = true
+			p.list = append(p.list, deepest)
+		}
+	}
+
+	return nil
+}
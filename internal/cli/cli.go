@@ -3,7 +3,6 @@ package cli
 import (
 	"errors"
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -20,9 +19,15 @@ func NewApp(
 	goc compiler.Compiler,
 	nativec compiler.Compiler,
 	wasmc compiler.Compiler,
+	wasic compiler.Compiler,
 	jsonc compiler.Compiler,
 	dotc compiler.Compiler,
+	svgc compiler.Compiler,
+	htmlc compiler.Compiler,
+	testc compiler.Compiler,
 ) *cli.App {
+	diags := diagnostics{workdir: workdir, bldr: bldr}
+
 	return &cli.App{
 		Name:  "neva",
 		Usage: "Dataflow programming language with static types and implicit parallelism",
@@ -31,8 +36,17 @@ func NewApp(
 			upgradeCmd,
 			newNewCmd(workdir),
 			newGetCmd(workdir, bldr),
-			newRunCmd(workdir, nativec),
-			newBuildCmd(workdir, goc, nativec, wasmc, jsonc, dotc),
+			newModCmd(workdir, bldr),
+			diags.wrap(newRunCmd(workdir, bldr, diags, nativec, wasic)),
+			diags.wrap(newBuildCmd(workdir, goc, nativec, wasmc, wasic, jsonc, dotc, svgc, htmlc)),
+			newTestCmd(workdir, testc),
+			newBenchCmd(workdir, testc),
+			newReplCmd(workdir, bldr, testc),
+			newCheckCmd(testc, diags),
+			newLintCmd(testc, diags),
+			diags.wrap(newDocCmd(bldr)),
+			newFmtCmd(workdir),
+			diags.wrap(newGraphCmd(goc)),
 			newOSArchCmd(),
 		},
 	}
@@ -47,21 +61,6 @@ var versionCmd = &cli.Command{
 	},
 }
 
-var upgradeCmd = &cli.Command{
-	Name:  "upgrade",
-	Usage: "Upgrade to newest Nevalang version",
-	Action: func(cliCtx *cli.Context) error {
-		curlCmd := "curl -sSL https://raw.githubusercontent.com/nevalang/neva/main/scripts/install.sh | bash"
-		err := exec.CommandContext(cliCtx.Context, curlCmd).Run()
-		if err != nil {
-			fmt.Println("Upgrading Nevalang failed :" + err.Error())
-		} else {
-			fmt.Println("Upgrading Nevalang completed. Upgraded to version: " + pkg.Version)
-		}
-		return nil
-	},
-}
-
 func newGetCmd(workdir string, bldr builder.Builder) *cli.Command {
 	return &cli.Command{
 		Name:      "get",
@@ -79,7 +78,7 @@ func newGetCmd(workdir string, bldr builder.Builder) *cli.Command {
 			path := cliCtx.Args().Get(0)
 			version := cliCtx.Args().Get(1)
 
-			installedPath, err := bldr.Get(workdir, path, version)
+			installedPath, err := bldr.Get(cliCtx.Context, workdir, path, version)
 			if err != nil {
 				return fmt.Errorf("failed to get dependency: %w", err)
 			}
@@ -0,0 +1,341 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/nevalang/neva/internal/builder"
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	"github.com/nevalang/neva/pkg"
+)
+
+// diagnostics prints compiler errors in the format chosen by --error-format flag.
+type diagnostics struct {
+	workdir string
+	bldr    builder.Builder
+}
+
+func (d diagnostics) flag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "error-format",
+		Usage: "Format of compiler errors (options: text, short, json, sarif)",
+		Value: "text",
+		Action: func(ctx *cli.Context, s string) error {
+			switch s {
+			case "text", "short", "json", "sarif":
+				return nil
+			}
+			return fmt.Errorf("Unknown error format %s", s)
+		},
+	}
+}
+
+// wrap adds --error-format flag to the command and prints compiler errors returned by its action.
+// Other errors are returned as is.
+func (d diagnostics) wrap(cmd *cli.Command) *cli.Command {
+	cmd.Flags = append(cmd.Flags, d.flag())
+
+	action := cmd.Action
+	cmd.Action = func(cliCtx *cli.Context) error {
+		err := action(cliCtx)
+
+		var compilerErr *compiler.Error
+		if !errors.As(err, &compilerErr) {
+			return err
+		}
+
+		return d.print(cliCtx, compilerErr)
+	}
+
+	return cmd
+}
+
+// print writes text errors to stderr and machine-readable ones to stdout.
+func (d diagnostics) print(cliCtx *cli.Context, err *compiler.Error) error {
+	errs := err.Errors()
+
+	// entry module is located relative to the package the command is executed for
+	wd := filepath.Join(d.workdir, cliCtx.Args().First())
+
+	switch cliCtx.String("error-format") {
+	case "short":
+		fmt.Fprintln(os.Stderr, err.Error())
+		return nil
+	case "json":
+		return writeJSONDiagnostics(os.Stdout, errs)
+	case "sarif":
+		return writeSARIFDiagnostics(os.Stdout, errs)
+	}
+
+	return writeTextDiagnostics(os.Stderr, errs, func(location core.Location) ([]byte, error) {
+		path, err := d.bldr.SourcePath(wd, location)
+		if err != nil {
+			return nil, err
+		}
+		return os.ReadFile(path)
+	})
+}
+
+// writeTextDiagnostics renders errors together with source code lines they point to.
+// Source code is optional, errors are rendered without snippets if it can't be read.
+func writeTextDiagnostics(
+	w io.Writer,
+	errs []*compiler.Error,
+	readSource func(core.Location) ([]byte, error),
+) error {
+	sources := map[core.Location][]string{}
+	sourceLines := func(location core.Location) []string {
+		if lines, ok := sources[location]; ok {
+			return lines
+		}
+		var lines []string
+		if location.Filename != "" {
+			if content, err := readSource(location); err == nil {
+				lines = strings.Split(string(content), "\n")
+			}
+		}
+		sources[location] = lines
+		return lines
+	}
+
+	var b strings.Builder
+	for i, err := range errs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "%v: %s\n", err.Severity, err.Message)
+		writeSnippet(&b, err.Meta, sourceLines)
+
+		for _, note := range err.Notes {
+			fmt.Fprintf(&b, "note: %s\n", note.Message)
+			writeSnippet(&b, note.Meta, sourceLines)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeSnippet writes location and source code line with the fragment between meta's start and stop underlined.
+func writeSnippet(b *strings.Builder, meta *core.Meta, sourceLines func(core.Location) []string) {
+	if meta == nil || meta.Start.Line == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, " --> %v:%v\n", meta.Location, meta.Start)
+
+	lines := sourceLines(meta.Location)
+	if meta.Start.Line > len(lines) {
+		return
+	}
+
+	line := []rune(lines[meta.Start.Line-1])
+
+	start := min(meta.Start.Column, len(line))
+	stop := start + 1
+	if meta.Stop.Line == meta.Start.Line && meta.Stop.Column > start {
+		stop = meta.Stop.Column
+	} else if meta.Stop.Line > meta.Start.Line {
+		stop = len([]rune(strings.TrimRight(string(line), " \t\r"))) // multi-line fragment, underline till the end of line
+	}
+	stop = max(stop, start+1)
+
+	// tabs are expanded so underline is aligned with the code
+	var code, underline strings.Builder
+	for i, r := range line {
+		text := string(r)
+		if r == '\t' {
+			text = "    "
+		}
+		code.WriteString(text)
+
+		switch {
+		case i < start:
+			underline.WriteString(strings.Repeat(" ", len([]rune(text))))
+		case i < stop:
+			underline.WriteString(strings.Repeat("^", len([]rune(text))))
+		}
+	}
+	if stop > len(line) {
+		underline.WriteString(strings.Repeat("^", stop-max(start, len(line))))
+	}
+
+	lineNum := strconv.Itoa(meta.Start.Line)
+	gutter := strings.Repeat(" ", len(lineNum))
+
+	fmt.Fprintf(b, "%s |\n", gutter)
+	fmt.Fprintf(b, "%s | %s\n", lineNum, strings.TrimRight(code.String(), " \r"))
+	fmt.Fprintf(b, "%s | %s\n", gutter, underline.String())
+}
+
+type jsonDiagnostic struct {
+	Severity string        `json:"severity"`
+	Message  string        `json:"message"`
+	Location *jsonLocation `json:"location,omitempty"`
+	Notes    []jsonNote    `json:"notes,omitempty"`
+}
+
+type jsonNote struct {
+	Message  string        `json:"message"`
+	Location *jsonLocation `json:"location,omitempty"`
+}
+
+type jsonLocation struct {
+	File  string        `json:"file"`
+	Start core.Position `json:"start"`
+	Stop  core.Position `json:"stop"`
+}
+
+func newJSONLocation(meta *core.Meta) *jsonLocation {
+	if meta == nil || meta.Start.Line == 0 {
+		return nil
+	}
+	return &jsonLocation{
+		File:  meta.Location.String(),
+		Start: meta.Start,
+		Stop:  meta.Stop,
+	}
+}
+
+func writeJSONDiagnostics(w io.Writer, errs []*compiler.Error) error {
+	result := make([]jsonDiagnostic, 0, len(errs))
+	for _, err := range errs {
+		diagnostic := jsonDiagnostic{
+			Severity: err.Severity.String(),
+			Message:  err.Message,
+			Location: newJSONLocation(err.Meta),
+		}
+		for _, note := range err.Notes {
+			diagnostic.Notes = append(diagnostic.Notes, jsonNote{
+				Message:  note.Message,
+				Location: newJSONLocation(note.Meta),
+			})
+		}
+		result = append(result, diagnostic)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// sarif types describe subset of the SARIF 2.1.0 format used by code scanning tools.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string `json:"name"`
+		Version        string `json:"version"`
+		InformationURI string `json:"informationUri"`
+	}
+	sarifResult struct {
+		Level            string          `json:"level"`
+		Message          sarifMessage    `json:"message"`
+		Locations        []sarifLocation `json:"locations,omitempty"`
+		RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifLocation struct {
+		ID               *int                  `json:"id,omitempty"`
+		Message          *sarifMessage         `json:"message,omitempty"`
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndLine     int `json:"endLine,omitempty"`
+		EndColumn   int `json:"endColumn,omitempty"`
+	}
+)
+
+// newSARIFLocation converts meta to SARIF location, columns in SARIF are 1-based.
+func newSARIFLocation(meta *core.Meta) (sarifLocation, bool) {
+	if meta == nil || meta.Start.Line == 0 {
+		return sarifLocation{}, false
+	}
+
+	region := sarifRegion{
+		StartLine:   meta.Start.Line,
+		StartColumn: meta.Start.Column + 1,
+	}
+	if meta.Stop.Line != 0 {
+		region.EndLine = meta.Stop.Line
+		region.EndColumn = meta.Stop.Column + 1
+	}
+
+	return sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(meta.Location.String())},
+			Region:           region,
+		},
+	}, true
+}
+
+func writeSARIFDiagnostics(w io.Writer, errs []*compiler.Error) error {
+	results := make([]sarifResult, 0, len(errs))
+	for _, err := range errs {
+		result := sarifResult{
+			Level:   err.Severity.String(),
+			Message: sarifMessage{Text: err.Message},
+		}
+		if location, ok := newSARIFLocation(err.Meta); ok {
+			result.Locations = []sarifLocation{location}
+		}
+		for i, note := range err.Notes {
+			location, ok := newSARIFLocation(note.Meta)
+			if !ok {
+				continue
+			}
+			location.ID = compiler.Pointer(i)
+			location.Message = &sarifMessage{Text: note.Message}
+			result.RelatedLocations = append(result.RelatedLocations, location)
+		}
+		results = append(results, result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{
+				Driver: sarifDriver{
+					Name:           "neva",
+					Version:        pkg.Version,
+					InformationURI: "https://github.com/nevalang/neva",
+				},
+			},
+			Results: results,
+		}},
+	})
+}
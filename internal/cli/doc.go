@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/nevalang/neva/internal/builder"
+	"github.com/nevalang/neva/internal/compiler/docgen"
+	"github.com/nevalang/neva/internal/compiler/parser"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+func newDocCmd(bldr builder.Builder) *cli.Command {
+	return &cli.Command{
+		Name:  "doc",
+		Usage: "Generate documentation of the module from doc comments",
+		Args:  true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Format of generated files (options: md, html)",
+				Value: string(docgen.FormatMarkdown),
+				Action: func(ctx *cli.Context, s string) error {
+					switch docgen.Format(s) {
+					case docgen.FormatMarkdown, docgen.FormatHTML:
+						return nil
+					}
+					return fmt.Errorf("Unknown doc format %s", s)
+				},
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "Directory to write documentation to",
+				Value: "doc",
+			},
+			&cli.BoolFlag{
+				Name:  "std",
+				Usage: "Generate documentation of the standard library instead",
+			},
+		},
+		ArgsUsage: "Provide path to the module. Defaults to current directory",
+		Action: func(cliCtx *cli.Context) error {
+			path := cliCtx.Args().First()
+			if path == "" {
+				path = "."
+			}
+
+			modRef := core.ModuleRef{Path: "@"}
+			if cliCtx.Bool("std") {
+				path = bldr.StdlibPath()
+				modRef = core.ModuleRef{Path: "std"}
+			}
+
+			raw, modRoot, err := bldr.LoadModuleByPath(cliCtx.Context, path)
+			if err != nil {
+				return err
+			}
+
+			pkgs, compilerErr := parser.New().ParsePackages(modRef, raw.Packages)
+			if compilerErr != nil {
+				return compilerErr
+			}
+
+			title := filepath.Base(modRoot)
+			if modRef.Path == "std" {
+				title = "std"
+			}
+
+			files, err := docgen.Generate(docgen.Module{
+				Title: title,
+				Name:  modRef.Path,
+				Module: src.Module{
+					Manifest: raw.Manifest,
+					Packages: pkgs,
+				},
+			}, docgen.Format(cliCtx.String("format")))
+			if err != nil {
+				return err
+			}
+
+			output := cliCtx.String("output")
+			for name, content := range files {
+				filePath := filepath.Join(output, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+					return err
+				}
+				if err := os.WriteFile(filePath, content, 0644); err != nil {
+					return err
+				}
+			}
+
+			fmt.Printf("Documentation is written to %s\n", filepath.Join(output, "index."+cliCtx.String("format")))
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/nevalang/neva/internal/compiler/formatter"
+)
+
+func newFmtCmd(workdir string) *cli.Command {
+	return &cli.Command{
+		Name:  "fmt",
+		Usage: "Format source code files",
+		Args:  true,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "check",
+				Usage: "Don't write files, list unformatted ones and exit with non-zero code if there are any",
+			},
+		},
+		ArgsUsage: "Provide paths to files or directories, directories are formatted recursively. Defaults to current directory",
+		Action: func(cliCtx *cli.Context) error {
+			args := cliCtx.Args().Slice()
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+
+			files, err := nevaFiles(workdir, args)
+			if err != nil {
+				return err
+			}
+
+			check := cliCtx.Bool("check")
+			unformatted := false
+
+			for _, file := range files {
+				changed, err := formatFile(file, !check)
+				if err != nil {
+					return err
+				}
+				if changed {
+					unformatted = true
+					rel, err := filepath.Rel(workdir, file)
+					if err != nil {
+						rel = file
+					}
+					fmt.Println(rel)
+				}
+			}
+
+			if check && unformatted {
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+// formatFile formats the file and reports whether its content differs from the formatted one.
+// File is only rewritten if write is true.
+func formatFile(path string, write bool) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	formatted, compilerErr := formatter.Format(content)
+	if compilerErr != nil {
+		return false, fmt.Errorf("%v:%v: %v", path, compilerErr.Meta.Start, compilerErr.Message)
+	}
+
+	if bytes.Equal(content, formatted) {
+		return false, nil
+	}
+
+	if !write {
+		return true, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	return true, os.WriteFile(path, formatted, info.Mode().Perm())
+}
+
+// nevaFiles returns paths of all .neva files in given files and directories.
+// Hidden directories are skipped.
+func nevaFiles(workdir string, args []string) ([]string, error) {
+	var result []string
+	seen := map[string]bool{}
+
+	for _, arg := range args {
+		root := arg
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(workdir, root)
+		}
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if d.IsDir() || filepath.Ext(path) != ".neva" || seen[path] {
+				return nil
+			}
+			seen[path] = true
+			result = append(result, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/visualizer"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+func newGraphCmd(cmplr compiler.Compiler) *cli.Command {
+	return &cli.Command{
+		Name:  "graph",
+		Usage: "Visualize network of a component as it's written in source code",
+		Args:  true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "component",
+				Usage: "Name of the component to visualize",
+				Value: "Main",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format (options: dot, mermaid)",
+				Value: "dot",
+				Action: func(ctx *cli.Context, s string) error {
+					switch s {
+					case "dot", "mermaid":
+						return nil
+					}
+					return fmt.Errorf("Unknown format %s", s)
+				},
+			},
+			&cli.IntFlag{
+				Name:  "depth",
+				Usage: "How many levels of sub-components to drill down into",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "File to write graph to. Graph is written to stdout if not set",
+			},
+		},
+		ArgsUsage: "Provide path to the package with the component",
+		Action: func(cliCtx *cli.Context) error {
+			pkgPath, err := mainPkgPathFromArgs(cliCtx)
+			if err != nil {
+				return err
+			}
+
+			build, pkgName, err := cmplr.Analyze(cliCtx.Context, pkgPath)
+			if err != nil {
+				return err
+			}
+
+			graph, err := visualizer.Build(
+				build,
+				pkgName,
+				cliCtx.String("component"),
+				cliCtx.Int("depth"),
+			)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			if cliCtx.IsSet("output") {
+				f, err := os.Create(cliCtx.String("output"))
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+
+			if cliCtx.String("format") == "mermaid" {
+				return visualizer.EncodeMermaid(w, graph)
+			}
+
+			return visualizer.EncodeDOT(w, graph)
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/linter"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+func newLintCmd(cmplr compiler.Compiler, diags diagnostics) *cli.Command {
+	return &cli.Command{
+		Name:  "lint",
+		Usage: "Report suspicious code that is valid but most likely wrong",
+		Args:  true,
+		Flags: []cli.Flag{
+			diags.flag(),
+			&cli.BoolFlag{
+				Name:  "rules",
+				Usage: "List available lint rules and exit",
+			},
+		},
+		ArgsUsage: "Provide paths to packages, every package of their modules is linted. Defaults to current directory",
+		Action: func(cliCtx *cli.Context) error {
+			if cliCtx.Bool("rules") {
+				for _, rule := range linter.Rules {
+					fmt.Fprintf(os.Stdout, "%-20s %s\n", rule.Name, rule.Doc)
+				}
+				return nil
+			}
+
+			// linting makes sense only for programs without errors
+			var errs, warnings problems
+
+			for _, path := range modulePathsFromArgs(cliCtx) {
+				found, err := cmplr.Lint(cliCtx.Context, path, linter.New())
+				if err := errs.add(err); err != nil {
+					return err
+				}
+				if found != nil {
+					_ = warnings.add(found)
+				}
+			}
+
+			if len(errs.list) == 0 && len(warnings.list) == 0 {
+				return nil
+			}
+
+			if err := diags.print(cliCtx, compiler.Join(append(errs.list, warnings.list...)...)); err != nil {
+				return err
+			}
+
+			return cli.Exit("", 1)
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/nevalang/neva/internal/builder"
+)
+
+func newModCmd(workdir string, bldr builder.Builder) *cli.Command {
+	return &cli.Command{
+		Name:  "mod",
+		Usage: "Inspect dependencies of current module",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "graph",
+				Usage: "Print requirement graph, one requirement per line, including versions that are not selected",
+				Action: func(cliCtx *cli.Context) error {
+					graph, err := bldr.Graph(cliCtx.Context, workdir)
+					if err != nil {
+						return err
+					}
+
+					for _, mod := range graph.Modules() {
+						for _, req := range graph.Reqs[mod] {
+							fmt.Println(mod, req)
+						}
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:      "why",
+				Usage:     "Print the shortest chain of requirements from current module to the given one",
+				Args:      true,
+				ArgsUsage: "Provide paths of the modules",
+				Action: func(cliCtx *cli.Context) error {
+					if cliCtx.Args().Len() == 0 {
+						return fmt.Errorf("expected at least 1 argument, got 0")
+					}
+
+					graph, err := bldr.Graph(cliCtx.Context, workdir)
+					if err != nil {
+						return err
+					}
+
+					for i, path := range cliCtx.Args().Slice() {
+						if i > 0 {
+							fmt.Println()
+						}
+
+						fmt.Println("#", path)
+
+						chain, ok := graph.Why(path)
+						if !ok {
+							fmt.Println("(module is not used by current module)")
+							continue
+						}
+
+						for _, mod := range chain {
+							fmt.Println(mod)
+						}
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "verify",
+				Usage: "Check that contents of every dependency match the lock file",
+				Action: func(cliCtx *cli.Context) error {
+					if err := bldr.Verify(cliCtx.Context, workdir); err != nil {
+						return err
+					}
+
+					fmt.Println("all modules verified")
+
+					return nil
+				},
+			},
+		},
+	}
+}
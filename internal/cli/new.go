@@ -1,64 +1,234 @@
 package cli
 
 import (
+	"context"
+	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
-	"github.com/nevalang/neva/pkg"
+	git "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
 	cli "github.com/urfave/cli/v2"
+
+	"github.com/nevalang/neva/pkg"
 )
 
+// templates contains built-in project templates, one directory per template.
+//
+//go:embed templates
+var templates embed.FS
+
+const defaultTemplate = "hello"
+
 func newNewCmd(workdir string) *cli.Command {
 	return &cli.Command{
 		Name:  "new",
 		Usage: "Create new Nevalang project",
 		Args:  true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name: "template",
+				Usage: fmt.Sprintf(
+					"Built-in template (%s), path to local directory or git repository url",
+					strings.Join(builtinTemplates(), ", "),
+				),
+				Value: defaultTemplate,
+			},
+			&cli.StringFlag{
+				Name:  "module",
+				Usage: "Value of {{module}} in the template. Defaults to the project directory name",
+			},
+			&cli.StringFlag{
+				Name:  "author",
+				Usage: "Value of {{author}} in the template. Defaults to the user name from global git config",
+			},
+		},
+		ArgsUsage: "Provide path to the project directory. Defaults to the current directory",
 		Action: func(cCtx *cli.Context) error {
+			path := workdir
 			if pathArg := cCtx.Args().First(); pathArg != "" {
 				if err := os.Mkdir(pathArg, 0755); err != nil {
 					return err
 				}
-				return createNevaMod(pathArg)
+				path = pathArg
+			}
+
+			tmpl, cleanup, err := loadTemplate(cCtx.Context, cCtx.String("template"))
+			if err != nil {
+				return err
 			}
-			return createNevaMod(workdir)
+			defer cleanup()
+
+			vars := templateVars{
+				module: cCtx.String("module"),
+				author: cCtx.String("author"),
+			}
+			if vars.module == "" {
+				abs, err := filepath.Abs(path)
+				if err != nil {
+					return err
+				}
+				vars.module = filepath.Base(abs)
+			}
+			if !cCtx.IsSet("author") {
+				vars.author = gitUserName()
+			}
+
+			return createNevaMod(path, tmpl, vars)
 		},
 	}
 }
 
-func createNevaMod(path string) error {
-	// Create neva.yml file
-	nevaYmlContent := fmt.Sprintf("neva: %s", pkg.Version)
-	if err := os.WriteFile(
-		filepath.Join(path, "neva.yml"),
-		[]byte(nevaYmlContent),
-		0644,
-	); err != nil {
-		return err
+func builtinTemplates() []string {
+	entries, err := templates.ReadDir("templates")
+	if err != nil {
+		panic(err)
 	}
 
-	// Create src sub-directory
-	srcPath := filepath.Join(path, "src")
-	if err := os.Mkdir(srcPath, 0755); err != nil {
-		return err
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
 	}
 
-	// Create main.neva file
-	mainNevaContent := `import { fmt }
+	return names
+}
 
-def Main(start any) (stop any) {
-	fmt.Println
-	---
-	:start -> 'Hello, World!' -> println -> :stop
-}`
+// loadTemplate returns files of the template. Template is either built-in one,
+// a local directory or a git repository that is cloned into temporary directory until cleanup.
+func loadTemplate(ctx context.Context, name string) (fs.FS, func(), error) {
+	noop := func() {}
 
-	if err := os.WriteFile(
-		filepath.Join(srcPath, "main.neva"),
-		[]byte(mainNevaContent),
-		0644,
-	); err != nil {
+	if slices.Contains(builtinTemplates(), name) {
+		sub, err := fs.Sub(templates, "templates/"+name)
+		return sub, noop, err
+	}
+
+	if info, err := os.Stat(name); err == nil && info.IsDir() {
+		return os.DirFS(name), noop, nil
+	}
+
+	url, ok := gitTemplateURL(name)
+	if !ok {
+		return nil, nil, fmt.Errorf(
+			"template not found: %s, built-in templates are: %s",
+			name,
+			strings.Join(builtinTemplates(), ", "),
+		)
+	}
+
+	tmp, err := os.MkdirTemp("", "neva-template-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+
+	if _, err := git.PlainCloneContext(ctx, tmp, false, &git.CloneOptions{
+		URL:   url,
+		Depth: 1,
+	}); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("clone template %s: %w", url, err)
+	}
+
+	return os.DirFS(tmp), cleanup, nil
+}
+
+// gitTemplateURL returns url to clone the template from
+// if the name looks like a repository url or a module path like github.com/user/repo.
+func gitTemplateURL(name string) (string, bool) {
+	if strings.Contains(name, "://") || strings.HasPrefix(name, "git@") || strings.HasSuffix(name, ".git") {
+		return name, true
+	}
+
+	host, _, ok := strings.Cut(name, "/")
+	if ok && strings.Contains(host, ".") && host != "." && host != ".." {
+		return "https://" + name, true
+	}
+
+	return "", false
+}
+
+func gitUserName() string {
+	cfg, err := gitconfig.LoadConfig(gitconfig.GlobalScope)
+	if err != nil {
+		return ""
+	}
+	return cfg.User.Name
+}
+
+// templateVars are substituted into paths and contents of template files.
+type templateVars struct {
+	module string
+	author string
+}
+
+func (v templateVars) replacer() *strings.Replacer {
+	return strings.NewReplacer(
+		"{{module}}", v.module,
+		"{{author}}", v.author,
+		"{{neva}}", pkg.Version,
+	)
+}
+
+// createNevaMod writes files of the template into the path.
+// Existing files are never overwritten, except for the manifest.
+// If template doesn't have a manifest, the default one is created.
+func createNevaMod(path string, tmpl fs.FS, vars templateVars) error {
+	replacer := vars.replacer()
+	hasManifest := false
+
+	err := fs.WalkDir(tmpl, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if name == ".git" {
+				return fs.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(path, replacer.Replace(name)), 0755)
+		}
+
+		content, err := fs.ReadFile(tmpl, name)
+		if err != nil {
+			return err
+		}
+
+		flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+		if name == "neva.yml" {
+			hasManifest = true
+			flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		}
+
+		dst := filepath.Join(path, replacer.Replace(name))
+
+		f, err := os.OpenFile(dst, flags, 0644)
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("file already exists: %s", dst)
+		} else if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = f.WriteString(replacer.Replace(string(content)))
+		return err
+	})
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if hasManifest {
+		return nil
+	}
+
+	return os.WriteFile(
+		filepath.Join(path, "neva.yml"),
+		[]byte(fmt.Sprintf("neva: %s", pkg.Version)),
+		0644,
+	)
 }
@@ -0,0 +1,833 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/nevalang/neva/internal/builder"
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/ir"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	"github.com/nevalang/neva/internal/interpreter"
+	"github.com/nevalang/neva/pkg"
+	pkgruntime "github.com/nevalang/neva/pkg/runtime"
+)
+
+const replHelp = `Enter one of the following:
+  import { fmt strings }     import packages
+  add Add<int>               declare node (redeclaring replaces it)
+  add -> println             connect nodes
+  1 -> add:left; 2 -> add:right
+                             send messages and print what nodes send from their outports
+  :show                      print the program
+  :undo                      revert last import, node or connection
+  :reset                     start from scratch
+  :quit                      exit
+Every line of sends runs the program from scratch, only nodes connected to receivers of the sends are used.
+`
+
+func newReplCmd(workdir string, bldr builder.Builder, cmplr compiler.Compiler) *cli.Command {
+	return &cli.Command{
+		Name:  "repl",
+		Usage: "Interactively declare nodes and connections and send messages to them",
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "idle",
+				Usage: "Finish evaluation when no messages are sent for this long",
+				Value: 200 * time.Millisecond,
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Finish evaluation if it runs longer than this",
+				Value: 10 * time.Second,
+			},
+		},
+		Action: func(cliCtx *cli.Context) error {
+			dir, err := os.MkdirTemp("", "neva-repl")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+
+			if err := writeReplManifest(workdir, bldr, dir); err != nil {
+				return err
+			}
+
+			repl := &replSession{
+				dir:     dir,
+				cmplr:   cmplr,
+				idle:    cliCtx.Duration("idle"),
+				timeout: cliCtx.Duration("timeout"),
+				out:     cliCtx.App.Writer,
+			}
+
+			return repl.loop(cliCtx.Context, cliCtx.App.Reader)
+		},
+	}
+}
+
+// writeReplManifest creates manifest of the module that REPL programs are compiled in.
+// Manifest of the current module is used if there's one so its dependencies can be imported.
+func writeReplManifest(workdir string, bldr builder.Builder, dir string) error {
+	manifest := []byte(fmt.Sprintf("neva: %s\n", pkg.Version))
+
+	if root, err := bldr.ModuleRoot(workdir); err == nil {
+		for _, name := range []string{"neva.yml", "neva.yaml"} {
+			if found, err := os.ReadFile(filepath.Join(root, name)); err == nil {
+				manifest = found
+				break
+			}
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "neva.yml"), manifest, 0644); err != nil {
+		return err
+	}
+
+	return os.MkdirAll(filepath.Join(dir, "main"), 0755)
+}
+
+// replState is everything user declared in the REPL.
+type replState struct {
+	imports []string
+	nodes   []replNode
+	conns   []string
+}
+
+type replNode struct {
+	name string
+	decl string // e.g. "add Add<int>"
+}
+
+func (s replState) clone() replState {
+	return replState{
+		imports: append([]string{}, s.imports...),
+		nodes:   append([]replNode{}, s.nodes...),
+		conns:   append([]string{}, s.conns...),
+	}
+}
+
+func (s replState) hasNode(name string) bool {
+	for _, node := range s.nodes {
+		if node.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+type replSession struct {
+	dir     string
+	cmplr   compiler.Compiler
+	idle    time.Duration
+	timeout time.Duration
+	out     io.Writer
+
+	state   replState
+	history []replState
+}
+
+func (r *replSession) loop(ctx context.Context, in io.Reader) error {
+	interactive := false
+	if f, ok := in.(*os.File); ok {
+		if info, err := f.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			interactive = true
+		}
+	}
+
+	if interactive {
+		fmt.Fprintln(r.out, "Nevalang REPL, type :help for help")
+	}
+
+	scanner := bufio.NewScanner(in)
+	for {
+		if interactive {
+			fmt.Fprint(r.out, "> ")
+		}
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+		if quit := r.eval(ctx, strings.TrimSpace(scanner.Text())); quit {
+			return nil
+		}
+	}
+}
+
+var (
+	replNodeRe   = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)\s+(\S.*)$`)
+	replIdentRe  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*`)
+	replImportRe = regexp.MustCompile(`^import\s*\{`) // nodes can have names like "importer"
+)
+
+// eval handles single line of input and reports whether REPL must exit.
+func (r *replSession) eval(ctx context.Context, line string) bool {
+	switch {
+	case line == "":
+	case strings.HasPrefix(line, ":"):
+		return r.command(line)
+	case replImportRe.MatchString(line):
+		next := r.state.clone()
+		for _, path := range strings.FieldsFunc(
+			strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "import")), "{}"),
+			func(r rune) bool { return r == ',' || r == ' ' || r == '\t' },
+		) {
+			if !slices.Contains(next.imports, path) {
+				next.imports = append(next.imports, path)
+			}
+		}
+		r.update(ctx, next)
+	case strings.Contains(line, "->"):
+		// connections start with nodes, everything else is sent to the program
+		if r.state.hasNode(replIdentRe.FindString(line)) {
+			next := r.state.clone()
+			next.conns = append(next.conns, line)
+			r.update(ctx, next)
+			return false
+		}
+		if err := r.send(ctx, splitReplSends(line)); err != nil {
+			r.printErr(err)
+		}
+	default:
+		match := replNodeRe.FindStringSubmatch(line)
+		if match == nil {
+			r.printErr(fmt.Errorf("unexpected input, type :help for help"))
+			return false
+		}
+		next := r.state.clone()
+		node := replNode{name: match[1], decl: line}
+		replaced := false
+		for i := range next.nodes {
+			if next.nodes[i].name == node.name {
+				next.nodes[i] = node
+				replaced = true
+			}
+		}
+		if !replaced {
+			next.nodes = append(next.nodes, node)
+		}
+		r.update(ctx, next)
+	}
+
+	return false
+}
+
+func (r *replSession) command(line string) bool {
+	switch line {
+	case ":quit", ":q", ":exit":
+		return true
+	case ":help":
+		fmt.Fprint(r.out, replHelp)
+	case ":show":
+		fmt.Fprint(r.out, r.state.String())
+	case ":undo":
+		if len(r.history) == 0 {
+			r.printErr(errors.New("nothing to undo"))
+			return false
+		}
+		r.state = r.history[len(r.history)-1]
+		r.history = r.history[:len(r.history)-1]
+	case ":reset":
+		r.state = replState{}
+		r.history = nil
+	default:
+		r.printErr(fmt.Errorf("unknown command %s, type :help for help", line))
+	}
+	return false
+}
+
+// update replaces state with the next one if the program is still valid.
+// Only syntax and node references are checked,
+// everything else is checked when messages are sent.
+func (r *replSession) update(ctx context.Context, next replState) {
+	if _, _, err := r.parse(ctx, next, nil, nil); err != nil {
+		r.printErr(err)
+		return
+	}
+	r.history = append(r.history, r.state)
+	r.state = next
+}
+
+// parse writes program to disk and parses it.
+// Returns main component and scope to resolve its nodes.
+func (r *replSession) parse(
+	ctx context.Context,
+	state replState,
+	sends []string,
+	discards []src.PortAddr,
+) (src.Component, src.Scope, error) {
+	if err := r.write(state, sends, discards); err != nil {
+		return src.Component{}, src.Scope{}, err
+	}
+
+	build, mainPkg, err := r.cmplr.Parse(ctx, filepath.Join(r.dir, "main"))
+	if err != nil {
+		return src.Component{}, src.Scope{}, err
+	}
+
+	scope := src.NewScope(build, core.Location{
+		ModRef:   build.EntryModRef,
+		Package:  mainPkg,
+		Filename: "main",
+	})
+
+	main, err := scope.GetComponent(core.EntityRef{Name: "Main"})
+	if err != nil {
+		return src.Component{}, src.Scope{}, err
+	}
+
+	for _, node := range state.nodes {
+		if _, _, err := scope.Entity(main.Nodes[node.name].EntityRef); err != nil {
+			return src.Component{}, src.Scope{}, fmt.Errorf("%s: %w", node.name, err)
+		}
+	}
+
+	return main, scope, nil
+}
+
+func (r *replSession) write(state replState, sends []string, discards []src.PortAddr) error {
+	return os.WriteFile(
+		filepath.Join(r.dir, "main", "main.neva"),
+		[]byte(state.source(sends, discards)),
+		0644,
+	)
+}
+
+// String returns imports, nodes and connections in the form they were entered.
+func (s replState) String() string {
+	var b strings.Builder
+	if len(s.imports) > 0 {
+		fmt.Fprintf(&b, "import { %s }\n", strings.Join(s.imports, " "))
+	}
+	for _, node := range s.nodes {
+		fmt.Fprintln(&b, node.decl)
+	}
+	for _, conn := range s.conns {
+		fmt.Fprintln(&b, conn)
+	}
+	return b.String()
+}
+
+const (
+	replHoldNode    = "__repl_hold"
+	replDiscardNode = "__repl_del"
+)
+
+// source returns program where sends are triggered by the start of the program
+// and unused outports are sent to discarding nodes.
+// Program never stops by itself, it's up to the REPL to finish it.
+func (s replState) source(sends []string, discards []src.PortAddr) string {
+	var b strings.Builder
+
+	if len(s.imports) > 0 {
+		b.WriteString("import {\n")
+		for _, path := range s.imports {
+			fmt.Fprintf(&b, "    %s\n", path)
+		}
+		b.WriteString("}\n\n")
+	}
+
+	b.WriteString("#extern(repl_hold)\ndef Hold(sig any) (res any)\n\n")
+
+	b.WriteString("def Main(start any) (stop any) {\n")
+	for _, node := range s.nodes {
+		fmt.Fprintf(&b, "    %s\n", node.decl)
+	}
+	fmt.Fprintf(&b, "    %s Hold\n", replHoldNode)
+	for i := range discards {
+		fmt.Fprintf(&b, "    %s%d Del\n", replDiscardNode, i)
+	}
+	b.WriteString("    ---\n")
+
+	// sends go first so they can be found in parsed network
+	b.WriteString("    :start -> [")
+	for _, send := range sends {
+		fmt.Fprintf(&b, "{ %s }, ", send)
+	}
+	fmt.Fprintf(&b, "%s]\n", replHoldNode)
+	for _, conn := range s.conns {
+		fmt.Fprintf(&b, "    %s\n", conn)
+	}
+	for i, addr := range discards {
+		fmt.Fprintf(&b, "    %s:%s -> %s%d\n", addr.Node, addr.Port, replDiscardNode, i)
+	}
+	fmt.Fprintf(&b, "    %s -> :stop\n", replHoldNode)
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// send runs the program with given sends
+// and prints messages that nodes send from their outports until program becomes idle.
+func (r *replSession) send(ctx context.Context, sends []string) error {
+	main, scope, err := r.parse(ctx, r.state, sends, nil)
+	if err != nil {
+		return err
+	}
+
+	state, discards, err := r.prepare(main, scope)
+	if err != nil {
+		return err
+	}
+
+	if err := r.write(state, sends, discards); err != nil {
+		return err
+	}
+
+	prog, err := r.cmplr.CompileToIR(ctx, filepath.Join(r.dir, "main"))
+	if err != nil {
+		return err
+	}
+
+	if len(prog.Natives) > 0 {
+		return r.sendBinary(ctx, state)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	observer := &replObserver{
+		labels:   replLabels(prog, state),
+		out:      r.out,
+		activity: time.Now(),
+	}
+
+	registry := pkgruntime.NewRegistry()
+	registry.MustRegister("repl_hold", pkgruntime.FuncCreatorFunc(replHold))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- interpreter.Run(
+			ctx,
+			prog,
+			pkgruntime.WithRegistry(registry),
+			pkgruntime.WithInterceptor(observer),
+		)
+	}()
+
+	ticker := time.NewTicker(r.idle / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out after %v", r.timeout)
+			}
+			return err
+		case <-ticker.C:
+			if observer.idleFor() >= r.idle {
+				cancel()
+				return <-done
+			}
+		}
+	}
+}
+
+// replHoldNative is native code that implements repl_hold for programs that are executed as binaries.
+const replHoldNative = `package native
+
+import (
+	"context"
+
+	"github.com/nevalang/neva/pkg/runtime"
+)
+
+func Registry() runtime.Registry {
+	return runtime.Registry{"repl_hold": runtime.FuncCreatorFunc(hold)}
+}
+
+func hold(io runtime.IO, _ runtime.Msg) (func(ctx context.Context), error) {
+	sigIn, err := io.In.Single("sig")
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) {
+		for {
+			if _, ok := sigIn.Receive(ctx); !ok {
+				return
+			}
+		}
+	}, nil
+}
+`
+
+// sendBinary is like send but for programs with native code, they can't be executed in-process.
+// Program that is already written to disk is compiled with tracing and executed in the directory of the binary,
+// messages are read from the trace file while it grows.
+func (r *replSession) sendBinary(ctx context.Context, state replState) error {
+	nativeDir := filepath.Join(r.dir, "native")
+	if err := os.MkdirAll(nativeDir, 0755); err != nil {
+		return err
+	}
+	defer os.RemoveAll(nativeDir)
+
+	if err := os.WriteFile(filepath.Join(nativeDir, "repl.go"), []byte(replHoldNative), 0644); err != nil {
+		return err
+	}
+
+	prog, err := r.cmplr.CompileToIR(ctx, filepath.Join(r.dir, "main"))
+	if err != nil {
+		return err
+	}
+
+	labels := map[string][]string{}
+	for addr, l := range replLabels(prog, state) {
+		labels[replTraceAddr(addr)] = l
+	}
+
+	bin, cleanup, err := buildBinary(r.cmplr, prog, true)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Dir = filepath.Dir(bin)
+	cmd.Stdout = r.out
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	trace := replTrace{
+		path:     filepath.Join(cmd.Dir, "trace.log"),
+		labels:   labels,
+		out:      r.out,
+		activity: time.Now(),
+	}
+
+	ticker := time.NewTicker(r.idle / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			trace.read()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out after %v", r.timeout)
+			}
+			return err
+		case <-ticker.C:
+			trace.read()
+			if time.Since(trace.activity) >= r.idle {
+				cancel()
+				<-done
+				trace.read()
+				return nil
+			}
+		}
+	}
+}
+
+// replTrace prints messages sent from outports of the nodes that are written to the trace file.
+type replTrace struct {
+	path     string
+	labels   map[string][]string // labels by senders in the format of trace file
+	out      io.Writer
+	offset   int
+	activity time.Time
+}
+
+// read prints lines that were appended to the trace file since the last read.
+func (t *replTrace) read() {
+	data, err := os.ReadFile(t.path)
+	if err != nil || len(data) <= t.offset {
+		return
+	}
+
+	end := bytes.LastIndexByte(data, '\n') + 1
+	if end <= t.offset {
+		return
+	}
+
+	t.activity = time.Now()
+
+	for _, line := range strings.Split(string(data[t.offset:end-1]), "\n") {
+		// e.g. sent | add:res | 3
+		parts := strings.SplitN(line, " | ", 3)
+		if len(parts) != 3 || parts[0] != "sent" {
+			continue
+		}
+		for _, label := range t.labels[parts[1]] {
+			fmt.Fprintf(t.out, "%s = %s\n", label, parts[2])
+		}
+	}
+
+	t.offset = end
+}
+
+// replTraceAddr formats sender the way it's written to the trace file.
+func replTraceAddr(addr ir.PortAddr) string {
+	path := strings.TrimSuffix(addr.Path, "/out")
+	if addr.IsArray {
+		return fmt.Sprintf("%s:%s[%d]", path, addr.Port, addr.Idx)
+	}
+	return fmt.Sprintf("%s:%s", path, addr.Port)
+}
+
+// prepare leaves only nodes and connections that are reachable from receivers of the sends
+// and returns outports of those nodes that must be discarded to make the program valid.
+func (r *replSession) prepare(main src.Component, scope src.Scope) (replState, []src.PortAddr, error) {
+	state := r.state.clone()
+
+	// first connection is the one with sends, then go the ones of the state in the same order
+	if len(main.Net) < len(state.conns)+1 {
+		return replState{}, nil, errors.New("internal error: unexpected number of connections")
+	}
+
+	components := map[string]string{} // node -> any node of the same component
+	var find func(string) string
+	find = func(node string) string {
+		parent, ok := components[node]
+		if !ok || parent == node {
+			return node
+		}
+		root := find(parent)
+		components[node] = root
+		return root
+	}
+
+	usedOutports := map[string]map[string]bool{}
+	connNodes := make([][]string, len(state.conns))
+	for i, conn := range main.Net[1 : len(state.conns)+1] {
+		conn.WalkPorts(func(addr src.PortAddr, out bool) {
+			if !state.hasNode(addr.Node) {
+				return
+			}
+			connNodes[i] = append(connNodes[i], addr.Node)
+			if out {
+				if usedOutports[addr.Node] == nil {
+					usedOutports[addr.Node] = map[string]bool{}
+				}
+				usedOutports[addr.Node][addr.Port] = true
+			}
+		})
+		for _, node := range connNodes[i] {
+			components[find(node)] = find(connNodes[i][0])
+		}
+	}
+
+	reachable := map[string]bool{}
+	main.Net[0].WalkPorts(func(addr src.PortAddr, out bool) {
+		if state.hasNode(addr.Node) {
+			reachable[find(addr.Node)] = true
+		}
+	})
+
+	state.conns = state.conns[:0]
+	for i, conn := range r.state.conns {
+		if len(connNodes[i]) > 0 && reachable[find(connNodes[i][0])] {
+			state.conns = append(state.conns, conn)
+		}
+	}
+
+	var discards []src.PortAddr
+
+	state.nodes = state.nodes[:0]
+	for _, node := range r.state.nodes {
+		if !reachable[find(node.name)] {
+			continue
+		}
+		state.nodes = append(state.nodes, node)
+
+		entity, _, err := scope.Entity(main.Nodes[node.name].EntityRef)
+		if err != nil {
+			return replState{}, nil, fmt.Errorf("%s: %w", node.name, err)
+		}
+		iface := entity.Component.Interface
+		if entity.Kind == src.InterfaceEntity {
+			iface = entity.Interface
+		}
+
+		used := usedOutports[node.name]
+		if used[""] && len(iface.IO.Out) == 1 {
+			continue // e.g. add -> println
+		}
+
+		outports := make([]string, 0, len(iface.IO.Out))
+		for name, port := range iface.IO.Out {
+			if !port.IsArray && !used[name] {
+				outports = append(outports, name)
+			}
+		}
+		sort.Strings(outports)
+
+		for _, port := range outports {
+			discards = append(discards, src.PortAddr{Node: node.name, Port: port})
+		}
+	}
+
+	return state, discards, nil
+}
+
+// replLabels maps senders of the program to outports of nodes that their messages go through.
+// Messages of composite nodes are sent by their sub-nodes, so connections are followed until final receivers.
+func replLabels(prog *ir.Program, state replState) map[ir.PortAddr][]string {
+	label := func(addr ir.PortAddr) (string, bool) {
+		node, ok := strings.CutSuffix(addr.Path, "/out")
+		if !ok || !state.hasNode(node) {
+			return "", false
+		}
+		if addr.IsArray {
+			return fmt.Sprintf("%s:%s[%d]", node, addr.Port, addr.Idx), true
+		}
+		return fmt.Sprintf("%s:%s", node, addr.Port), true
+	}
+
+	labels := map[ir.PortAddr][]string{}
+	for _, call := range prog.Funcs {
+		for _, sender := range call.IO.Out {
+			addr, visited := sender, map[ir.PortAddr]bool{}
+			for !visited[addr] {
+				visited[addr] = true
+				if l, ok := label(addr); ok {
+					labels[sender] = append(labels[sender], l)
+				}
+				next, ok := prog.Connections[addr]
+				if !ok {
+					break
+				}
+				addr = next
+			}
+		}
+	}
+
+	return labels
+}
+
+// replObserver prints messages sent from outports of the nodes and tracks activity of the program.
+type replObserver struct {
+	labels map[ir.PortAddr][]string
+	out    io.Writer
+
+	mu       sync.Mutex
+	activity time.Time
+}
+
+func (o *replObserver) Sent(addr pkgruntime.PortSlotAddr, msg pkgruntime.Msg) pkgruntime.Msg {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.activity = time.Now()
+
+	irAddr := ir.PortAddr{Path: addr.Path, Port: addr.Port}
+	if addr.Index != nil {
+		irAddr.Idx, irAddr.IsArray = *addr.Index, true
+	}
+
+	for _, label := range o.labels[irAddr] {
+		fmt.Fprintf(o.out, "%s = %s\n", label, formatReplMsg(msg))
+	}
+
+	return msg
+}
+
+func (o *replObserver) Received(_ pkgruntime.PortSlotAddr, msg pkgruntime.Msg) pkgruntime.Msg {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.activity = time.Now()
+	return msg
+}
+
+func (o *replObserver) idleFor() time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return time.Since(o.activity)
+}
+
+func formatReplMsg(msg pkgruntime.Msg) string {
+	if s, ok := msg.(pkgruntime.StringMsg); ok {
+		return fmt.Sprintf("%q", s.Str())
+	}
+	return fmt.Sprint(msg)
+}
+
+// replHold receives start signal and never sends anything,
+// it keeps the program running until REPL finishes it.
+func replHold(io pkgruntime.IO, _ pkgruntime.Msg) (func(ctx context.Context), error) {
+	sigIn, err := io.In.Single("sig")
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) {
+		for {
+			if _, ok := sigIn.Receive(ctx); !ok {
+				return
+			}
+		}
+	}, nil
+}
+
+// splitReplSends splits line by semicolons that are not inside of string literals.
+func splitReplSends(line string) []string {
+	var (
+		result []string
+		quote  rune
+		start  int
+	)
+	for i, c := range line {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ';':
+			result = append(result, strings.TrimSpace(line[start:i]))
+			start = i + 1
+		}
+	}
+	result = append(result, strings.TrimSpace(line[start:]))
+
+	sends := result[:0]
+	for _, send := range result {
+		if send != "" {
+			sends = append(sends, send)
+		}
+	}
+	return sends
+}
+
+func (r *replSession) printErr(err error) {
+	var compilerErr *compiler.Error
+	if errors.As(err, &compilerErr) {
+		for _, e := range compilerErr.Errors() {
+			fmt.Fprintf(r.out, "error: %s\n", e.Message)
+		}
+		return
+	}
+	fmt.Fprintf(r.out, "error: %v\n", err)
+}
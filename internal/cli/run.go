@@ -1,18 +1,27 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"time"
 
+	"github.com/nevalang/neva/internal/builder"
 	"github.com/nevalang/neva/internal/compiler"
 
 	cli "github.com/urfave/cli/v2"
 )
 
-func newRunCmd(workdir string, nativec compiler.Compiler) *cli.Command {
+func newRunCmd(
+	workdir string,
+	bldr builder.Builder,
+	diags diagnostics,
+	nativec compiler.Compiler,
+	wasic compiler.Compiler,
+) *cli.Command {
 	return &cli.Command{
 		Name:  "run",
 		Usage: "Build and run neva program from source code",
@@ -22,6 +31,22 @@ func newRunCmd(workdir string, nativec compiler.Compiler) *cli.Command {
 				Name:  "trace",
 				Usage: "Write trace information to file",
 			},
+			&cli.StringFlag{
+				Name:  "target",
+				Usage: "Where to run the program (options: native, wasi). With 'wasi' program is executed in sandboxed WebAssembly runtime that only has access to stdio.",
+				Value: "native",
+				Action: func(ctx *cli.Context, s string) error {
+					switch s {
+					case "native", "wasi":
+						return nil
+					}
+					return fmt.Errorf("Unknown target %s", s)
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "watch",
+				Usage: "Rebuild and restart the program when source code of the module changes",
+			},
 		},
 		ArgsUsage: "Provide path to main package",
 		Action: func(cliCtx *cli.Context) error {
@@ -46,29 +71,66 @@ func newRunCmd(workdir string, nativec compiler.Compiler) *cli.Command {
 				Trace:  trace,
 			}
 
-			if err := nativec.Compile(cliCtx.Context, input); err != nil {
-				return err
+			run := func(ctx context.Context) error {
+				if cliCtx.String("target") == "wasi" {
+					return compileAndRunWASI(ctx, workdir, wasic, input)
+				}
+				return compileAndRunNative(ctx, workdir, nativec, input)
 			}
 
-			fileName := "output"
-			if runtime.GOOS == "windows" {
-				fileName += ".exe"
+			if cliCtx.Bool("watch") {
+				return watch(cliCtx, filepath.Join(workdir, mainPkg), bldr, diags, run)
 			}
 
-			defer func() {
-				if err := os.Remove(filepath.Join(workdir, fileName)); err != nil {
-					fmt.Println("failed to remove output file:", err)
-				}
-			}()
+			return run(cliCtx.Context)
+		},
+	}
+}
 
-			pathToExec := filepath.Join(workdir, fileName)
+func compileAndRunWASI(ctx context.Context, workdir string, wasic compiler.Compiler, input compiler.CompilerInput) error {
+	if err := wasic.Compile(ctx, input); err != nil {
+		return err
+	}
 
-			cmd := exec.CommandContext(cliCtx.Context, pathToExec)
-			cmd.Stdin = os.Stdin
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
+	pathToWASM := filepath.Join(workdir, "output.wasm")
 
-			return cmd.Run()
-		},
+	defer func() {
+		if err := os.Remove(pathToWASM); err != nil {
+			fmt.Println("failed to remove output file:", err)
+		}
+	}()
+
+	return runWASI(ctx, pathToWASM, os.Stdin, os.Stdout, os.Stderr)
+}
+
+func compileAndRunNative(ctx context.Context, workdir string, nativec compiler.Compiler, input compiler.CompilerInput) error {
+	if err := nativec.Compile(ctx, input); err != nil {
+		return err
 	}
+
+	fileName := "output"
+	if runtime.GOOS == "windows" {
+		fileName += ".exe"
+	}
+
+	defer func() {
+		if err := os.Remove(filepath.Join(workdir, fileName)); err != nil {
+			fmt.Println("failed to remove output file:", err)
+		}
+	}()
+
+	pathToExec := filepath.Join(workdir, fileName)
+
+	cmd := exec.CommandContext(ctx, pathToExec)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// program is interrupted first so it can finish gracefully and only then killed
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = 5 * time.Second
+
+	return cmd.Run()
 }
@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	"github.com/nevalang/neva/internal/interpreter"
+	nevaruntime "github.com/nevalang/neva/internal/runtime"
+	pkgruntime "github.com/nevalang/neva/pkg/runtime"
+
+	cli "github.com/urfave/cli/v2"
+)
+
+func newTestCmd(workdir string, cmplr compiler.Compiler) *cli.Command {
+	return &cli.Command{
+		Name:  "test",
+		Usage: "Run test components from *_test.neva files",
+		Args:  true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "run",
+				Usage: "Run only tests with names matching the regular expression",
+			},
+			&cli.IntFlag{
+				Name:  "parallel",
+				Usage: "Maximum number of tests to run simultaneously",
+				Value: runtime.NumCPU(),
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Fail test if it runs longer than this",
+				Value: time.Minute,
+			},
+		},
+		ArgsUsage: "Provide paths to packages to test, path/... tests all packages inside. Defaults to ./...",
+		Action: func(cliCtx *cli.Context) error {
+			var filter *regexp.Regexp
+			if cliCtx.IsSet("run") {
+				var err error
+				filter, err = regexp.Compile(cliCtx.String("run"))
+				if err != nil {
+					return fmt.Errorf("invalid run flag: %w", err)
+				}
+			}
+
+			parallel := cliCtx.Int("parallel")
+			if parallel < 1 {
+				return fmt.Errorf("parallel must be positive: %d", parallel)
+			}
+
+			args := cliCtx.Args().Slice()
+			if len(args) == 0 {
+				args = []string{"./..."}
+			}
+
+			pkgs, err := testPkgPaths(workdir, args)
+			if err != nil {
+				return err
+			}
+
+			if len(pkgs) == 0 {
+				fmt.Println("no test files")
+				return nil
+			}
+
+			runner := testRunner{
+				filter:   filter,
+				parallel: parallel,
+				timeout:  cliCtx.Duration("timeout"),
+				out:      os.Stdout,
+			}
+
+			failed := false
+			for _, pkg := range pkgs {
+				ok, err := runner.runPkg(cliCtx.Context, cmplr, pkg)
+				if err != nil {
+					return err
+				}
+				failed = failed || !ok
+			}
+
+			if failed {
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+// testPkgPaths turns command arguments into paths of packages with test files.
+// Argument ending with "/..." means all packages in the directory and its subdirectories.
+func testPkgPaths(workdir string, args []string) ([]string, error) {
+	var result []string
+	seen := map[string]bool{}
+
+	for _, arg := range args {
+		if !strings.HasSuffix(arg, "...") {
+			path := strings.TrimSuffix(arg, "/")
+			if !seen[path] {
+				seen[path] = true
+				result = append(result, path)
+			}
+			continue
+		}
+
+		root := strings.TrimSuffix(strings.TrimSuffix(arg, "..."), "/")
+		if root == "" {
+			root = "."
+		}
+
+		var found []string
+		err := filepath.WalkDir(filepath.Join(workdir, root), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && strings.HasPrefix(d.Name(), ".") && d.Name() != "." {
+				return filepath.SkipDir
+			}
+			if d.IsDir() || !strings.HasSuffix(d.Name(), "_test.neva") {
+				return nil
+			}
+			rel, err := filepath.Rel(workdir, filepath.Dir(path))
+			if err != nil {
+				return err
+			}
+			if !seen[rel] {
+				seen[rel] = true
+				found = append(found, rel)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(found)
+		result = append(result, found...)
+	}
+
+	return result, nil
+}
+
+type testRunner struct {
+	filter   *regexp.Regexp
+	parallel int
+	timeout  time.Duration
+	out      io.Writer
+}
+
+type testResult struct {
+	test     compiler.Test
+	failures []testFailure
+	duration time.Duration
+}
+
+// testFailure is a failure of the test or benchmark at the node that reported it.
+type testFailure struct {
+	meta core.Meta
+	msg  string
+}
+
+func (f testFailure) Error() string {
+	return fmt.Sprintf("%v:%v: %s", f.meta.Location, f.meta.Start, f.msg)
+}
+
+// runPkg compiles and runs tests of the package and reports whether all of them passed.
+// Results are printed in the order of test names even though tests run in parallel.
+func (r testRunner) runPkg(ctx context.Context, cmplr compiler.Compiler, pkg string) (bool, error) {
+	start := time.Now()
+
+	tests, err := cmplr.CompileTests(ctx, pkg)
+	if err != nil {
+		return false, err
+	}
+
+	if r.filter != nil {
+		filtered := tests[:0]
+		for _, test := range tests {
+			if r.filter.MatchString(test.Name) {
+				filtered = append(filtered, test)
+			}
+		}
+		tests = filtered
+	}
+
+	// binaries are built one by one because the backend changes working directory
+	binaries := make([]string, len(tests))
+	for i, test := range tests {
+		if len(test.IR.Natives) == 0 {
+			continue
+		}
+		bin, cleanup, err := buildBinary(cmplr, test.IR, false)
+		if err != nil {
+			return false, err
+		}
+		defer cleanup()
+		binaries[i] = bin
+	}
+
+	results := make([]testResult, len(tests))
+	sem := make(chan struct{}, r.parallel)
+	var wg sync.WaitGroup
+
+	for i, test := range tests {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = r.runTest(ctx, test, binaries[i])
+		}()
+	}
+
+	wg.Wait()
+
+	passed := true
+	for _, result := range results {
+		status := "PASS"
+		if len(result.failures) > 0 {
+			status = "FAIL"
+			passed = false
+		}
+		fmt.Fprintf(r.out, "--- %s: %s (%.2fs)\n", status, result.test.Name, result.duration.Seconds())
+		for _, failure := range result.failures {
+			fmt.Fprintf(r.out, "    %v\n", failure)
+		}
+	}
+
+	status := "ok  "
+	if !passed {
+		status = "FAIL"
+	}
+	fmt.Fprintf(r.out, "%s\t%s\t%.3fs\n", status, pkg, time.Since(start).Seconds())
+
+	return passed, nil
+}
+
+// runTest runs the test in-process or, if bin is not empty, by executing the binary built for it.
+func (r testRunner) runTest(ctx context.Context, test compiler.Test, bin string) testResult {
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var failures []testFailure
+	if bin != "" {
+		failures = r.runTestBinary(ctx, test, bin)
+	} else {
+		failures = r.runTestInProcess(ctx, test)
+	}
+
+	return testResult{
+		test:     test,
+		failures: failures,
+		duration: time.Since(start),
+	}
+}
+
+func (r testRunner) runTestInProcess(ctx context.Context, test compiler.Test) []testFailure {
+	reporter := &testReporter{test: test}
+	stop := &stopInterceptor{}
+
+	err := interpreter.Run(
+		nevaruntime.WithTestReporter(ctx, reporter),
+		test.IR,
+		pkgruntime.WithInterceptor(stop),
+	)
+
+	failures := reporter.reported()
+	switch {
+	case err != nil:
+		failures = append(failures, testFailure{test.Meta, err.Error()})
+	case len(failures) == 0 && !stop.stopped():
+		failures = append(failures, testFailure{test.Meta, fmt.Sprintf("test timed out after %v", r.timeout)})
+	}
+
+	return failures
+}
+
+// runTestBinary is like runTestInProcess but for tests with native code.
+func (r testRunner) runTestBinary(ctx context.Context, test compiler.Test, bin string) []testFailure {
+	reported, err := runBinary(ctx, bin)
+
+	failures := make([]testFailure, 0, len(reported))
+	for _, failure := range reported {
+		failures = append(failures, testFailure{nodeMeta(test, failure.node), failure.msg})
+	}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) && len(failures) == 0:
+		failures = append(failures, testFailure{test.Meta, fmt.Sprintf("test timed out after %v", r.timeout)})
+	case err != nil && len(failures) == 0:
+		failures = append(failures, testFailure{test.Meta, err.Error()})
+	}
+
+	return failures
+}
+
+// nodeMeta returns meta of the test's node with the given path if it's known and meta of the test otherwise.
+func nodeMeta(test compiler.Test, node string) core.Meta {
+	if meta, ok := test.Nodes[node]; ok {
+		return meta
+	}
+	return test.Meta
+}
+
+// testReporter collects failures of a single test.
+type testReporter struct {
+	test     compiler.Test
+	mu       sync.Mutex
+	failures []testFailure
+}
+
+func (t *testReporter) Fail(node string, msg string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failures = append(t.failures, testFailure{nodeMeta(t.test, node), msg})
+}
+
+func (t *testReporter) reported() []testFailure {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]testFailure{}, t.failures...)
+}
+
+// stopInterceptor remembers whether program received message on its stop port.
+type stopInterceptor struct {
+	mu   sync.Mutex
+	done bool
+}
+
+func (s *stopInterceptor) Sent(_ pkgruntime.PortSlotAddr, msg pkgruntime.Msg) pkgruntime.Msg {
+	return msg
+}
+
+func (s *stopInterceptor) Received(addr pkgruntime.PortSlotAddr, msg pkgruntime.Msg) pkgruntime.Msg {
+	if addr.PortAddr == pkgruntime.StopAddr.PortAddr {
+		s.mu.Lock()
+		s.done = true
+		s.mu.Unlock()
+	}
+	return msg
+}
+
+func (s *stopInterceptor) stopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done
+}
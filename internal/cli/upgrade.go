@@ -0,0 +1,361 @@
+package cli
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/nevalang/neva/pkg"
+)
+
+const (
+	releasesURL      = "https://github.com/nevalang/neva/releases"
+	latestReleaseURL = "https://api.github.com/repos/nevalang/neva/releases/latest"
+	checksumsAsset   = "checksums.txt"
+)
+
+// Release consists of binaries named neva-<os>-<arch> (with .exe suffix on windows)
+// and checksums.txt in the format of sha256sum.
+// Checksums protect from corrupted downloads, they are not signed,
+// so authenticity of the release relies on HTTPS connection to GitHub.
+var upgradeCmd = &cli.Command{
+	Name:  "upgrade",
+	Usage: "Upgrade to newest Nevalang version",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "version",
+			Usage: "Install specific version instead of the latest one",
+		},
+		&cli.StringFlag{
+			Name:  "from",
+			Usage: "Install from local release directory or .tar.gz/.zip archive instead of downloading it",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Install even if this version is already installed",
+		},
+	},
+	Action: func(cliCtx *cli.Context) error {
+		if from := cliCtx.String("from"); from != "" {
+			release, err := openLocalRelease(from)
+			if err != nil {
+				return err
+			}
+
+			if err := upgrade(release); err != nil {
+				return fmt.Errorf("install from %s: %w", from, err)
+			}
+
+			fmt.Printf("Installed Nevalang from %s\n", from)
+
+			return nil
+		}
+
+		version := cliCtx.String("version")
+		if version == "" {
+			var err error
+			version, err = latestVersion(cliCtx.Context)
+			if err != nil {
+				return fmt.Errorf("resolve latest version: %w (use --from to install from local release)", err)
+			}
+		}
+		version = "v" + strings.TrimPrefix(version, "v")
+
+		if version == "v"+pkg.Version && !cliCtx.Bool("force") {
+			fmt.Println("Nevalang is up to date:", pkg.Version)
+			return nil
+		}
+
+		if err := upgrade(remoteRelease{ctx: cliCtx.Context, tag: version}); err != nil {
+			return fmt.Errorf("upgrade to %s: %w (use --from to install from local release)", version, err)
+		}
+
+		fmt.Printf("Upgraded Nevalang to %s\n", version)
+
+		return nil
+	},
+}
+
+// upgrade replaces running executable with verified binary from the release.
+func upgrade(release releaseSource) error {
+	bin, err := verifiedBinary(release)
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	exe, err = filepath.EvalSymlinks(exe)
+	if err != nil {
+		return err
+	}
+
+	if err := replaceExecutable(exe, bin); err != nil {
+		return fmt.Errorf("replace %s: %w", exe, err)
+	}
+
+	return nil
+}
+
+// releaseSource gives access to files of a single release.
+type releaseSource interface {
+	asset(name string) ([]byte, error)
+}
+
+var errAssetNotFound = errors.New("not found")
+
+func binaryAsset() string {
+	name := fmt.Sprintf("neva-%s-%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// verifiedBinary returns binary for the current platform after checking its checksum.
+func verifiedBinary(release releaseSource) ([]byte, error) {
+	checksums, err := release.asset(checksumsAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	name := binaryAsset()
+
+	expected, err := findChecksum(checksums, name)
+	if err != nil {
+		return nil, err
+	}
+
+	bin, err := release.asset(name)
+	if err != nil {
+		return nil, err
+	}
+
+	actual := sha256.Sum256(bin)
+	if hex.EncodeToString(actual[:]) != expected {
+		return nil, fmt.Errorf("%s: checksum mismatch", name)
+	}
+
+	return bin, nil
+}
+
+// findChecksum returns hex encoded sha256 of the file from output of sha256sum.
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// binary mode of sha256sum prefixes file name with '*'
+		if strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s: no checksum for %s", checksumsAsset, name)
+}
+
+// replaceExecutable writes new binary next to the old one and renames it over the old one,
+// so the executable is either old or new but never partially written.
+func replaceExecutable(exe string, bin []byte) error {
+	dir := filepath.Dir(exe)
+
+	tmp, err := os.CreateTemp(dir, ".neva-upgrade-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bin); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+
+	// running executable can't be replaced on windows, but it can be renamed
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), exe); err != nil {
+			_ = os.Rename(old, exe)
+			return err
+		}
+		return nil
+	}
+
+	return os.Rename(tmp.Name(), exe)
+}
+
+var httpClient = &http.Client{Timeout: 5 * time.Minute}
+
+func latestVersion(ctx context.Context) (string, error) {
+	data, err := download(ctx, latestReleaseURL)
+	if err != nil {
+		return "", err
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.Unmarshal(data, &release); err != nil {
+		return "", err
+	}
+	if release.TagName == "" {
+		return "", errors.New("latest release has no tag")
+	}
+
+	return release.TagName, nil
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", url, errAssetNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// remoteRelease downloads assets of the release from GitHub.
+type remoteRelease struct {
+	ctx context.Context
+	tag string
+}
+
+func (r remoteRelease) asset(name string) ([]byte, error) {
+	return download(r.ctx, fmt.Sprintf("%s/download/%s/%s", releasesURL, r.tag, name))
+}
+
+// localRelease holds assets of the release read from directory or archive, keyed by base names.
+type localRelease map[string][]byte
+
+func (l localRelease) asset(name string) ([]byte, error) {
+	data, ok := l[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, errAssetNotFound)
+	}
+	return data, nil
+}
+
+// openLocalRelease reads release from directory, .tar.gz (.tgz) or .zip archive.
+func openLocalRelease(src string) (localRelease, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := map[string]bool{
+		binaryAsset():  true,
+		checksumsAsset: true,
+	}
+	release := localRelease{}
+
+	switch {
+	case info.IsDir():
+		for name := range wanted {
+			data, err := os.ReadFile(filepath.Join(src, name))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			release[name] = data
+		}
+	case strings.HasSuffix(src, ".zip"):
+		r, err := zip.OpenReader(src)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		for _, f := range r.File {
+			if !wanted[path.Base(f.Name)] {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			release[path.Base(f.Name)] = data
+		}
+	case strings.HasSuffix(src, ".tar.gz") || strings.HasSuffix(src, ".tgz"):
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			if hdr.Typeflag != tar.TypeReg || !wanted[path.Base(hdr.Name)] {
+				continue
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			release[path.Base(hdr.Name)] = data
+		}
+	default:
+		return nil, fmt.Errorf("unsupported release archive: %s, expected directory, .tar.gz or .zip", src)
+	}
+
+	return release, nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// runWASI executes WASI module in embedded WebAssembly runtime.
+// Module has no access to file system, network or environment of the host, only to given stdio.
+func runWASI(ctx context.Context, path string, stdin io.Reader, stdout, stderr io.Writer) error {
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// without it module keeps running after ctx is done and program can't be stopped
+	rt := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+	defer rt.Close(ctx)
+
+	wasi_snapshot_preview1.MustInstantiate(ctx, rt)
+
+	cfg := wazero.NewModuleConfig().
+		WithArgs("output.wasm").
+		WithStdin(stdin).
+		WithStdout(stdout).
+		WithStderr(stderr).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithNanosleep(func(ns int64) {
+			// idle program sleeps in host, it has to wake up to notice that ctx is done
+			select {
+			case <-time.After(time.Duration(ns)):
+			case <-ctx.Done():
+			}
+		}).
+		WithRandSource(rand.Reader)
+
+	_, err = rt.InstantiateWithConfig(ctx, bb, cfg)
+
+	var exitErr *sys.ExitError
+	if errors.As(err, &exitErr) {
+		if exitErr.ExitCode() == 0 {
+			return nil
+		}
+		return fmt.Errorf("exit status %d", exitErr.ExitCode())
+	}
+
+	return err
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cli "github.com/urfave/cli/v2"
+
+	"github.com/nevalang/neva/internal/builder"
+	"github.com/nevalang/neva/internal/compiler"
+)
+
+// watchInterval is how often module is checked for changes.
+// Polling is used instead of fs events because it works the same on every platform.
+const watchInterval = 300 * time.Millisecond
+
+// watch runs the program and restarts it every time source code of the module changes.
+// Compiler errors are printed and the program is restarted after the next change.
+// It only returns when interrupted.
+func watch(
+	cliCtx *cli.Context,
+	pkgPath string,
+	bldr builder.Builder,
+	diags diagnostics,
+	run func(ctx context.Context) error,
+) error {
+	root, err := bldr.ModuleRoot(pkgPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cliCtx.Context, os.Interrupt)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "Watching for changes in %s, press Ctrl+C to stop\n", root)
+
+	snapshot, err := moduleSnapshot(root)
+	if err != nil {
+		return err
+	}
+
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() { done <- run(runCtx) }()
+
+		changed, err := waitForChanges(ctx, root, &snapshot, cancel, done, func(err error) {
+			report(cliCtx, diags, runCtx, err)
+		})
+		if !changed {
+			return err
+		}
+
+		fmt.Fprintln(os.Stderr, "Changes detected, restarting")
+	}
+}
+
+// waitForChanges polls the module until it changes or ctx is done.
+// Result of the program is reported as soon as it's done.
+// The run is cancelled and finished by the time it returns.
+func waitForChanges(
+	ctx context.Context,
+	root string,
+	snapshot *map[string]fileState,
+	cancel context.CancelFunc,
+	done chan error,
+	report func(error),
+) (bool, error) {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	running := true
+	finish := func() {
+		cancel()
+		if running {
+			<-done
+		}
+	}
+
+	for {
+		select {
+		case err := <-done:
+			running = false
+			report(err)
+			fmt.Fprintln(os.Stderr, "Waiting for changes")
+		case <-ctx.Done():
+			finish()
+			return false, nil
+		case <-ticker.C:
+			current, err := moduleSnapshot(root)
+			if err != nil {
+				finish()
+				return false, err
+			}
+			if maps.Equal(*snapshot, current) {
+				continue
+			}
+			*snapshot = current
+			finish()
+			return true, nil
+		}
+	}
+}
+
+// report prints result of the finished program run.
+// Errors caused by cancellation of the run are not reported.
+func report(cliCtx *cli.Context, diags diagnostics, runCtx context.Context, err error) {
+	if err == nil || runCtx.Err() != nil {
+		return
+	}
+
+	var compilerErr *compiler.Error
+	if errors.As(err, &compilerErr) {
+		if err := diags.print(cliCtx, compilerErr); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// moduleSnapshot returns state of every file of the module that affects the build.
+func moduleSnapshot(root string) (map[string]fileState, error) {
+	snapshot := map[string]fileState{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil // file is removed while walking, it's going to be noticed next time
+		}
+
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(path) != ".neva" && d.Name() != "neva.yml" && d.Name() != "neva.yaml" &&
+			!isNativeFile(root, path) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		snapshot[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+
+		return nil
+	})
+
+	return snapshot, err
+}
+
+// isNativeFile tells whether file is go code from the native directory in the module root.
+func isNativeFile(root, path string) bool {
+	return filepath.Dir(path) == filepath.Join(root, "native") &&
+		filepath.Ext(path) == ".go" &&
+		!strings.HasSuffix(path, "_test.go")
+}
@@ -7,8 +7,6 @@ import (
 	"errors"
 	"fmt"
 
-	"golang.org/x/exp/maps"
-
 	"github.com/nevalang/neva/internal/compiler"
 	src "github.com/nevalang/neva/internal/compiler/sourcecode"
 	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
@@ -21,6 +19,21 @@ var (
 
 type Analyzer struct {
 	resolver ts.Resolver
+	cache    compiler.PackageCache // optional
+	workers  int                   // non-positive means one per CPU
+}
+
+// WithCache returns analyzer that takes analyzed packages from the cache instead of analyzing them
+// and puts there the ones it had to analyze.
+func (a Analyzer) WithCache(cache compiler.PackageCache) compiler.Analyzer {
+	a.cache = cache
+	return a
+}
+
+// WithWorkers returns analyzer that analyzes at most n packages at a time.
+func (a Analyzer) WithWorkers(n int) Analyzer {
+	a.workers = n
+	return a
 }
 
 func (a Analyzer) AnalyzeExecutableBuild(build src.Build, mainPkgName string) (src.Build, *compiler.Error) {
@@ -48,36 +61,69 @@ func (a Analyzer) AnalyzeExecutableBuild(build src.Build, mainPkgName string) (s
 
 	scope := src.NewScope(build, meta.Location)
 
-	if err := a.mainSpecificPkgValidation(mainPkgName, entryMod, scope); err != nil {
-		return src.Build{}, compiler.Error{Meta: &meta}.Wrap(err)
-	}
+	// build is analyzed even if main package is invalid, so independent errors are reported together
+	mainErr := a.mainSpecificPkgValidation(mainPkgName, entryMod, scope)
 
-	analyzedBuild, err := a.AnalyzeBuild(build)
-	if err != nil {
+	analyzedBuild, buildErr := a.AnalyzeBuild(build)
+
+	if err := compiler.Join(mainErr, buildErr); err != nil {
 		return src.Build{}, compiler.Error{Meta: &meta}.Wrap(err)
 	}
 
 	return analyzedBuild, nil
 }
 
+// AnalyzeBuild analyzes every module of the build.
+// Packages are analyzed concurrently because each of them only reads the build.
+// Analysis doesn't stop at the first problem, all independent errors are joined.
 func (a Analyzer) AnalyzeBuild(build src.Build) (src.Build, *compiler.Error) {
-	analyzedMods := make(map[core.ModuleRef]src.Module, len(build.Modules))
+	var (
+		errs []*compiler.Error
+		jobs []pkgJob
+	)
 
 	for modRef, mod := range build.Modules {
 		if err := a.semverCheck(mod, modRef); err != nil {
-			return src.Build{}, err
+			errs = append(errs, err)
+			continue
 		}
 
-		analyzedPkgs, err := a.analyzeModule(modRef, build)
-		if err != nil {
-			return src.Build{}, err
+		if err := a.analyzeModule(modRef, build); err != nil {
+			errs = append(errs, err)
+			continue
 		}
 
+		if err := a.analyzeNative(modRef, build); err != nil {
+			errs = append(errs, err)
+		}
+
+		for pkgName := range mod.Packages {
+			jobs = append(jobs, pkgJob{modRef: modRef, pkgName: pkgName})
+		}
+	}
+
+	analyzedPkgs := make([]src.Package, len(jobs))
+	pkgErrs := make([]*compiler.Error, len(jobs))
+
+	compiler.Parallel(a.workers, len(jobs), func(i int) {
+		analyzedPkgs[i], pkgErrs[i] = a.analyzeModulePkg(jobs[i].modRef, jobs[i].pkgName, build)
+	})
+
+	if err := compiler.Join(append(errs, pkgErrs...)...); err != nil {
+		return src.Build{}, err
+	}
+
+	analyzedMods := make(map[core.ModuleRef]src.Module, len(build.Modules))
+	for modRef, mod := range build.Modules {
 		analyzedMods[modRef] = src.Module{
 			Manifest: mod.Manifest,
-			Packages: analyzedPkgs,
+			Packages: make(map[string]src.Package, len(mod.Packages)),
+			Native:   mod.Native,
 		}
 	}
+	for i, job := range jobs {
+		analyzedMods[job.modRef].Packages[job.pkgName] = analyzedPkgs[i]
+	}
 
 	return src.Build{
 		EntryModRef: build.EntryModRef,
@@ -85,9 +131,15 @@ func (a Analyzer) AnalyzeBuild(build src.Build) (src.Build, *compiler.Error) {
 	}, nil
 }
 
-func (a Analyzer) analyzeModule(modRef core.ModuleRef, build src.Build) (map[string]src.Package, *compiler.Error) {
+type pkgJob struct {
+	modRef  core.ModuleRef
+	pkgName string
+}
+
+// analyzeModule checks module-level rules, its packages are analyzed separately.
+func (a Analyzer) analyzeModule(modRef core.ModuleRef, build src.Build) *compiler.Error {
 	if modRef != build.EntryModRef && modRef.Version == "" {
-		return nil, &compiler.Error{
+		return &compiler.Error{
 			Message: "every dependency module must have version",
 			Meta: &core.Meta{
 				Location: core.Location{
@@ -97,42 +149,46 @@ func (a Analyzer) analyzeModule(modRef core.ModuleRef, build src.Build) (map[str
 		}
 	}
 
-	location := core.Location{ModRef: modRef}
-	mod := build.Modules[modRef]
-
-	if len(mod.Packages) == 0 {
-		return nil, &compiler.Error{
+	if len(build.Modules[modRef].Packages) == 0 {
+		return &compiler.Error{
 			Message: "module must contain at least one package",
 			Meta: &core.Meta{
-				Location: location,
+				Location: core.Location{ModRef: modRef},
 			},
 		}
 	}
 
-	pkgsCopy := make(map[string]src.Package, len(mod.Packages))
-	maps.Copy(pkgsCopy, mod.Packages)
+	return nil
+}
 
-	for pkgName, pkg := range pkgsCopy {
-		scope := src.NewScope(build, core.Location{
-			ModRef:  modRef,
-			Package: pkgName,
-		})
+func (a Analyzer) analyzeModulePkg(modRef core.ModuleRef, pkgName string, build src.Build) (src.Package, *compiler.Error) {
+	if a.cache != nil {
+		if cached, ok := a.cache.Get(modRef, pkgName); ok {
+			return cached, nil
+		}
+	}
 
-		resolvedPkg, err := a.analyzePkg(pkg, scope)
-		if err != nil {
-			return nil, compiler.Error{
-				Meta: &core.Meta{
-					Location: core.Location{
-						Package: pkgName,
-					},
+	scope := src.NewScope(build, core.Location{
+		ModRef:  modRef,
+		Package: pkgName,
+	})
+
+	resolvedPkg, err := a.analyzePkg(build.Modules[modRef].Packages[pkgName], scope)
+	if err != nil {
+		return nil, compiler.Error{
+			Meta: &core.Meta{
+				Location: core.Location{
+					Package: pkgName,
 				},
-			}.Wrap(err)
-		}
+			},
+		}.Wrap(err)
+	}
 
-		pkgsCopy[pkgName] = resolvedPkg
+	if a.cache != nil {
+		a.cache.Put(modRef, pkgName, resolvedPkg)
 	}
 
-	return pkgsCopy, nil
+	return resolvedPkg, nil
 }
 
 func (a Analyzer) analyzePkg(pkg src.Package, scope src.Scope) (src.Package, *compiler.Error) {
@@ -154,6 +210,8 @@ func (a Analyzer) analyzePkg(pkg src.Package, scope src.Scope) (src.Package, *co
 		}
 	}
 
+	var errs []*compiler.Error
+
 	for result := range pkg.Entities() {
 		relocatedScope := scope.Relocate(core.Location{
 			ModRef:   scope.Location().ModRef,
@@ -161,16 +219,22 @@ func (a Analyzer) analyzePkg(pkg src.Package, scope src.Scope) (src.Package, *co
 			Filename: result.FileName,
 		})
 
+		// entities are analyzed independently so we continue after failed one
 		analyzedEntity, err := a.analyzeEntity(result.Entity, relocatedScope)
 		if err != nil {
-			return nil, compiler.Error{
+			errs = append(errs, compiler.Error{
 				Meta: result.Entity.Meta(),
-			}.Wrap(err)
+			}.Wrap(err))
+			continue
 		}
 
 		analyzedFiles[result.FileName].Entities[result.EntityName] = analyzedEntity
 	}
 
+	if err := compiler.Join(errs...); err != nil {
+		return nil, err
+	}
+
 	return analyzedFiles, nil
 }
 
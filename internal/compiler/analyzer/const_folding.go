@@ -0,0 +1,399 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nevalang/neva/internal/compiler"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	ts "github.com/nevalang/neva/internal/compiler/sourcecode/typesystem"
+)
+
+// foldBinarySender evaluates binary expression at compile time
+// if both of its (analyzed) operands are constants of primitive types.
+// It returns nil if expression cannot be folded.
+func (a Analyzer) foldBinarySender(
+	binary src.Binary,
+	left src.ConnectionSender,
+	leftType ts.Expr,
+	right src.ConnectionSender,
+	rightType ts.Expr,
+	resultType ts.Expr,
+	scope src.Scope,
+) (*src.ConnectionSender, *compiler.Error) {
+	leftLit, ok := a.getFoldableLiteral(left, leftType, scope)
+	if !ok {
+		return nil, nil
+	}
+
+	rightLit, ok := a.getFoldableLiteral(right, rightType, scope)
+	if !ok {
+		return nil, nil
+	}
+
+	result, err := a.evalBinary(binary, *leftLit, *rightLit)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.newFoldedSender(result, resultType, binary.Meta), nil
+}
+
+// foldUnarySender evaluates unary expression at compile time
+// if its (analyzed) operand is a constant of primitive type.
+// It returns nil if expression cannot be folded.
+func (a Analyzer) foldUnarySender(
+	unary src.Unary,
+	operand src.ConnectionSender,
+	operandType ts.Expr,
+	scope src.Scope,
+) (*src.ConnectionSender, *compiler.Error) {
+	lit, ok := a.getFoldableLiteral(operand, operandType, scope)
+	if !ok {
+		return nil, nil
+	}
+
+	result, err := a.evalUnary(unary, *lit)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.newFoldedSender(result, operandType, unary.Meta), nil
+}
+
+// foldTernarySender replaces ternary expression with one of its branches
+// if condition and both branches are constants of primitive types.
+// It returns nil if expression cannot be folded.
+func (a Analyzer) foldTernarySender(
+	ternary src.Ternary,
+	cond src.ConnectionSender,
+	condType ts.Expr,
+	left src.ConnectionSender,
+	leftType ts.Expr,
+	right src.ConnectionSender,
+	rightType ts.Expr,
+	scope src.Scope,
+) (*src.ConnectionSender, *ts.Expr) {
+	condLit, ok := a.getFoldableLiteral(cond, condType, scope)
+	if !ok || condLit.Bool == nil {
+		return nil, nil
+	}
+
+	leftLit, ok := a.getFoldableLiteral(left, leftType, scope)
+	if !ok {
+		return nil, nil
+	}
+
+	rightLit, ok := a.getFoldableLiteral(right, rightType, scope)
+	if !ok {
+		return nil, nil
+	}
+
+	if *condLit.Bool {
+		return a.newFoldedSender(*leftLit, leftType, ternary.Meta), &leftType
+	}
+
+	return a.newFoldedSender(*rightLit, rightType, ternary.Meta), &rightType
+}
+
+func (Analyzer) newFoldedSender(
+	lit src.MsgLiteral,
+	typeExpr ts.Expr,
+	meta core.Meta,
+) *src.ConnectionSender {
+	lit.Meta = meta
+	return &src.ConnectionSender{
+		Const: &src.Const{
+			TypeExpr: typeExpr,
+			Value: src.ConstValue{
+				Message: &lit,
+			},
+			Meta: meta,
+		},
+		Meta: meta,
+	}
+}
+
+// getFoldableLiteral returns message literal for constant sender
+// (either literal or reference to constant, possibly to another reference).
+// Only bool, int, float and string constants can be folded.
+func (a Analyzer) getFoldableLiteral(
+	sender src.ConnectionSender,
+	resolvedType ts.Expr,
+	scope src.Scope,
+) (*src.MsgLiteral, bool) {
+	if sender.Const == nil || resolvedType.Inst == nil {
+		return nil, false
+	}
+
+	typeName := resolvedType.Inst.Ref.String()
+	switch typeName {
+	case "bool", "int", "float", "string":
+	default:
+		return nil, false
+	}
+
+	value := sender.Const.Value
+	for value.Ref != nil {
+		entity, location, err := scope.Entity(*value.Ref)
+		if err != nil || entity.Kind != src.ConstEntity {
+			return nil, false
+		}
+		scope = scope.Relocate(location)
+		value = entity.Const.Value
+	}
+
+	msg := value.Message
+	if msg == nil {
+		return nil, false
+	}
+
+	switch typeName {
+	case "bool":
+		if msg.Bool == nil {
+			return nil, false
+		}
+		return &src.MsgLiteral{Bool: msg.Bool}, true
+	case "int":
+		if msg.Int == nil {
+			return nil, false
+		}
+		return &src.MsgLiteral{Int: msg.Int}, true
+	case "float":
+		// float constants are allowed to have integer literals
+		if msg.Int != nil {
+			return &src.MsgLiteral{Float: compiler.Pointer(float64(*msg.Int))}, true
+		}
+		if msg.Float == nil {
+			return nil, false
+		}
+		return &src.MsgLiteral{Float: msg.Float}, true
+	default:
+		if msg.Str == nil {
+			return nil, false
+		}
+		return &src.MsgLiteral{Str: msg.Str}, true
+	}
+}
+
+// evalBinary follows semantics of the runtime functions
+// that implement binary operators.
+func (a Analyzer) evalBinary(
+	binary src.Binary,
+	left src.MsgLiteral,
+	right src.MsgLiteral,
+) (src.MsgLiteral, *compiler.Error) {
+	// equality of messages with different types is false, like in runtime
+	if binary.Operator == src.EqOp || binary.Operator == src.NeOp {
+		eq := literalsEqual(left, right)
+		if binary.Operator == src.NeOp {
+			eq = !eq
+		}
+		return src.MsgLiteral{Bool: &eq}, nil
+	}
+
+	switch {
+	case left.Int != nil && right.Int != nil:
+		return a.evalIntBinary(binary, *left.Int, *right.Int)
+	case left.Float != nil && right.Float != nil:
+		return a.evalFloatBinary(binary, *left.Float, *right.Float)
+	case left.Str != nil && right.Str != nil:
+		return a.evalStringBinary(binary, *left.Str, *right.Str)
+	case left.Bool != nil && right.Bool != nil:
+		return a.evalBoolBinary(binary, *left.Bool, *right.Bool)
+	}
+
+	return src.MsgLiteral{}, &compiler.Error{
+		Message: fmt.Sprintf(
+			"Operands of %v must be of the same type: %v and %v",
+			binary.Operator, binary.Left, binary.Right,
+		),
+		Meta: &binary.Meta,
+	}
+}
+
+func (Analyzer) evalIntBinary(binary src.Binary, l, r int) (src.MsgLiteral, *compiler.Error) {
+	var result int
+
+	switch binary.Operator {
+	case src.AddOp:
+		result = l + r
+	case src.SubOp:
+		result = l - r
+	case src.MulOp:
+		result = l * r
+	case src.DivOp, src.ModOp:
+		if r == 0 {
+			return src.MsgLiteral{}, &compiler.Error{
+				Message: fmt.Sprintf("Constant division by zero: %v", binary),
+				Meta:    &binary.Meta,
+			}
+		}
+		if binary.Operator == src.DivOp {
+			result = l / r
+		} else {
+			result = l % r
+		}
+	case src.PowOp:
+		result = intPow(l, r)
+	case src.BitAndOp:
+		result = l & r
+	case src.BitOrOp:
+		result = l | r
+	case src.BitXorOp:
+		result = l ^ r
+	case src.BitLshOp, src.BitRshOp:
+		if r < 0 {
+			return src.MsgLiteral{}, &compiler.Error{
+				Message: fmt.Sprintf("Negative shift count: %v", binary),
+				Meta:    &binary.Meta,
+			}
+		}
+		if binary.Operator == src.BitLshOp {
+			result = l << r
+		} else {
+			result = l >> r
+		}
+	case src.GtOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(l > r)}, nil
+	case src.LtOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(l < r)}, nil
+	case src.GeOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(l >= r)}, nil
+	case src.LeOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(l <= r)}, nil
+	default:
+		return src.MsgLiteral{}, unsupportedConstOperatorError(binary)
+	}
+
+	return src.MsgLiteral{Int: &result}, nil
+}
+
+func (Analyzer) evalFloatBinary(binary src.Binary, l, r float64) (src.MsgLiteral, *compiler.Error) {
+	var result float64
+
+	switch binary.Operator {
+	case src.AddOp:
+		result = l + r
+	case src.SubOp:
+		result = l - r
+	case src.MulOp:
+		result = l * r
+	case src.DivOp:
+		if r == 0 {
+			return src.MsgLiteral{}, &compiler.Error{
+				Message: fmt.Sprintf("Constant division by zero: %v", binary),
+				Meta:    &binary.Meta,
+			}
+		}
+		result = l / r
+	case src.GtOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(l > r)}, nil
+	case src.LtOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(l < r)}, nil
+	case src.GeOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(l >= r)}, nil
+	case src.LeOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(l <= r)}, nil
+	default:
+		return src.MsgLiteral{}, unsupportedConstOperatorError(binary)
+	}
+
+	return src.MsgLiteral{Float: &result}, nil
+}
+
+func (Analyzer) evalStringBinary(binary src.Binary, l, r string) (src.MsgLiteral, *compiler.Error) {
+	switch binary.Operator {
+	case src.AddOp:
+		return src.MsgLiteral{Str: compiler.Pointer(l + r)}, nil
+	case src.GtOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(strings.Compare(l, r) > 0)}, nil
+	case src.LtOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(strings.Compare(l, r) < 0)}, nil
+	case src.GeOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(strings.Compare(l, r) >= 0)}, nil
+	case src.LeOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(strings.Compare(l, r) <= 0)}, nil
+	default:
+		return src.MsgLiteral{}, unsupportedConstOperatorError(binary)
+	}
+}
+
+func (Analyzer) evalBoolBinary(binary src.Binary, l, r bool) (src.MsgLiteral, *compiler.Error) {
+	switch binary.Operator {
+	case src.AndOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(l && r)}, nil
+	case src.OrOp:
+		return src.MsgLiteral{Bool: compiler.Pointer(l || r)}, nil
+	default:
+		return src.MsgLiteral{}, unsupportedConstOperatorError(binary)
+	}
+}
+
+// evalUnary follows semantics of the runtime functions
+// that implement unary operators.
+func (Analyzer) evalUnary(unary src.Unary, operand src.MsgLiteral) (src.MsgLiteral, *compiler.Error) {
+	switch {
+	case unary.Operator == src.NotOp && operand.Bool != nil:
+		return src.MsgLiteral{Bool: compiler.Pointer(!*operand.Bool)}, nil
+	case operand.Int != nil:
+		switch unary.Operator {
+		case src.IncOp:
+			return src.MsgLiteral{Int: compiler.Pointer(*operand.Int + 1)}, nil
+		case src.DecOp:
+			return src.MsgLiteral{Int: compiler.Pointer(*operand.Int - 1)}, nil
+		case src.NegOp:
+			return src.MsgLiteral{Int: compiler.Pointer(-*operand.Int)}, nil
+		}
+	case operand.Float != nil:
+		switch unary.Operator {
+		case src.IncOp:
+			return src.MsgLiteral{Float: compiler.Pointer(*operand.Float + 1)}, nil
+		case src.DecOp:
+			return src.MsgLiteral{Float: compiler.Pointer(*operand.Float - 1)}, nil
+		case src.NegOp:
+			return src.MsgLiteral{Float: compiler.Pointer(-*operand.Float)}, nil
+		}
+	}
+
+	return src.MsgLiteral{}, &compiler.Error{
+		Message: fmt.Sprintf("Unsupported constant unary expression: %v", unary),
+		Meta:    &unary.Meta,
+	}
+}
+
+func unsupportedConstOperatorError(binary src.Binary) *compiler.Error {
+	return &compiler.Error{
+		Message: fmt.Sprintf("Unsupported constant binary expression: %v", binary),
+		Meta:    &binary.Meta,
+	}
+}
+
+func literalsEqual(l, r src.MsgLiteral) bool {
+	switch {
+	case l.Bool != nil && r.Bool != nil:
+		return *l.Bool == *r.Bool
+	case l.Int != nil && r.Int != nil:
+		return *l.Int == *r.Int
+	case l.Float != nil && r.Float != nil:
+		return *l.Float == *r.Float
+	case l.Str != nil && r.Str != nil:
+		return *l.Str == *r.Str
+	}
+	return false
+}
+
+// intPow computes power the same way int_pow runtime function does,
+// including wrapping on overflow and returning 1 for negative exponent.
+func intPow(base, exp int) int {
+	result := 1
+	for exp > 0 {
+		if exp&1 == 1 {
+			result *= base
+		}
+		base *= base
+		exp >>= 1
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/nevalang/neva/internal/compiler"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+// AnalyzeLibraryBuild is like AnalyzeExecutableBuild but instead of main package
+// it validates that given component can be exported as a library.
+func (a Analyzer) AnalyzeLibraryBuild(
+	build src.Build,
+	pkgName string,
+	componentName string,
+) (src.Build, *compiler.Error) {
+	meta := core.Meta{
+		Location: core.Location{
+			ModRef:  build.EntryModRef,
+			Package: pkgName,
+		},
+	}
+
+	entryMod, ok := build.Modules[build.EntryModRef]
+	if !ok {
+		return src.Build{}, &compiler.Error{
+			Message: fmt.Sprintf("entry module not found: %s", build.EntryModRef),
+			Meta:    &meta,
+		}
+	}
+
+	pkg, ok := entryMod.Packages[pkgName]
+	if !ok {
+		return src.Build{}, &compiler.Error{
+			Message: "library package not found",
+			Meta:    &meta,
+		}
+	}
+
+	if err := a.analyzeLibraryComponent(pkg, componentName, meta.Location); err != nil {
+		return src.Build{}, compiler.Error{Meta: &meta}.Wrap(err)
+	}
+
+	analyzedBuild, err := a.AnalyzeBuild(build)
+	if err != nil {
+		return src.Build{}, compiler.Error{Meta: &meta}.Wrap(err)
+	}
+
+	return analyzedBuild, nil
+}
+
+func (a Analyzer) analyzeLibraryComponent(
+	pkg src.Package,
+	componentName string,
+	location core.Location,
+) *compiler.Error {
+	entity, filename, ok := pkg.Entity(componentName)
+	if !ok {
+		return &compiler.Error{
+			Message: fmt.Sprintf("Exported entity is not found: %v", componentName),
+			Meta:    &core.Meta{Location: location},
+		}
+	}
+
+	location.Filename = filename
+
+	if entity.Kind != src.ComponentEntity {
+		return &compiler.Error{
+			Message: fmt.Sprintf("Exported entity must be a component: %v", componentName),
+			Meta:    &core.Meta{Location: location},
+		}
+	}
+
+	iface := entity.Component.Interface
+
+	if len(iface.TypeParams.Params) != 0 {
+		return &compiler.Error{
+			Message: "Exported component cannot have type parameters",
+			Meta:    &iface.TypeParams.Meta,
+		}
+	}
+
+	if len(iface.IO.In) == 0 || len(iface.IO.Out) == 0 {
+		return &compiler.Error{
+			Message: "Exported component must have at least one inport and one outport",
+			Meta:    &iface.IO.Meta,
+		}
+	}
+
+	for _, ports := range []map[string]src.Port{iface.IO.In, iface.IO.Out} {
+		for name, port := range ports {
+			if port.IsArray {
+				return &compiler.Error{
+					Message: fmt.Sprintf("Exported component's ports cannot be arrays: %v", name),
+					Meta:    &port.Meta,
+				}
+			}
+		}
+	}
+
+	return analyzeRootInports("Exported", entity.Component)
+}
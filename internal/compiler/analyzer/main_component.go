@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/nevalang/neva/internal/compiler"
 	src "github.com/nevalang/neva/internal/compiler/sourcecode"
@@ -19,6 +20,10 @@ func (a Analyzer) analyzeMainComponent(cmp src.Component, scope src.Scope) *comp
 		return compiler.Error{Meta: &cmp.Interface.Meta}.Wrap(err)
 	}
 
+	if err := analyzeRootInports("Main", cmp); err != nil {
+		return err
+	}
+
 	if err := a.analyzeMainComponentNodes(cmp.Nodes, scope); err != nil {
 		return compiler.Error{Meta: &cmp.Meta}.Wrap(err)
 	}
@@ -84,10 +89,7 @@ func (Analyzer) analyzeMainComponentNodes(
 	for nodeName, node := range nodes {
 		nodeEntity, _, err := scope.Entity(node.EntityRef)
 		if err != nil {
-			return &compiler.Error{
-				Message: err.Error(),
-				Meta:    &node.EntityRef.Meta,
-			}
+			continue // build analysis reports it along with other errors of Main
 		}
 
 		if nodeEntity.Kind != src.ComponentEntity {
@@ -100,3 +102,39 @@ func (Analyzer) analyzeMainComponentNodes(
 
 	return nil
 }
+
+// analyzeRootInports checks that component the program starts from uses all its inports,
+// because runtime sends messages to them. Other components may leave inports unused.
+func analyzeRootInports(kind string, cmp src.Component) *compiler.Error {
+	if _, ok := cmp.Directives[compiler.ExternDirective]; ok {
+		return nil
+	}
+
+	used := map[string]bool{}
+	for _, conn := range cmp.Net {
+		conn.WalkPorts(func(addr src.PortAddr, out bool) {
+			if out && addr.Node == "in" { // self inports are outports for the network
+				used[addr.Port] = true
+			}
+		})
+	}
+
+	names := make([]string, 0, len(cmp.Interface.IO.In))
+	for name := range cmp.Interface.IO.In {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if used[name] {
+			continue
+		}
+		port := cmp.Interface.IO.In[name]
+		return &compiler.Error{
+			Message: fmt.Sprintf("%v component must use its inport: %v", kind, name),
+			Meta:    &port.Meta,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nevalang/neva/internal/compiler"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+// analyzeNative checks that compiler understands which runtime functions native code of the module declares
+// and that every extern of the module refers either to one of them or to a function of the standard library.
+func (a Analyzer) analyzeNative(modRef core.ModuleRef, build src.Build) *compiler.Error {
+	mod := build.Modules[modRef]
+	if len(mod.Native) == 0 {
+		return nil
+	}
+
+	nativeFuncs, err := compiler.NativeFuncs(mod.Native)
+	if err != nil {
+		return &compiler.Error{
+			Message: "invalid native code: " + err.Error(),
+			Meta:    &core.Meta{Location: core.Location{ModRef: modRef}},
+		}
+	}
+
+	stdFuncs := externFuncs(build)
+
+	var errs []*compiler.Error
+	for _, pkgName := range slices.Sorted(maps.Keys(mod.Packages)) {
+		for _, ext := range externs(mod.Packages[pkgName]) {
+			for _, ref := range ext.refs {
+				_, isNative := nativeFuncs[ref]
+				_, isStd := stdFuncs[ref]
+				if isNative || isStd {
+					continue
+				}
+				errs = append(errs, &compiler.Error{
+					Message: fmt.Sprintf(
+						"runtime function %s is declared neither by native code of the module nor by the standard library",
+						ref,
+					),
+					Meta: &ext.component.Meta,
+				})
+			}
+		}
+	}
+
+	return compiler.Join(errs...)
+}
+
+// externFuncs returns names of runtime functions that externs of the standard library refer to.
+func externFuncs(build src.Build) map[string]struct{} {
+	result := map[string]struct{}{}
+	for modRef, mod := range build.Modules {
+		if modRef.Path != "std" {
+			continue
+		}
+		for _, pkg := range mod.Packages {
+			for _, ext := range externs(pkg) {
+				for _, ref := range ext.refs {
+					result[ref] = struct{}{}
+				}
+			}
+		}
+	}
+	return result
+}
+
+type externComponent struct {
+	name      string
+	component src.Component
+	refs      []string // Names of runtime functions, one per overload.
+}
+
+// externs returns components of the package with #extern directive, sorted by name.
+func externs(pkg src.Package) []externComponent {
+	var result []externComponent
+
+	for entity := range pkg.Entities() {
+		if entity.Entity.Kind != src.ComponentEntity {
+			continue
+		}
+		args, ok := entity.Entity.Component.Directives[compiler.ExternDirective]
+		if !ok {
+			continue
+		}
+
+		refs := make([]string, 0, len(args))
+		for _, arg := range args {
+			// overloaded externs have <type, flow_ref> pairs as arguments
+			if _, ref, ok := strings.Cut(arg, " "); ok {
+				arg = ref
+			}
+			refs = append(refs, arg)
+		}
+
+		result = append(result, externComponent{
+			name:      entity.EntityName,
+			component: entity.Entity.Component,
+			refs:      refs,
+		})
+	}
+
+	slices.SortFunc(result, func(a, b externComponent) int { return strings.Compare(a.name, b.name) })
+
+	return result
+}
@@ -51,7 +51,7 @@ func (a Analyzer) analyzeNetwork(
 }
 
 // analyzeConnections does two things:
-// 1. Analyzes every connection and terminates with non-nil error if any of them is invalid.
+// 1. Analyzes every connection and returns joined errors of all invalid ones.
 // 2. Updates nodesUsage (we mutate it in-place instead of returning to avoid merging across recursive calls).
 func (a Analyzer) analyzeConnections(
 	net []src.Connection,
@@ -62,6 +62,7 @@ func (a Analyzer) analyzeConnections(
 	scope src.Scope,
 ) ([]src.Connection, *compiler.Error) {
 	analyzedConnections := make([]src.Connection, 0, len(net))
+	var errs []*compiler.Error
 
 	for _, conn := range net {
 		resolvedConn, err := a.analyzeConnection(
@@ -74,11 +75,16 @@ func (a Analyzer) analyzeConnections(
 			nil,
 		)
 		if err != nil {
-			return nil, err
+			errs = append(errs, err)
+			continue
 		}
 		analyzedConnections = append(analyzedConnections, resolvedConn)
 	}
 
+	if err := compiler.Join(errs...); err != nil {
+		return nil, err
+	}
+
 	return analyzedConnections, nil
 }
 
@@ -424,6 +430,10 @@ func (a Analyzer) analyzePortAddrReceiver(
 					analyzedSenders[i], portAddr, err.Error(),
 				),
 				Meta: &portAddr.Meta,
+				Notes: []compiler.Note{{
+					Message: "sender is here",
+					Meta:    &analyzedSenders[i].Meta,
+				}},
 			}
 		}
 	}
@@ -576,8 +586,6 @@ func (a Analyzer) analyzeSender(
 		}
 	}
 
-	// TODO support unary
-
 	if sender.Range != nil && len(prevChainLink) == 0 {
 		return nil, nil, &compiler.Error{
 			Message: "range expression cannot be used in non-chained connection",
@@ -594,7 +602,7 @@ func (a Analyzer) analyzeSender(
 
 	if sender.Ternary != nil {
 		// analyze the condition part
-		_, condType, err := a.analyzeSender(
+		analyzedCond, condType, err := a.analyzeSender(
 			sender.Ternary.Condition,
 			scope,
 			iface,
@@ -621,7 +629,7 @@ func (a Analyzer) analyzeSender(
 		}
 
 		// analyze the trueVal part
-		_, trueValType, err := a.analyzeSender(
+		analyzedTrueVal, trueValType, err := a.analyzeSender(
 			sender.Ternary.Left,
 			scope,
 			iface,
@@ -637,7 +645,7 @@ func (a Analyzer) analyzeSender(
 		}
 
 		// analyze the falseVal part
-		_, _, err = a.analyzeSender(
+		analyzedFalseVal, falseValType, err := a.analyzeSender(
 			sender.Ternary.Right,
 			scope,
 			iface,
@@ -652,11 +660,63 @@ func (a Analyzer) analyzeSender(
 			}.Wrap(err)
 		}
 
+		// if all parts are constants we can choose the branch at compile time
+		folded, foldedType := a.foldTernarySender(
+			*sender.Ternary,
+			*analyzedCond,
+			*condType,
+			*analyzedTrueVal,
+			*trueValType,
+			*analyzedFalseVal,
+			*falseValType,
+			scope,
+		)
+		if folded != nil {
+			return folded, foldedType, nil
+		}
+
 		return &sender, trueValType, nil
 	}
 
+	if sender.Unary != nil {
+		analyzedOperand, operandType, err := a.analyzeSender(
+			sender.Unary.Operand,
+			scope,
+			iface,
+			nodes,
+			nodesIfaces,
+			nodesUsage,
+			prevChainLink,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		constr := a.getUnaryOperatorConstraint(sender.Unary.Operator)
+
+		if err := a.resolver.IsSubtypeOf(*operandType, constr, scope); err != nil {
+			return nil, nil, &compiler.Error{
+				Message: fmt.Sprintf("Invalid operand type for %s: %v", sender.Unary.Operator, err),
+				Meta:    &sender.Unary.Meta,
+			}
+		}
+
+		// desugarer needs this information to use overloaded components
+		sender.Unary.AnalyzedType = *operandType
+
+		folded, err := a.foldUnarySender(*sender.Unary, *analyzedOperand, *operandType, scope)
+		if err != nil {
+			return nil, nil, err
+		}
+		if folded != nil {
+			return folded, operandType, nil
+		}
+
+		return &sender, operandType, nil
+	}
+
 	if sender.Binary != nil {
-		_, leftType, err := a.analyzeSender(
+		analyzedLeft, leftType, err := a.analyzeSender(
 			sender.Binary.Left,
 			scope,
 			iface,
@@ -669,7 +729,7 @@ func (a Analyzer) analyzeSender(
 			return nil, nil, err
 		}
 
-		_, rightType, err := a.analyzeSender(
+		analyzedRight, rightType, err := a.analyzeSender(
 			sender.Binary.Right,
 			scope,
 			iface,
@@ -707,6 +767,23 @@ func (a Analyzer) analyzeSender(
 
 		resultType := a.getBinaryExprType(sender.Binary.Operator, *leftType)
 
+		// expressions with constant operands are evaluated at compile time
+		folded, err := a.foldBinarySender(
+			*sender.Binary,
+			*analyzedLeft,
+			*leftType,
+			*analyzedRight,
+			*rightType,
+			resultType,
+			scope,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if folded != nil {
+			return folded, &resultType, nil
+		}
+
 		return &sender, &resultType, nil
 	}
 
@@ -852,24 +929,7 @@ func (a Analyzer) analyzeNetPortsUsage(
 	nodesUsage map[string]netNodeUsage,
 	nodes map[string]src.Node,
 ) *compiler.Error {
-	// 1. every self inport must be used
-	inportsUsage, ok := nodesUsage["in"]
-	if !ok {
-		return &compiler.Error{
-			Message: "Unused inports",
-			Meta:    &compInterface.Meta,
-		}
-	}
-
-	for inportName := range compInterface.IO.In {
-		if _, ok := inportsUsage.Out[inportName]; !ok { // note that self inports are outports for the network
-			return &compiler.Error{
-				Message: fmt.Sprintf("Unused inport: %v", inportName),
-			}
-		}
-	}
-
-	// 2. every self-outport must be used
+	// 1. every self-outport must be used, unused self inports are reported by linter
 	outportsUsage, ok := nodesUsage["out"]
 	if !ok {
 		return &compiler.Error{
@@ -893,20 +953,17 @@ func (a Analyzer) analyzeNetPortsUsage(
 		}
 	}
 
-	// 3. check sub-nodes usage in network
+	// 2. check sub-nodes usage in network.
+	// Unused nodes are not errors, linter warns about them and desugarer removes them.
 	for nodeName, nodeIface := range nodesIfaces {
 		nodeMeta := nodes[nodeName].Meta
 
-		// every sub-node must be used
 		nodeUsage, ok := nodesUsage[nodeName]
 		if !ok {
-			return &compiler.Error{
-				Message: fmt.Sprintf("Unused node found: %v", nodeName),
-				Meta:    &nodeMeta,
-			}
+			continue
 		}
 
-		// every sub-node's inport must be used
+		// every sub-node's inport must be used, otherwise it never receives anything
 		for inportName := range nodeIface.iface.IO.In {
 			if _, ok := nodeUsage.In[inportName]; ok {
 				continue
@@ -927,38 +984,18 @@ func (a Analyzer) analyzeNetPortsUsage(
 			}
 		}
 
-		if len(nodeIface.iface.IO.Out) == 0 { // e.g. Del
-			continue
-		}
-
-		// :err outport must always be used + at least one outport must be used in general
-		atLeastOneOutportIsUsed := false
-		for outportName := range nodeIface.iface.IO.Out {
-			if _, ok := nodeUsage.Out[outportName]; ok {
-				atLeastOneOutportIsUsed = true
-				continue
-			}
-
-			if outportName == "err" && !nodes[nodeName].ErrGuard {
+		// :err outport must always be used, other unused outports are handled by desugarer
+		if _, ok := nodeIface.iface.IO.Out["err"]; ok && !nodes[nodeName].ErrGuard {
+			if _, ok := nodeUsage.Out["err"]; !ok {
 				return &compiler.Error{
 					Message: fmt.Sprintf("unhandled error: %v:err", nodeName),
 					Meta:    &nodeMeta,
 				}
 			}
 		}
-
-		if !atLeastOneOutportIsUsed {
-			if _, ok := nodeUsage.Out[""]; ok && len(nodeIface.iface.IO.Out) == 1 {
-				continue
-			}
-			return &compiler.Error{
-				Message: fmt.Sprintf("All node's outports are unused: %v", nodeName),
-				Meta:    &nodeMeta,
-			}
-		}
 	}
 
-	// 4. check that array ports are used correctly (from 0 and without holes)
+	// 3. check that array ports are used correctly (from 0 and without holes)
 	for nodeName, nodeUsage := range nodesUsage {
 		nodeMeta := nodes[nodeName].Meta
 
@@ -1099,8 +1136,7 @@ func (a Analyzer) getNodeInportType(
 	}
 
 	resolvedPortAddr, resolvedInportType, isArray, err := a.getResolvedPortType(
-		nodeIface.iface.IO.In,
-		nodeIface.iface.TypeParams.Params,
+		nodeIface,
 		portAddr,
 		node,
 		scope.Relocate(nodeIface.location),
@@ -1118,13 +1154,18 @@ func (a Analyzer) getNodeInportType(
 // getResolvedPortType returns resolved port-addr, type expr and isArray bool.
 // Resolved port is equal to the given one unless it was an "" empty string.
 func (a Analyzer) getResolvedPortType(
-	ports map[string]src.Port,
-	nodeIfaceParams []ts.Param,
+	nodeIface foundInterface,
 	portAddr src.PortAddr,
 	node src.Node,
 	scope src.Scope,
 	isInput bool,
 ) (src.PortAddr, ts.Expr, bool, *compiler.Error) {
+	ports := nodeIface.iface.IO.Out
+	if isInput {
+		ports = nodeIface.iface.IO.In
+	}
+	nodeIfaceParams := nodeIface.iface.TypeParams.Params
+
 	if portAddr.Port == "" {
 		if len(ports) == 1 || (!isInput && len(ports) == 2 && node.ErrGuard) {
 			for name := range ports {
@@ -1147,6 +1188,10 @@ func (a Analyzer) getResolvedPortType(
 				portAddr,
 			),
 			Meta: &portAddr.Meta,
+			Notes: []compiler.Note{{
+				Message: fmt.Sprintf("ports of node '%v' are declared here", portAddr.Node),
+				Meta:    &nodeIface.iface.Meta,
+			}},
 		}
 	}
 
@@ -1253,6 +1298,21 @@ func (a Analyzer) getResolvedSenderType(
 		return sender, a.getBinaryExprType(sender.Binary.Operator, leftType), false, nil
 	}
 
+	if sender.Unary != nil {
+		_, operandType, _, err := a.getResolvedSenderType(
+			sender.Unary.Operand,
+			iface,
+			nodes,
+			nodesIfaces,
+			scope,
+			prevChainLink,
+		)
+		if err != nil {
+			return src.ConnectionSender{}, ts.Expr{}, false, err
+		}
+		return sender, operandType, false, nil
+	}
+
 	// logic of getting type for ternary expr partially duplicates logic of validating it
 	// so we have to duplicate some code from "analyzeSender", but it should be possible to refactor
 	if sender.Ternary != nil {
@@ -1309,6 +1369,22 @@ func (Analyzer) getBinaryExprType(operator src.BinaryOperator, leftType ts.Expr)
 	return leftType
 }
 
+func (Analyzer) getUnaryOperatorConstraint(operator src.UnaryOperator) ts.Expr {
+	if operator == src.NotOp {
+		return ts.Expr{
+			Inst: &ts.InstExpr{Ref: core.EntityRef{Name: "bool"}},
+		}
+	}
+	return ts.Expr{
+		Lit: &ts.LitExpr{
+			Union: []ts.Expr{
+				{Inst: &ts.InstExpr{Ref: core.EntityRef{Name: "int"}}},
+				{Inst: &ts.InstExpr{Ref: core.EntityRef{Name: "float"}}},
+			},
+		},
+	}
+}
+
 func (Analyzer) getOperatorConstraint(binary src.Binary) (ts.Expr, *compiler.Error) {
 	switch binary.Operator {
 	case src.AddOp:
@@ -1513,8 +1589,7 @@ func (a Analyzer) getNodeOutportType(
 	}
 
 	return a.getResolvedPortType(
-		nodeIface.iface.IO.Out,
-		nodeIface.iface.TypeParams.Params,
+		nodeIface,
 		portAddr,
 		node,
 		scope.Relocate(nodeIface.location),
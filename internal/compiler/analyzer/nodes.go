@@ -27,6 +27,7 @@ func (a Analyzer) analyzeNodes(
 	analyzedNodes := make(map[string]src.Node, len(nodes))
 	nodesInterfaces := make(map[string]foundInterface, len(nodes))
 	hasErrGuard := false
+	var errs []*compiler.Error
 
 	for nodeName, node := range nodes {
 		if node.ErrGuard {
@@ -39,15 +40,20 @@ func (a Analyzer) analyzeNodes(
 			scope,
 		)
 		if err != nil {
-			return nil, nil, false, compiler.Error{
+			errs = append(errs, compiler.Error{
 				Meta: &node.Meta,
-			}.Wrap(err)
+			}.Wrap(err))
+			continue
 		}
 
 		nodesInterfaces[nodeName] = nodeInterface
 		analyzedNodes[nodeName] = analyzedNode
 	}
 
+	if err := compiler.Join(errs...); err != nil {
+		return nil, nil, false, err
+	}
+
 	return analyzedNodes, nodesInterfaces, hasErrGuard, nil
 }
 
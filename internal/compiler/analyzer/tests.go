@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nevalang/neva/internal/compiler"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+// AnalyzeTestBuild is like AnalyzeExecutableBuild but instead of main package
+// it validates that every test component of the given package can be executed.
+func (a Analyzer) AnalyzeTestBuild(build src.Build, pkgName string) (src.Build, *compiler.Error) {
+	return a.analyzeTestPackageBuild(build, pkgName, "Test", src.Package.Tests)
+}
+
+// AnalyzeBenchmarkBuild is like AnalyzeTestBuild but validates benchmark components instead of tests.
+func (a Analyzer) AnalyzeBenchmarkBuild(build src.Build, pkgName string) (src.Build, *compiler.Error) {
+	return a.analyzeTestPackageBuild(build, pkgName, "Benchmark", src.Package.Benchmarks)
+}
+
+func (a Analyzer) analyzeTestPackageBuild(
+	build src.Build,
+	pkgName string,
+	kind string,
+	components func(src.Package) func(func(src.EntitiesResult) bool),
+) (src.Build, *compiler.Error) {
+	meta := core.Meta{
+		Location: core.Location{
+			ModRef:  build.EntryModRef,
+			Package: pkgName,
+		},
+	}
+
+	entryMod, ok := build.Modules[build.EntryModRef]
+	if !ok {
+		return src.Build{}, &compiler.Error{
+			Message: fmt.Sprintf("entry module not found: %s", build.EntryModRef),
+			Meta:    &meta,
+		}
+	}
+
+	pkg, ok := entryMod.Packages[pkgName]
+	if !ok {
+		return src.Build{}, &compiler.Error{
+			Message: "test package not found",
+			Meta:    &meta,
+		}
+	}
+
+	if err := a.analyzeTestComponents(kind, components(pkg)); err != nil {
+		return src.Build{}, err
+	}
+
+	analyzedBuild, err := a.AnalyzeBuild(build)
+	if err != nil {
+		return src.Build{}, compiler.Error{Meta: &meta}.Wrap(err)
+	}
+
+	return analyzedBuild, nil
+}
+
+// analyzeTestComponents checks components in the order of their names so the first error is always the same.
+func (a Analyzer) analyzeTestComponents(
+	kind string,
+	components func(func(src.EntitiesResult) bool),
+) *compiler.Error {
+	results := []src.EntitiesResult{}
+	for result := range components {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].EntityName < results[j].EntityName })
+
+	for _, result := range results {
+		if err := a.analyzeTestComponent(kind, result.Entity.Component); err != nil {
+			return compiler.Error{Meta: &result.Entity.Component.Meta}.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// analyzeTestComponent checks that test or benchmark component has the same interface as Main.
+func (a Analyzer) analyzeTestComponent(kind string, cmp src.Component) *compiler.Error {
+	if _, ok := cmp.Directives[compiler.ExternDirective]; ok {
+		return &compiler.Error{
+			Message: kind + " component cannot be extern",
+			Meta:    &cmp.Meta,
+		}
+	}
+
+	iface := cmp.Interface
+
+	if len(iface.TypeParams.Params) != 0 {
+		return &compiler.Error{
+			Message: kind + " component cannot have type parameters",
+			Meta:    &iface.TypeParams.Meta,
+		}
+	}
+
+	if len(iface.IO.In) != 1 || len(iface.IO.Out) != 1 {
+		return &compiler.Error{
+			Message: kind + " component must have exactly one 'start' inport and one 'stop' outport",
+			Meta:    &iface.IO.Meta,
+		}
+	}
+
+	start, ok := iface.IO.In["start"]
+	if !ok {
+		return &compiler.Error{Message: kind + " component must have 'start' inport", Meta: &iface.IO.Meta}
+	}
+
+	stop, ok := iface.IO.Out["stop"]
+	if !ok {
+		return &compiler.Error{Message: kind + " component must have 'stop' outport", Meta: &iface.IO.Meta}
+	}
+
+	for _, port := range []src.Port{start, stop} {
+		if port.IsArray {
+			return &compiler.Error{
+				Message: kind + " component's ports cannot be arrays",
+				Meta:    &port.Meta,
+			}
+		}
+		if !(src.Scope{}).IsTopType(port.TypeExpr) {
+			return &compiler.Error{
+				Message: kind + " component's ports must be of type any",
+				Meta:    &port.Meta,
+			}
+		}
+	}
+
+	return analyzeRootInports(kind, cmp)
+}
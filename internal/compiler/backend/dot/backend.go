@@ -7,14 +7,43 @@ import (
 	"github.com/nevalang/neva/internal/compiler/ir"
 )
 
-type Backend struct{}
+type format uint8
 
+const (
+	formatDOT format = iota
+	formatSVG
+	formatHTML
+)
+
+type Backend struct {
+	format format
+}
+
+// NewBackend creates backend that emits program graph in Graphviz DOT format.
 func NewBackend() Backend {
-	return Backend{}
+	return Backend{format: formatDOT}
+}
+
+// NewSVGBackend creates backend that lays out program graph itself and emits SVG image.
+func NewSVGBackend() Backend {
+	return Backend{format: formatSVG}
+}
+
+// NewHTMLBackend creates backend that emits self-contained HTML page with interactive SVG image.
+func NewHTMLBackend() Backend {
+	return Backend{format: formatHTML}
 }
 
 func (b Backend) Emit(dst string, prog *ir.Program, trace bool) error {
-	outFile := filepath.Join(dst, "program.dot")
+	fileName, build := "program.dot", (*ClusterBuilder).Build
+	switch b.format {
+	case formatSVG:
+		fileName, build = "program.svg", (*ClusterBuilder).BuildSVG
+	case formatHTML:
+		fileName, build = "program.html", (*ClusterBuilder).BuildHTML
+	}
+
+	outFile := filepath.Join(dst, fileName)
 	f, err := os.OpenFile(outFile, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0755)
 	if err != nil {
 		return err
@@ -24,5 +53,5 @@ func (b Backend) Emit(dst string, prog *ir.Program, trace bool) error {
 	for sender, receiver := range prog.Connections {
 		cb.InsertEdge(sender, receiver)
 	}
-	return cb.Build(f)
+	return build(&cb, f)
 }
@@ -0,0 +1,356 @@
+// Package layout implements layered (Sugiyama-style) layout of directed graphs.
+// It's used to render graphs without Graphviz installed.
+// Edges are directed from top to bottom: cycles are broken, nodes are assigned to layers,
+// long edges get dummy nodes, crossings are reduced with barycenter heuristic
+// and finally nodes get coordinates.
+package layout
+
+import (
+	"math"
+	"sort"
+)
+
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// Node is a rectangle of the given size.
+type Node struct {
+	Width, Height float64
+}
+
+// Edge connects nodes by their indexes.
+type Edge struct {
+	From, To int
+}
+
+type Point struct {
+	X, Y float64
+}
+
+type Options struct {
+	LayerGap   float64 // Vertical space between layers.
+	NodeGap    float64 // Horizontal space between nodes in the same layer.
+	Iterations int     // Number of crossing reduction sweeps.
+}
+
+func DefaultOptions() Options {
+	return Options{
+		LayerGap:   50,
+		NodeGap:    30,
+		Iterations: 12,
+	}
+}
+
+type Result struct {
+	Nodes  []Point   // Top-left corners of nodes.
+	Bends  [][]Point // Intermediate points of each edge, from source to target.
+	Width  float64
+	Height float64
+}
+
+// vertex is either real node or dummy node that is inserted into long edge.
+type vertex struct {
+	node   int // -1 for dummy
+	width  float64
+	height float64
+	layer  int
+	order  float64 // position inside the layer, used for sorting
+	x      float64 // center
+	up     []int   // vertices in previous layer
+	down   []int   // vertices in next layer
+}
+
+func Layout(g Graph, opts Options) Result {
+	if len(g.Nodes) == 0 {
+		return Result{}
+	}
+
+	edges, reversed := removeCycles(len(g.Nodes), g.Edges)
+	layers := assignLayers(len(g.Nodes), edges)
+
+	vertices := make([]*vertex, len(g.Nodes))
+	for i, node := range g.Nodes {
+		vertices[i] = &vertex{
+			node:   i,
+			width:  node.Width,
+			height: node.Height,
+			layer:  layers[i],
+		}
+	}
+
+	// chains are vertices that each edge goes through, dummies only
+	chains := make([][]int, len(g.Edges))
+	for i, edge := range edges {
+		if edge.From == edge.To {
+			continue
+		}
+		prev := edge.From
+		for layer := layers[edge.From] + 1; layer < layers[edge.To]; layer++ {
+			vertices = append(vertices, &vertex{node: -1, layer: layer})
+			dummy := len(vertices) - 1
+			link(vertices, prev, dummy)
+			chains[i] = append(chains[i], dummy)
+			prev = dummy
+		}
+		link(vertices, prev, edge.To)
+	}
+
+	byLayer := groupByLayer(vertices)
+	reduceCrossings(vertices, byLayer, opts.Iterations)
+	assignX(vertices, byLayer, opts.NodeGap)
+
+	// vertical coordinates
+	layerY := make([]float64, len(byLayer))
+	layerHeight := make([]float64, len(byLayer))
+	y := 0.0
+	for l, layer := range byLayer {
+		for _, v := range layer {
+			layerHeight[l] = math.Max(layerHeight[l], vertices[v].height)
+		}
+		layerY[l] = y
+		y += layerHeight[l] + opts.LayerGap
+	}
+
+	result := Result{
+		Nodes:  make([]Point, len(g.Nodes)),
+		Bends:  make([][]Point, len(g.Edges)),
+		Height: y - opts.LayerGap,
+	}
+
+	for i := range g.Nodes {
+		v := vertices[i]
+		result.Nodes[i] = Point{
+			X: v.x - v.width/2,
+			// center nodes vertically inside the layer
+			Y: layerY[v.layer] + (layerHeight[v.layer]-v.height)/2,
+		}
+		result.Width = math.Max(result.Width, v.x+v.width/2)
+	}
+
+	for i, chain := range chains {
+		bends := make([]Point, 0, len(chain)*2)
+		for _, d := range chain {
+			v := vertices[d]
+			// dummy occupies whole layer height so edge goes straight through it
+			bends = append(bends,
+				Point{X: v.x, Y: layerY[v.layer]},
+				Point{X: v.x, Y: layerY[v.layer] + layerHeight[v.layer]},
+			)
+		}
+		if reversed[i] {
+			for l, r := 0, len(bends)-1; l < r; l, r = l+1, r-1 {
+				bends[l], bends[r] = bends[r], bends[l]
+			}
+		}
+		result.Bends[i] = bends
+	}
+
+	return result
+}
+
+func link(vertices []*vertex, from, to int) {
+	vertices[from].down = append(vertices[from].down, to)
+	vertices[to].up = append(vertices[to].up, from)
+}
+
+// removeCycles reverses back edges found by depth-first search so the graph becomes acyclic.
+func removeCycles(n int, edges []Edge) ([]Edge, []bool) {
+	adj := make([][]int, n)
+	for i, edge := range edges {
+		adj[edge.From] = append(adj[edge.From], i)
+	}
+
+	const (
+		unvisited = iota
+		inStack
+		done
+	)
+
+	state := make([]int, n)
+	result := append([]Edge{}, edges...)
+	reversed := make([]bool, len(edges))
+
+	var visit func(int)
+	visit = func(v int) {
+		state[v] = inStack
+		for _, i := range adj[v] {
+			to := edges[i].To
+			switch state[to] {
+			case unvisited:
+				visit(to)
+			case inStack:
+				if to != v {
+					result[i] = Edge{From: to, To: v}
+					reversed[i] = true
+				}
+			}
+		}
+		state[v] = done
+	}
+
+	for v := 0; v < n; v++ {
+		if state[v] == unvisited {
+			visit(v)
+		}
+	}
+
+	return result, reversed
+}
+
+// assignLayers puts every node to the layer right after the deepest of its predecessors.
+func assignLayers(n int, edges []Edge) []int {
+	inDegree := make([]int, n)
+	adj := make([][]int, n)
+	for _, edge := range edges {
+		if edge.From == edge.To {
+			continue
+		}
+		adj[edge.From] = append(adj[edge.From], edge.To)
+		inDegree[edge.To]++
+	}
+
+	queue := make([]int, 0, n)
+	for v := 0; v < n; v++ {
+		if inDegree[v] == 0 {
+			queue = append(queue, v)
+		}
+	}
+
+	layers := make([]int, n)
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, to := range adj[v] {
+			layers[to] = max(layers[to], layers[v]+1)
+			inDegree[to]--
+			if inDegree[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	return layers
+}
+
+func groupByLayer(vertices []*vertex) [][]int {
+	count := 0
+	for _, v := range vertices {
+		count = max(count, v.layer+1)
+	}
+	byLayer := make([][]int, count)
+	for i, v := range vertices {
+		v.order = float64(len(byLayer[v.layer]))
+		byLayer[v.layer] = append(byLayer[v.layer], i)
+	}
+	return byLayer
+}
+
+// reduceCrossings sorts layers by barycenters of neighbours, alternating downward and upward sweeps.
+func reduceCrossings(vertices []*vertex, byLayer [][]int, iterations int) {
+	for it := 0; it < iterations; it++ {
+		if it%2 == 0 {
+			for l := 1; l < len(byLayer); l++ {
+				sortByBarycenter(vertices, byLayer[l], func(v *vertex) []int { return v.up })
+			}
+		} else {
+			for l := len(byLayer) - 2; l >= 0; l-- {
+				sortByBarycenter(vertices, byLayer[l], func(v *vertex) []int { return v.down })
+			}
+		}
+	}
+}
+
+func sortByBarycenter(vertices []*vertex, layer []int, neighbours func(*vertex) []int) {
+	barycenters := make(map[int]float64, len(layer))
+	for _, i := range layer {
+		ns := neighbours(vertices[i])
+		if len(ns) == 0 {
+			barycenters[i] = vertices[i].order
+			continue
+		}
+		sum := 0.0
+		for _, n := range ns {
+			sum += vertices[n].order
+		}
+		barycenters[i] = sum / float64(len(ns))
+	}
+
+	sort.SliceStable(layer, func(a, b int) bool {
+		return barycenters[layer[a]] < barycenters[layer[b]]
+	})
+
+	for pos, i := range layer {
+		vertices[i].order = float64(pos)
+	}
+}
+
+// assignX packs layers from left to right and then pulls vertices towards their neighbours
+// without changing order inside layers.
+func assignX(vertices []*vertex, byLayer [][]int, gap float64) {
+	for _, layer := range byLayer {
+		x := 0.0
+		for _, i := range layer {
+			v := vertices[i]
+			v.x = x + v.width/2
+			x += v.width + gap
+		}
+	}
+
+	for it := 0; it < 8; it++ {
+		for l := range byLayer {
+			neighbours := func(v *vertex) []int { return append(append([]int{}, v.up...), v.down...) }
+			balanceLayer(vertices, byLayer[l], neighbours, gap)
+		}
+	}
+
+	minX := math.Inf(1)
+	for _, v := range vertices {
+		minX = math.Min(minX, v.x-v.width/2)
+	}
+	for _, v := range vertices {
+		v.x -= minX
+	}
+}
+
+func balanceLayer(vertices []*vertex, layer []int, neighbours func(*vertex) []int, gap float64) {
+	desired := make([]float64, len(layer))
+	for pos, i := range layer {
+		v := vertices[i]
+		ns := neighbours(v)
+		if len(ns) == 0 {
+			desired[pos] = v.x
+			continue
+		}
+		sum := 0.0
+		for _, n := range ns {
+			sum += vertices[n].x
+		}
+		desired[pos] = sum / float64(len(ns))
+	}
+
+	// place vertices as close to desired positions as possible pushing them to the right,
+	// then to the left, and take the average which also keeps vertices apart
+	leftToRight := make([]float64, len(layer))
+	for pos, i := range layer {
+		leftToRight[pos] = desired[pos]
+		if pos > 0 {
+			minDist := (vertices[layer[pos-1]].width+vertices[i].width)/2 + gap
+			leftToRight[pos] = math.Max(leftToRight[pos], leftToRight[pos-1]+minDist)
+		}
+	}
+
+	rightToLeft := make([]float64, len(layer))
+	for pos := len(layer) - 1; pos >= 0; pos-- {
+		rightToLeft[pos] = desired[pos]
+		if pos < len(layer)-1 {
+			minDist := (vertices[layer[pos+1]].width+vertices[layer[pos]].width)/2 + gap
+			rightToLeft[pos] = math.Min(rightToLeft[pos], rightToLeft[pos+1]-minDist)
+		}
+	}
+
+	for pos, i := range layer {
+		vertices[i].x = (leftToRight[pos] + rightToLeft[pos]) / 2
+	}
+}
@@ -0,0 +1,58 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayout(t *testing.T) {
+	g := Graph{
+		Nodes: []Node{
+			{Width: 40, Height: 20},
+			{Width: 60, Height: 20},
+			{Width: 40, Height: 30},
+			{Width: 40, Height: 20},
+		},
+		Edges: []Edge{
+			{From: 0, To: 1},
+			{From: 0, To: 2},
+			{From: 1, To: 3},
+			{From: 0, To: 3}, // long edge
+			{From: 3, To: 0}, // cycle
+		},
+	}
+
+	result := Layout(g, DefaultOptions())
+
+	require.Len(t, result.Nodes, 4)
+	require.Len(t, result.Bends, 5)
+
+	// edges go from top to bottom
+	require.Less(t, result.Nodes[0].Y, result.Nodes[1].Y)
+	require.Less(t, result.Nodes[1].Y, result.Nodes[3].Y)
+
+	// nodes of the same layer don't overlap
+	left, right := result.Nodes[1], result.Nodes[2]
+	leftWidth := g.Nodes[1].Width
+	if left.X > right.X {
+		left, right = right, left
+		leftWidth = g.Nodes[2].Width
+	}
+	require.LessOrEqual(t, left.X+leftWidth, right.X)
+
+	// long edges are routed through the layer they skip
+	require.Len(t, result.Bends[3], 2)
+	require.Len(t, result.Bends[4], 2)
+	require.Greater(t, result.Bends[4][0].Y, result.Bends[4][1].Y) // reversed edge goes up
+
+	for _, p := range result.Nodes {
+		require.GreaterOrEqual(t, p.X, 0.0)
+		require.LessOrEqual(t, p.X, result.Width)
+		require.LessOrEqual(t, p.Y, result.Height)
+	}
+}
+
+func TestLayout_Empty(t *testing.T) {
+	require.Equal(t, Result{}, Layout(Graph{}, DefaultOptions()))
+}
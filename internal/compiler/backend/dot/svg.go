@@ -0,0 +1,375 @@
+package dot
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/nevalang/neva/internal/compiler/backend/dot/layout"
+)
+
+// Sizes used to render graph without Graphviz. Text width is estimated for monospace font.
+const (
+	svgCharWidth     = 7.0
+	svgFontSize      = 12
+	svgPortHeight    = 16.0
+	svgTitleHeight   = 26.0
+	svgPadding       = 8.0
+	svgPortGap       = 6.0
+	svgClusterPad    = 12.0
+	svgClusterLabelH = 16.0
+	svgMargin        = 20.0
+)
+
+// svgNode is a node with its geometry.
+type svgNode struct {
+	node     *Node
+	clusters []*Cluster // clusters that contain node, from outer to inner
+	in       []Port
+	out      []Port
+	x, y     float64
+	width    float64
+	height   float64
+}
+
+type svgRect struct {
+	x1, y1, x2, y2 float64
+}
+
+// BuildSVG lays out the graph without Graphviz and writes it as SVG image.
+func (b *ClusterBuilder) BuildSVG(w io.Writer) error {
+	svg, err := b.renderSVG()
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, svg)
+	return err
+}
+
+// BuildHTML writes self-contained HTML page with SVG image that can be panned and zoomed.
+// Hovering a port shows its address and connections.
+func (b *ClusterBuilder) BuildHTML(w io.Writer) error {
+	if b.once.Do(b.initTemplates); b.err != nil {
+		return b.err
+	}
+	svg, err := b.renderSVG()
+	if err != nil {
+		return err
+	}
+	return b.tmpl.ExecuteTemplate(w, "graph.html.tmpl", struct{ SVG string }{svg})
+}
+
+func (b *ClusterBuilder) renderSVG() (string, error) {
+	var nodes []*svgNode
+	if b.Main != nil {
+		nodes = collectSVGNodes(b.Main, nil, nil)
+	}
+
+	nodeIdx := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		nodeIdx[n.node.Name] = i
+	}
+
+	g := layout.Graph{Nodes: make([]layout.Node, len(nodes))}
+	for i, n := range nodes {
+		g.Nodes[i] = layout.Node{Width: n.width, Height: n.height}
+	}
+
+	links := map[string][]string{}
+	for _, edge := range b.Edges {
+		from, ok := nodeIdx[trimPortPath(edge.Send.Path)]
+		if !ok {
+			return "", fmt.Errorf("sender node not found: %v", edge.Send.PortAddr)
+		}
+		to, ok := nodeIdx[trimPortPath(edge.Recv.Path)]
+		if !ok {
+			return "", fmt.Errorf("receiver node not found: %v", edge.Recv.PortAddr)
+		}
+		g.Edges = append(g.Edges, layout.Edge{From: from, To: to})
+
+		sendAddr, recvAddr := edge.Send.PortAddr.String(), edge.Recv.PortAddr.String()
+		links[sendAddr] = append(links[sendAddr], recvAddr)
+		links[recvAddr] = append(links[recvAddr], sendAddr)
+	}
+
+	result := layout.Layout(g, layout.DefaultOptions())
+	for i, n := range nodes {
+		n.x, n.y = result.Nodes[i].X, result.Nodes[i].Y
+	}
+
+	var clusters strings.Builder
+	bounds := svgRect{0, 0, result.Width, result.Height}
+	if b.Main != nil {
+		for _, cluster := range b.Main.sortedClusters() {
+			rect := writeSVGCluster(&clusters, cluster, nodes)
+			bounds = bounds.union(rect)
+		}
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(
+		&sb,
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="%.1f %.1f %.1f %.1f" width="%.0f" height="%.0f" font-family="monospace" font-size="%d">`+"\n",
+		bounds.x1-svgMargin,
+		bounds.y1-svgMargin,
+		bounds.x2-bounds.x1+2*svgMargin,
+		bounds.y2-bounds.y1+2*svgMargin,
+		bounds.x2-bounds.x1+2*svgMargin,
+		bounds.y2-bounds.y1+2*svgMargin,
+		svgFontSize,
+	)
+	sb.WriteString(`<defs><marker id="arrow" viewBox="0 0 8 8" refX="8" refY="4" markerWidth="8" markerHeight="8" orient="auto"><path d="M0,0 L8,4 L0,8 z"/></marker></defs>` + "\n")
+
+	sb.WriteString(`<g class="clusters">` + "\n")
+	sb.WriteString(clusters.String())
+	sb.WriteString("</g>\n")
+
+	sb.WriteString(`<g class="edges" fill="none" stroke="black">` + "\n")
+	for i, edge := range b.Edges {
+		from := nodes[g.Edges[i].From].portPoint(edge.Send)
+		to := nodes[g.Edges[i].To].portPoint(edge.Recv)
+
+		var d strings.Builder
+		fmt.Fprintf(&d, "M%.1f,%.1f", from.X, from.Y)
+		for _, p := range result.Bends[i] {
+			fmt.Fprintf(&d, " L%.1f,%.1f", p.X, p.Y)
+		}
+		fmt.Fprintf(&d, " L%.1f,%.1f", to.X, to.Y)
+
+		fmt.Fprintf(
+			&sb,
+			`<path class="edge" d="%s" data-from="%s" data-to="%s" marker-end="url(#arrow)"/>`+"\n",
+			d.String(),
+			html.EscapeString(edge.Send.PortAddr.String()),
+			html.EscapeString(edge.Recv.PortAddr.String()),
+		)
+	}
+	sb.WriteString("</g>\n")
+
+	sb.WriteString(`<g class="nodes">` + "\n")
+	for _, n := range nodes {
+		n.write(&sb, links)
+	}
+	sb.WriteString("</g>\n")
+
+	sb.WriteString("</svg>\n")
+
+	return sb.String(), nil
+}
+
+// collectSVGNodes returns nodes of the cluster and its sub-clusters in deterministic order.
+func collectSVGNodes(c *Cluster, parents []*Cluster, result []*svgNode) []*svgNode {
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		result = append(result, newSVGNode(c.Nodes[name], parents))
+	}
+
+	for _, sub := range c.sortedClusters() {
+		result = collectSVGNodes(sub, append(append([]*Cluster{}, parents...), sub), result)
+	}
+
+	return result
+}
+
+func (c *Cluster) sortedClusters() []*Cluster {
+	names := make([]string, 0, len(c.Clusters))
+	for name := range c.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]*Cluster, len(names))
+	for i, name := range names {
+		result[i] = c.Clusters[name]
+	}
+	return result
+}
+
+func newSVGNode(node *Node, clusters []*Cluster) *svgNode {
+	n := &svgNode{
+		node:     node,
+		clusters: clusters,
+		in:       sortedPorts(node.In),
+		out:      sortedPorts(node.Out),
+	}
+
+	n.width = math.Max(
+		textWidth(node.FormatLabel())+2*svgPadding,
+		math.Max(portsRowWidth(n.in), portsRowWidth(n.out)),
+	)
+	n.height = svgPortHeight + svgTitleHeight + svgPortHeight
+
+	return n
+}
+
+func sortedPorts(ports map[Port]struct{}) []Port {
+	result := make([]Port, 0, len(ports))
+	for port := range ports {
+		result = append(result, port)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Port != result[j].Port {
+			return result[i].Port < result[j].Port
+		}
+		return result[i].Idx < result[j].Idx
+	})
+	return result
+}
+
+func textWidth(s string) float64 {
+	return float64(len([]rune(html.UnescapeString(s)))) * svgCharWidth
+}
+
+func portWidth(port Port) float64 {
+	return textWidth(port.FormatLabel()) + svgPadding
+}
+
+func portsRowWidth(ports []Port) float64 {
+	width := 2 * svgPadding
+	for i, port := range ports {
+		if i > 0 {
+			width += svgPortGap
+		}
+		width += portWidth(port)
+	}
+	return width
+}
+
+// portPoint returns point where edge is attached to the port: top of inport or bottom of outport.
+// Port is identified by its path so sender can be an inport (e.g. of the component itself) and vice versa.
+func (n *svgNode) portPoint(port Port) layout.Point {
+	ports, y := n.out, n.y+n.height
+	if strings.HasSuffix(port.Path, "/in") {
+		ports, y = n.in, n.y
+	}
+
+	x := n.x + svgPadding
+	for i, p := range ports {
+		if i > 0 {
+			x += svgPortGap
+		}
+		if p == port {
+			return layout.Point{X: x + portWidth(p)/2, Y: y}
+		}
+		x += portWidth(p)
+	}
+
+	return layout.Point{X: n.x + n.width/2, Y: y}
+}
+
+func (n *svgNode) write(sb *strings.Builder, links map[string][]string) {
+	fmt.Fprintf(sb, `<g class="node" data-name="%s">`+"\n", html.EscapeString(n.node.Name))
+
+	fmt.Fprintf(
+		sb,
+		`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="6" fill="white" stroke="black"/>`+"\n",
+		n.x, n.y+svgPortHeight, n.width, svgTitleHeight,
+	)
+	fmt.Fprintf(
+		sb,
+		`<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+		n.x+n.width/2, n.y+svgPortHeight+svgTitleHeight/2, html.EscapeString(n.node.FormatLabel()),
+	)
+
+	writeSVGPorts(sb, n.in, n.x, n.y, links)
+	writeSVGPorts(sb, n.out, n.x, n.y+svgPortHeight+svgTitleHeight, links)
+
+	sb.WriteString("</g>\n")
+}
+
+func writeSVGPorts(sb *strings.Builder, ports []Port, x, y float64, links map[string][]string) {
+	x += svgPadding
+	for i, port := range ports {
+		if i > 0 {
+			x += svgPortGap
+		}
+		addr := port.PortAddr.String()
+		width := portWidth(port)
+		fmt.Fprintf(
+			sb,
+			`<g class="port" data-addr="%s" data-links="%s"><title>%s</title>`+
+				`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="white" stroke="black"/>`+
+				`<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central">%s</text></g>`+"\n",
+			html.EscapeString(addr),
+			html.EscapeString(strings.Join(links[addr], ", ")),
+			html.EscapeString(addr),
+			x, y, width, svgPortHeight,
+			x+width/2, y+svgPortHeight/2, port.FormatLabel(),
+		)
+		x += width
+	}
+}
+
+// writeSVGCluster writes cluster with all its sub-clusters and returns its bounds.
+// Outer clusters are written first so inner ones are drawn on top of them.
+func writeSVGCluster(sb *strings.Builder, c *Cluster, nodes []*svgNode) svgRect {
+	rect, ok := clusterBounds(c, nodes)
+	if !ok {
+		return svgRect{}
+	}
+
+	fmt.Fprintf(
+		sb,
+		`<g class="cluster"><rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="none" stroke="gray" stroke-dasharray="4 2"/>`+
+			`<text x="%.1f" y="%.1f" fill="gray">%s</text></g>`+"\n",
+		rect.x1, rect.y1, rect.x2-rect.x1, rect.y2-rect.y1,
+		rect.x1+svgPadding, rect.y1+svgClusterLabelH-4, html.EscapeString(c.Label()),
+	)
+
+	for _, sub := range c.sortedClusters() {
+		writeSVGCluster(sb, sub, nodes)
+	}
+
+	return rect
+}
+
+// clusterBounds returns rectangle that contains all nodes of the cluster with padding.
+// Padding grows with nesting so inner clusters fit into outer ones.
+func clusterBounds(c *Cluster, nodes []*svgNode) (svgRect, bool) {
+	var (
+		rect  svgRect
+		found bool
+	)
+
+	for _, n := range nodes {
+		for i, nc := range n.clusters {
+			if nc != c {
+				continue
+			}
+			nodeRect := svgRect{n.x, n.y, n.x + n.width, n.y + n.height}
+			// the deeper node is nested relative to the cluster, the more space it needs
+			pad := svgClusterPad * float64(len(n.clusters)-i)
+			nodeRect = svgRect{
+				nodeRect.x1 - pad,
+				nodeRect.y1 - pad - svgClusterLabelH*float64(len(n.clusters)-i),
+				nodeRect.x2 + pad,
+				nodeRect.y2 + pad,
+			}
+			if !found {
+				rect, found = nodeRect, true
+			} else {
+				rect = rect.union(nodeRect)
+			}
+		}
+	}
+
+	return rect, found
+}
+
+func (r svgRect) union(other svgRect) svgRect {
+	return svgRect{
+		x1: math.Min(r.x1, other.x1),
+		y1: math.Min(r.y1, other.y1),
+		x2: math.Max(r.x2, other.x2),
+		y2: math.Max(r.y2, other.y2),
+	}
+}
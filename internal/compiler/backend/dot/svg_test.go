@@ -0,0 +1,65 @@
+package dot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/internal/compiler/ir"
+)
+
+func testClusterBuilder() *ClusterBuilder {
+	var b ClusterBuilder
+	b.InsertEdge(
+		ir.PortAddr{Path: "in", Port: "start"},
+		ir.PortAddr{Path: "printer/in", Port: "data"},
+	)
+	b.InsertEdge(
+		ir.PortAddr{Path: "printer/out", Port: "res"},
+		ir.PortAddr{Path: "printer/lock/in", Port: "sig"},
+	)
+	b.InsertEdge(
+		ir.PortAddr{Path: "printer/lock/out", Port: "data"},
+		ir.PortAddr{Path: "out", Port: "stop"},
+	)
+	b.InsertEdge(
+		ir.PortAddr{Path: "printer/out", Port: "err", IsArray: true, Idx: 1},
+		ir.PortAddr{Path: "out", Port: "stop"},
+	)
+	return &b
+}
+
+func TestClusterBuilder_BuildSVG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testClusterBuilder().BuildSVG(&buf))
+
+	svg := buf.String()
+	require.Equal(t, 4, strings.Count(svg, `class="node"`))
+	require.Equal(t, 4, strings.Count(svg, `class="edge"`))
+	require.Equal(t, 1, strings.Count(svg, `class="cluster"`))
+	require.Contains(t, svg, `data-addr="printer/out:err[1]"`)
+	require.Contains(t, svg, `data-links="printer/lock/in:sig"`)
+
+	decoder := xml.NewDecoder(&buf)
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestClusterBuilder_BuildHTML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testClusterBuilder().BuildHTML(&buf))
+
+	page := buf.String()
+	require.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+	require.Contains(t, page, "<svg ")
+	require.Contains(t, page, "<script>")
+}
@@ -48,10 +48,18 @@ func (b Backend) Emit(dst string, prog *ir.Program, trace bool) error {
 		return err
 	}
 
+	files := map[string][]byte{}
+
+	natives, err := b.insertNativeFiles(files, prog.Natives)
+	if err != nil {
+		return err
+	}
+
 	tplData := templateData{
 		CompilerVersion: pkg.Version,
 		ChanVarNames:    chanVarNames,
 		FuncCalls:       funcCalls,
+		Natives:         natives,
 		Trace:           trace,
 	}
 
@@ -60,7 +68,6 @@ func (b Backend) Emit(dst string, prog *ir.Program, trace bool) error {
 		return errors.Join(ErrExecTmpl, err)
 	}
 
-	files := map[string][]byte{}
 	files["main.go"] = buf.Bytes()
 	files["go.mod"] = []byte("module github.com/nevalang/neva/internal\n\ngo 1.23") //nolint:lll // must match imports in runtime package
 
@@ -0,0 +1,168 @@
+package golang
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/ir"
+	"github.com/nevalang/neva/pkg"
+)
+
+// EmitLibrary generates go module with package that exports given component.
+// Unlike executable, library module is meant to be imported by other go modules,
+// so its path is configurable and runtime is placed under it.
+func (b Backend) EmitLibrary(dst string, prog *ir.Program, lib compiler.Library, trace bool) error {
+	prog.Connections = ir.GraphReduction(prog.Connections)
+
+	addrToChanVar, chanVarNames := b.buildPortChanMap(prog.Connections)
+	funcCalls, err := b.buildFuncCalls(prog.Funcs, addrToChanVar)
+	if err != nil {
+		return err
+	}
+
+	modulePath := lib.ModulePath
+	if modulePath == "" {
+		modulePath = strings.ToLower(lib.Component)
+	}
+
+	files := map[string][]byte{}
+
+	natives, err := b.insertNativeFiles(files, prog.Natives)
+	if err != nil {
+		return err
+	}
+	for i := range natives {
+		natives[i].ImportPath = modulePath + "/" + strings.TrimPrefix(natives[i].ImportPath, internalImportPrefix)
+	}
+
+	tplData := libTemplateData{
+		CompilerVersion: pkg.Version,
+		ModulePath:      modulePath,
+		PackageName:     getPackageName(modulePath),
+		Component:       exportedIdent(lib.Component),
+		ChanVarNames:    chanVarNames,
+		FuncCalls:       funcCalls,
+		Natives:         natives,
+		Trace:           trace,
+	}
+
+	for _, port := range lib.In {
+		tplPort, err := b.getLibTemplatePort(port, ir.PortAddr{Path: "in", Port: port.Name}, addrToChanVar)
+		if err != nil {
+			return err
+		}
+		tplData.In = append(tplData.In, tplPort)
+	}
+
+	for _, port := range lib.Out {
+		tplPort, err := b.getLibTemplatePort(port, ir.PortAddr{Path: "out", Port: port.Name}, addrToChanVar)
+		if err != nil {
+			return err
+		}
+		tplData.Out = append(tplData.Out, tplPort)
+	}
+
+	tmpl, err := template.New("lib.go").Parse(libGoTemplate)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, tplData); err != nil {
+		return errors.Join(ErrExecTmpl, err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated code: %w", err)
+	}
+
+	files[tplData.PackageName+".go"] = formatted
+	files["go.mod"] = []byte(fmt.Sprintf("module %s\n\ngo 1.23", modulePath))
+
+	if err := b.insertRuntimeFiles(files); err != nil {
+		return err
+	}
+
+	// runtime and native files import each other by paths that only work inside executable's module
+	for path, content := range files {
+		if strings.Contains(path, "/") {
+			files[path] = bytes.ReplaceAll(
+				content,
+				[]byte(`"`+internalImportPrefix),
+				[]byte(`"`+modulePath+"/"),
+			)
+		}
+	}
+
+	return compiler.SaveFilesToDir(dst, files)
+}
+
+func (b Backend) getLibTemplatePort(
+	port compiler.LibraryPort,
+	addr ir.PortAddr,
+	addrToChanVar map[ir.PortAddr]string,
+) (libTemplatePort, error) {
+	chanVar, ok := addrToChanVar[addr]
+	if !ok {
+		return libTemplatePort{}, fmt.Errorf("port chan not found: %v", addr)
+	}
+
+	result := libTemplatePort{
+		Name:   port.Name,
+		Method: exportedIdent(port.Name),
+		Field:  addr.Path + exportedIdent(port.Name),
+		Chan:   chanVar,
+		GoType: "runtime.Msg",
+	}
+
+	if port.TypeExpr.Inst == nil {
+		return result, nil
+	}
+
+	// primitive types are converted to and from go values, everything else is passed as is
+	switch port.TypeExpr.Inst.Ref.String() {
+	case "bool":
+		result.GoType, result.ToMsg, result.FromMsg = "bool", "runtime.NewBoolMsg", ".Bool()"
+	case "int":
+		result.GoType, result.ToMsg, result.FromMsg = "int64", "runtime.NewIntMsg", ".Int()"
+	case "float":
+		result.GoType, result.ToMsg, result.FromMsg = "float64", "runtime.NewFloatMsg", ".Float()"
+	case "string":
+		result.GoType, result.ToMsg, result.FromMsg = "string", "runtime.NewStringMsg", ".Str()"
+	}
+
+	return result, nil
+}
+
+// getPackageName turns last element of the module path into valid go package name.
+func getPackageName(modulePath string) string {
+	name := modulePath[strings.LastIndex(modulePath, "/")+1:]
+
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+
+	result := sb.String()
+	if result == "" || unicode.IsDigit(rune(result[0])) {
+		result = "neva" + result
+	}
+
+	return result
+}
+
+func exportedIdent(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,42 @@
+package golang
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPackageName(t *testing.T) {
+	tests := []struct {
+		name       string
+		modulePath string
+		expected   string
+	}{
+		{
+			name:       "single_element",
+			modulePath: "adder",
+			expected:   "adder",
+		},
+		{
+			name:       "nested_path",
+			modulePath: "github.com/acme/adder",
+			expected:   "adder",
+		},
+		{
+			name:       "special_chars",
+			modulePath: "github.com/acme/my-Adder.go",
+			expected:   "myaddergo",
+		},
+		{
+			name:       "leading_digit",
+			modulePath: "example.com/42",
+			expected:   "neva42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getPackageName(tt.modulePath))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/nevalang/neva/internal/compiler/ir"
+	"github.com/nevalang/neva/pkg"
+)
+
+const (
+	// publicRuntimeImportPath is import path that native code uses to access runtime.
+	publicRuntimeImportPath = "github.com/nevalang/neva/pkg/runtime"
+	// internalImportPrefix is a prefix of all import paths inside generated go module for executables.
+	internalImportPrefix = "github.com/nevalang/neva/internal/"
+)
+
+// templateNative is a native package of some Neva module, imported by generated code.
+type templateNative struct {
+	Module     string // Path of the Neva module, used to qualify func refs.
+	Alias      string // Name of the import.
+	ImportPath string
+}
+
+// insertNativeFiles puts native packages and public runtime into files
+// and returns information needed to import and register them.
+// Native packages must export `func Registry() runtime.Registry`.
+func (b Backend) insertNativeFiles(files map[string][]byte, natives []ir.NativePackage) ([]templateNative, error) {
+	if len(natives) == 0 {
+		return nil, nil
+	}
+
+	if err := fs.WalkDir(
+		pkg.Efs,
+		"runtime",
+		func(path string, dirEntry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if dirEntry.IsDir() || strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+
+			bb, err := pkg.Efs.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			files["pkg/"+path] = bb
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	result := make([]templateNative, 0, len(natives))
+	for i, native := range natives {
+		dir := fmt.Sprintf("natives/n%d", i)
+
+		for name, content := range native.Files {
+			files[dir+"/"+name] = bytes.ReplaceAll(
+				content,
+				[]byte(`"`+publicRuntimeImportPath+`"`),
+				[]byte(`"`+internalImportPrefix+"pkg/runtime"+`"`),
+			)
+		}
+
+		result = append(result, templateNative{
+			Module:     native.Module,
+			Alias:      fmt.Sprintf("n%d", i),
+			ImportPath: internalImportPrefix + dir,
+		})
+	}
+
+	return result, nil
+}
@@ -4,6 +4,7 @@ type templateData struct {
 	CompilerVersion string
 	ChanVarNames    []string
 	FuncCalls       []templateFuncCall
+	Natives         []templateNative
 	Trace           bool
 }
 
@@ -28,6 +29,9 @@ import (
 
     "github.com/nevalang/neva/internal/runtime"
     "github.com/nevalang/neva/internal/runtime/funcs"
+    {{- range .Natives}}
+    {{.Alias}} "{{.ImportPath}}"
+    {{- end}}
 )
 
 func main() {
@@ -90,15 +94,214 @@ func main() {
         {{- end}}
     }
 
+    registry := funcs.NewRegistry()
+    {{- range .Natives}}
+    for name, creator := range {{.Alias}}.Registry() {
+        registry["{{.Module}}."+name] = creator
+    }
+    {{- end}}
+
     rprog := runtime.Program{
         Start: startPort,
         Stop: stopPort,
         FuncCalls: funcCalls,
     }
     
-    if err := runtime.Run(context.Background(), rprog, funcs.NewRegistry()); err != nil {
+    if err := runtime.Run(context.Background(), rprog, registry); err != nil {
 		fmt.Fprintln(os.Stderr, "runtime error:", err.Error())
 		os.Exit(1)
 	}
 }
 `
+
+type libTemplateData struct {
+	CompilerVersion string
+	ModulePath      string
+	PackageName     string
+	Component       string
+	ChanVarNames    []string
+	FuncCalls       []templateFuncCall
+	Natives         []templateNative
+	In              []libTemplatePort
+	Out             []libTemplatePort
+	Trace           bool
+}
+
+type libTemplatePort struct {
+	Name    string // port name in neva
+	Method  string // suffix for Send/Receive methods
+	Field   string
+	Chan    string
+	GoType  string
+	ToMsg   string // constructor of runtime.Msg, empty if GoType is runtime.Msg
+	FromMsg string // method of runtime.Msg, empty if GoType is runtime.Msg
+}
+
+var libGoTemplate = `// Code generated by Neva v{{.CompilerVersion}}. DO NOT EDIT.
+
+// Package {{.PackageName}} exports Neva component {{.Component}}.
+package {{.PackageName}}
+
+import (
+    "context"
+
+    "{{.ModulePath}}/runtime"
+    "{{.ModulePath}}/runtime/funcs"
+    {{- range .Natives}}
+    {{.Alias}} "{{.ImportPath}}"
+    {{- end}}
+)
+
+// {{.Component}} is a running instance of Neva component.
+// It's safe to send and receive messages from different goroutines.
+type {{.Component}} struct {
+    {{- range .In}}
+    {{.Field}} *runtime.SingleOutport
+    {{- end}}
+    {{- range .Out}}
+    {{.Field}} *runtime.SingleInport
+    {{- end}}
+    ctx    context.Context
+    cancel context.CancelFunc
+    done   <-chan struct{}
+    {{- if .Trace}}
+    closeTrace func() error
+    {{- end}}
+}
+
+// New{{.Component}} creates {{.Component}} and starts it in background.
+// Component works until Close is called or given context is done.
+func New{{.Component}}(ctx context.Context) (*{{.Component}}, error) {
+    var (
+        {{- range .ChanVarNames}}
+        {{.}} = make(chan runtime.OrderedMsg)
+        {{- end}}
+    )
+    {{- if .Trace }}
+
+    interceptor := runtime.NewDebugInterceptor()
+
+    closeTrace, err := interceptor.Open("trace.log")
+    if err != nil {
+        return nil, err
+    }
+    {{- else }}
+
+    interceptor := runtime.ProdInterceptor{}
+    {{- end }}
+
+    funcCalls := []runtime.FuncCall{
+        {{- range .FuncCalls}}
+        {
+            Ref: "{{.Ref}}",
+            IO: runtime.IO{
+                In: runtime.NewInports(map[string]runtime.Inport{
+                    {{- range $key, $value := .IO.In}}
+                    "{{$key}}": {{$value}},
+                    {{- end}}
+                }),
+                Out: runtime.NewOutports(map[string]runtime.Outport{
+                    {{- range $key, $value := .IO.Out}}
+                    "{{$key}}": {{$value}},
+                    {{- end}}
+                }),
+            },
+            Config: {{.Config}},
+        },
+        {{- end}}
+    }
+
+    registry := funcs.NewRegistry()
+    {{- range .Natives}}
+    for name, creator := range {{.Alias}}.Registry() {
+        registry["{{.Module}}."+name] = creator
+    }
+    {{- end}}
+
+    ctx, cancel := context.WithCancel(ctx)
+
+    done, err := runtime.RunFuncCalls(ctx, cancel, funcCalls, registry)
+    if err != nil {
+        cancel()
+        {{- if .Trace}}
+        _ = closeTrace()
+        {{- end}}
+        return nil, err
+    }
+
+    return &{{.Component}}{
+        {{- range .In}}
+        {{.Field}}: runtime.NewSingleOutport(
+            runtime.PortAddr{Path: "in", Port: "{{.Name}}"},
+            interceptor,
+            {{.Chan}},
+        ),
+        {{- end}}
+        {{- range .Out}}
+        {{.Field}}: runtime.NewSingleInport(
+            {{.Chan}},
+            runtime.PortAddr{Path: "out", Port: "{{.Name}}"},
+            interceptor,
+        ),
+        {{- end}}
+        ctx:    ctx,
+        cancel: cancel,
+        done:   done,
+        {{- if .Trace}}
+        closeTrace: closeTrace,
+        {{- end}}
+    }, nil
+}
+{{- $component := .Component}}
+{{- range .In}}
+
+// Send{{.Method}} sends message to '{{.Name}}' inport.
+// It blocks until message is received and returns false if component or given context is done.
+func (c *{{$component}}) Send{{.Method}}(ctx context.Context, v {{.GoType}}) bool {
+    ctx, cancel := c.withCancel(ctx)
+    defer cancel()
+    return c.{{.Field}}.Send(ctx, {{if .ToMsg}}{{.ToMsg}}(v){{else}}v{{end}})
+}
+{{- end}}
+{{- range .Out}}
+
+// Receive{{.Method}} receives message from '{{.Name}}' outport.
+// It blocks until message is sent and returns false if component or given context is done.
+func (c *{{$component}}) Receive{{.Method}}(ctx context.Context) ({{.GoType}}, bool) {
+    ctx, cancel := c.withCancel(ctx)
+    defer cancel()
+    msg, ok := c.{{.Field}}.Receive(ctx)
+    if !ok {
+        var zero {{.GoType}}
+        return zero, false
+    }
+    return msg{{.FromMsg}}, true
+}
+{{- end}}
+
+// Done returns channel that is closed when component is stopped.
+func (c *{{.Component}}) Done() <-chan struct{} {
+    return c.done
+}
+
+// Close stops component and waits until all its functions are finished.
+func (c *{{.Component}}) Close() error {
+    c.cancel()
+    <-c.done
+    {{- if .Trace}}
+    return c.closeTrace()
+    {{- else}}
+    return nil
+    {{- end}}
+}
+
+// withCancel returns context that is done when either given context or component is done.
+func (c *{{.Component}}) withCancel(ctx context.Context) (context.Context, context.CancelFunc) {
+    ctx, cancel := context.WithCancel(ctx)
+    stop := context.AfterFunc(c.ctx, cancel)
+    return ctx, func() {
+        stop()
+        cancel()
+    }
+}
+`
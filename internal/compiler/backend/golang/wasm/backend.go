@@ -11,6 +11,7 @@ import (
 
 type Backend struct {
 	golang golang.Backend
+	goos   string
 }
 
 func (b Backend) Emit(dst string, prog *ir.Program, trace bool) error {
@@ -18,7 +19,7 @@ func (b Backend) Emit(dst string, prog *ir.Program, trace bool) error {
 	if err := b.golang.Emit(tmpGoProj, prog, trace); err != nil {
 		return err
 	}
-	if err := buildWASM(tmpGoProj, dst); err != nil {
+	if err := buildWASM(tmpGoProj, dst, b.goos); err != nil {
 		return err
 	}
 	if err := os.RemoveAll(tmpGoProj); err != nil {
@@ -27,9 +28,10 @@ func (b Backend) Emit(dst string, prog *ir.Program, trace bool) error {
 	return nil
 }
 
-func buildWASM(src, dst string) error {
-	outputPath := filepath.Join(dst, "output")
-	if err := os.Chdir(src); err != nil {
+func buildWASM(src, dst, goos string) error {
+	// go build is executed inside generated module so output path must not be relative
+	outputPath, err := filepath.Abs(filepath.Join(dst, "output"))
+	if err != nil {
 		return err
 	}
 	cmd := exec.Command(
@@ -37,16 +39,27 @@ func buildWASM(src, dst string) error {
 		"build",
 		"-ldflags", "-s -w", // for optimization
 		"-o", outputPath+".wasm",
-		src,
+		".",
 	)
-	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	cmd.Dir = src
+	cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH=wasm")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// NewBackend creates backend that produces wasm for javascript hosts.
 func NewBackend(golangBackend golang.Backend) Backend {
 	return Backend{
 		golang: golangBackend,
+		goos:   "js",
+	}
+}
+
+// NewWASIBackend creates backend that produces wasm for WASI (preview 1) hosts.
+func NewWASIBackend(golangBackend golang.Backend) Backend {
+	return Backend{
+		golang: golangBackend,
+		goos:   "wasip1",
 	}
 }
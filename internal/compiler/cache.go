@@ -0,0 +1,211 @@
+package compiler
+
+import (
+	"sort"
+	"sync"
+
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	"github.com/nevalang/neva/pkg"
+)
+
+// PackageKeys are cache keys of parsed packages by modules and package names.
+type PackageKeys map[core.ModuleRef]map[string]string
+
+// parseKey returns cache key of the parsed package.
+// Parsed package only depends on its files and location.
+func parseKey(cache Cache, modRef core.ModuleRef, pkgName string, files RawPackage) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := []string{"parsed", modRef.String(), pkgName}
+	for _, name := range names {
+		parts = append(parts, name, string(files[name]))
+	}
+
+	return cache.Key(parts...)
+}
+
+// parse parses modules of the build, packages that were parsed before are loaded from cache.
+func (f Frontend) parse(raw RawBuild) (map[core.ModuleRef]src.Module, PackageKeys, *Error) {
+	if f.cache == nil {
+		parsed, err := f.parser.ParseModules(raw.Modules)
+		return parsed, nil, err
+	}
+
+	keys := make(PackageKeys, len(raw.Modules))
+	parsed := make(map[core.ModuleRef]src.Module, len(raw.Modules))
+	missing := map[core.ModuleRef]RawModule{}
+
+	for modRef, rawMod := range raw.Modules {
+		keys[modRef] = make(map[string]string, len(rawMod.Packages))
+		parsed[modRef] = src.Module{
+			Manifest: rawMod.Manifest,
+			Packages: make(map[string]src.Package, len(rawMod.Packages)),
+			Native:   rawMod.Native,
+		}
+
+		for pkgName, files := range rawMod.Packages {
+			key := parseKey(f.cache, modRef, pkgName, files)
+			keys[modRef][pkgName] = key
+
+			var pkg src.Package
+			if f.cache.Get(key, &pkg) {
+				parsed[modRef].Packages[pkgName] = pkg
+				continue
+			}
+
+			if _, ok := missing[modRef]; !ok {
+				missing[modRef] = RawModule{
+					Manifest: rawMod.Manifest,
+					Packages: map[string]RawPackage{},
+					Native:   rawMod.Native,
+				}
+			}
+			missing[modRef].Packages[pkgName] = files
+		}
+	}
+
+	if len(missing) == 0 {
+		return parsed, keys, nil
+	}
+
+	parsedMissing, err := f.parser.ParseModules(missing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for modRef, mod := range parsedMissing {
+		for pkgName, pkg := range mod.Packages {
+			parsed[modRef].Packages[pkgName] = pkg
+			_ = f.cache.Put(keys[modRef][pkgName], pkg) // caching is best effort
+		}
+	}
+
+	return parsed, keys, nil
+}
+
+// analyzerFor returns analyzer that loads analysis results of unchanged packages from cache,
+// if both the analyzer and the compiler support caching.
+func (m Middleend) analyzerFor(feResult FrontendResult) Analyzer {
+	caching, ok := m.analyzer.(CachingAnalyzer)
+	if !ok || m.cache == nil || feResult.PackageKeys == nil {
+		return m.analyzer
+	}
+
+	return caching.WithCache(&analysisCache{
+		cache:     m.cache,
+		build:     feResult.ParsedBuild,
+		parseKeys: feResult.PackageKeys,
+		keys:      map[pkgRef]string{},
+	})
+}
+
+type pkgRef struct {
+	modRef core.ModuleRef
+	name   string
+}
+
+// analysisCache implements PackageCache for a single build.
+// Analyzed package depends on its own files and files of every package it transitively imports,
+// so its key covers parse keys of all of them.
+type analysisCache struct {
+	cache     Cache
+	build     src.Build
+	parseKeys PackageKeys
+
+	mu   sync.Mutex
+	keys map[pkgRef]string // empty key means package can't be cached
+}
+
+func (a *analysisCache) Get(modRef core.ModuleRef, pkgName string) (src.Package, bool) {
+	key := a.key(pkgRef{modRef, pkgName})
+	if key == "" {
+		return nil, false
+	}
+
+	var pkg src.Package
+	if !a.cache.Get(key, &pkg) {
+		return nil, false
+	}
+
+	return pkg, true
+}
+
+func (a *analysisCache) Put(modRef core.ModuleRef, pkgName string, pkg src.Package) {
+	if key := a.key(pkgRef{modRef, pkgName}); key != "" {
+		_ = a.cache.Put(key, pkg) // caching is best effort
+	}
+}
+
+func (a *analysisCache) key(ref pkgRef) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if key, ok := a.keys[ref]; ok {
+		return key
+	}
+
+	deps := map[pkgRef]bool{}
+	key := ""
+	if a.collectDeps(ref, deps) {
+		parts := make([]string, 0, len(deps)+1)
+		for dep := range deps {
+			parts = append(parts, dep.modRef.String()+" "+dep.name+" "+a.parseKeys[dep.modRef][dep.name])
+		}
+		sort.Strings(parts)
+		key = a.cache.Key(append([]string{"analyzed", ref.modRef.String(), ref.name}, parts...)...)
+	}
+
+	a.keys[ref] = key
+
+	return key
+}
+
+// collectDeps adds the package and every package it transitively depends on to deps.
+// It reports false if any of them can't be found, such packages are never cached.
+func (a *analysisCache) collectDeps(ref pkgRef, deps map[pkgRef]bool) bool {
+	if deps[ref] {
+		return true
+	}
+
+	mod, ok := a.build.Modules[ref.modRef]
+	if !ok {
+		return false
+	}
+	files, ok := mod.Packages[ref.name]
+	if !ok {
+		return false
+	}
+	if _, ok := a.parseKeys[ref.modRef][ref.name]; !ok {
+		return false
+	}
+
+	deps[ref] = true
+
+	// builtin package is implicitly imported by every package
+	imported := []pkgRef{{core.ModuleRef{Path: "std", Version: pkg.Version}, "builtin"}}
+	for _, file := range files {
+		for _, imp := range file.Imports {
+			depModRef := ref.modRef
+			if imp.Module != "@" {
+				depModRef, ok = mod.Manifest.Deps[imp.Module]
+				if !ok {
+					return false
+				}
+			}
+			imported = append(imported, pkgRef{depModRef, imp.Package})
+		}
+	}
+
+	for _, dep := range imported {
+		if !a.collectDeps(dep, deps) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+// Package cache implements content-addressed storage of compilation results on disk.
+// Entries are never invalidated, instead keys cover everything the results depend on,
+// so changed inputs simply produce new keys. Entries that are not used for a while are trimmed.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nevalang/neva/pkg"
+)
+
+// Cache stores JSON encoded values in a directory.
+// It's safe to share the directory between concurrent builds.
+type Cache struct {
+	dir  string
+	salt string
+}
+
+// New returns cache that stores entries in the given directory.
+// Keys are salted with identity of the running executable
+// so results of different compiler builds never mix even if their versions are the same.
+func New(dir string) Cache {
+	return Cache{
+		dir:  dir,
+		salt: compilerID(),
+	}
+}
+
+// Dir returns directory of the cache and reports whether caching is enabled.
+// It's taken from NEVACACHE environment variable, "off" disables caching.
+// By default cache is stored in the neva directory in user's home.
+func Dir() (string, bool) {
+	dir := os.Getenv("NEVACACHE")
+	if dir == "off" {
+		return "", false
+	}
+	if dir != "" {
+		return dir, true
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+
+	return filepath.Join(home, "neva", "cache"), true
+}
+
+// Key returns key of the entry that depends on the given parts.
+func (c Cache) Key(parts ...string) string {
+	h := sha256.New()
+	for _, part := range append([]string{c.salt}, parts...) {
+		// length prefix makes sure different parts never produce the same input
+		fmt.Fprintf(h, "%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+const (
+	// maxUnusedAge is how long entry stays in the cache after it was used last time.
+	maxUnusedAge = 5 * 24 * time.Hour
+	// trimInterval is how often cache is checked for unused entries.
+	trimInterval = 24 * time.Hour
+	// touchInterval is how often modification time of used entry is updated,
+	// it's used as last usage time of the entry.
+	touchInterval = time.Hour
+	// trimFile stores time of the last trim.
+	trimFile = "trim.txt"
+)
+
+// Get decodes entry with the given key into v and reports whether it was found.
+// Entries that can't be decoded are treated as missing.
+func (c Cache) Get(key string, v any) bool {
+	path := c.path(key)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if json.Unmarshal(data, v) != nil {
+		return false
+	}
+
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > touchInterval {
+		now := time.Now()
+		_ = os.Chtimes(path, now, now) // entry is still usable if it fails
+	}
+
+	return true
+}
+
+// Trim removes entries that were not used for maxUnusedAge.
+// It does nothing if the cache was trimmed less than trimInterval ago, so it's cheap to call after every build.
+func (c Cache) Trim(now time.Time) error {
+	marker := filepath.Join(c.dir, trimFile)
+
+	if data, err := os.ReadFile(marker); err == nil {
+		if last, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil &&
+			now.Sub(time.Unix(last, 0)) < trimInterval {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	// marker is written first so concurrent builds don't trim at the same time
+	if err := os.WriteFile(marker, []byte(strconv.FormatInt(now.Unix(), 10)), 0644); err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path == marker {
+			return nil // entries removed by other process are fine
+		}
+		info, err := d.Info()
+		if err != nil || now.Sub(info.ModTime()) <= maxUnusedAge {
+			return nil
+		}
+		return os.Remove(path)
+	})
+}
+
+// Put stores v under the given key.
+// Entry is written to temporary file first so readers never see partially written entries.
+func (c Cache) Put(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// path spreads entries over subdirectories to keep directories small.
+func (c Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+// compilerID identifies the running compiler by its version and executable.
+// Development builds often share the version, so size and modification time of the executable are used as well.
+func compilerID() string {
+	id := pkg.Version
+
+	exe, err := os.Executable()
+	if err != nil {
+		return id
+	}
+
+	info, err := os.Stat(exe)
+	if err != nil {
+		return id
+	}
+
+	return id + "-" + strconv.FormatInt(info.Size(), 10) + "-" + strconv.FormatInt(info.ModTime().UnixNano(), 10)
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	c := New(t.TempDir())
+
+	key := c.Key("parsed", "main")
+	require.NotEqual(t, key, c.Key("parsed", "main", ""))
+	require.NotEqual(t, c.Key("ab", "c"), c.Key("a", "bc"))
+
+	var v map[string]int
+	require.False(t, c.Get(key, &v))
+
+	require.NoError(t, c.Put(key, map[string]int{"a": 1}))
+	require.True(t, c.Get(key, &v))
+	require.Equal(t, map[string]int{"a": 1}, v)
+
+	// corrupted entries are treated as missing
+	require.NoError(t, os.WriteFile(c.path(key), []byte("{"), 0644))
+	require.False(t, c.Get(key, &v))
+
+	entries, err := os.ReadDir(filepath.Dir(c.path(key)))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary files must be removed")
+}
+
+func TestTrim(t *testing.T) {
+	c := New(t.TempDir())
+
+	used, unused := c.Key("used"), c.Key("unused")
+	require.NoError(t, c.Put(used, 1))
+	require.NoError(t, c.Put(unused, 2))
+
+	old := time.Now().Add(-maxUnusedAge - time.Hour)
+	for _, key := range []string{used, unused} {
+		require.NoError(t, os.Chtimes(c.path(key), old, old))
+	}
+
+	var v int
+	require.True(t, c.Get(used, &v), "getting entry marks it as used")
+
+	require.NoError(t, c.Trim(time.Now()))
+	require.True(t, c.Get(used, &v))
+	require.False(t, c.Get(unused, &v))
+
+	// cache is not trimmed again until trim interval passes
+	require.NoError(t, c.Put(unused, 2))
+	require.NoError(t, os.Chtimes(c.path(unused), old, old))
+	require.NoError(t, c.Trim(time.Now()))
+	require.True(t, c.Get(unused, &v))
+
+	require.NoError(t, os.Chtimes(c.path(unused), old, old))
+	require.NoError(t, c.Trim(time.Now().Add(trimInterval)))
+	require.False(t, c.Get(unused, &v))
+}
+
+func TestDir(t *testing.T) {
+	t.Setenv("NEVACACHE", "off")
+	_, ok := Dir()
+	require.False(t, ok)
+
+	t.Setenv("NEVACACHE", "/tmp/neva-cache")
+	dir, ok := Dir()
+	require.True(t, ok)
+	require.Equal(t, "/tmp/neva-cache", dir)
+}
@@ -2,6 +2,13 @@ package compiler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/nevalang/neva/internal/compiler/ir"
@@ -19,6 +26,72 @@ type CompilerInput struct {
 	Main   string
 	Output string
 	Trace  bool
+	// Library is optional. If set, the component is exported as a library
+	// and Main is treated as a path to the package that contains it.
+	Library *LibraryInput
+	// Emit is a list of stages to write to Output as JSON, in addition to the build itself.
+	// Stages that succeeded are written even if compilation fails.
+	Emit []Stage
+}
+
+// Stage is an intermediate result of compilation.
+type Stage string
+
+const (
+	StageParsed    Stage = "parsed"    // build right after parsing
+	StageAnalyzed  Stage = "analyzed"  // build after semantic analysis
+	StageDesugared Stage = "desugared" // build after desugaring
+	StageIR        Stage = "ir"        // program that is passed to the backend
+)
+
+// Stages lists all stages in the order they're produced.
+var Stages = []Stage{StageParsed, StageAnalyzed, StageDesugared, StageIR}
+
+type LibraryInput struct {
+	Component  string
+	ModulePath string
+}
+
+func (c Compiler) compileLibrary(feResult FrontendResult, input CompilerInput) error {
+	libBackend, ok := c.be.(LibraryBackend)
+	if !ok {
+		return errors.New("target does not support library mode")
+	}
+
+	meResult, err := c.me.ProcessLibrary(feResult, input.Library.Component)
+	if emitErr := emitStages(input, meResult); emitErr != nil {
+		return emitErr
+	}
+	if err != nil {
+		return err
+	}
+
+	lib := Library{
+		ModulePath: input.Library.ModulePath,
+		Component:  input.Library.Component,
+	}
+
+	entity, _, entityErr := sourcecode.
+		NewScope(meResult.AnalyzedBuild, core.Location{
+			ModRef:  meResult.AnalyzedBuild.EntryModRef,
+			Package: feResult.MainPkg,
+		}).
+		Entity(core.EntityRef{Name: input.Library.Component})
+	if entityErr != nil {
+		return entityErr
+	}
+
+	for name, port := range entity.Component.Interface.IO.In {
+		lib.In = append(lib.In, LibraryPort{Name: name, TypeExpr: port.TypeExpr})
+	}
+	for name, port := range entity.Component.Interface.IO.Out {
+		lib.Out = append(lib.Out, LibraryPort{Name: name, TypeExpr: port.TypeExpr})
+	}
+
+	sort.Slice(lib.In, func(i, j int) bool { return lib.In[i].Name < lib.In[j].Name })
+	sort.Slice(lib.Out, func(i, j int) bool { return lib.Out[i].Name < lib.Out[j].Name })
+
+	return libBackend.EmitLibrary(input.Output, meResult.IR, lib, input.Trace)
 }
 
 func (c Compiler) Compile(ctx context.Context, input CompilerInput) error {
@@ -27,7 +100,18 @@ func (c Compiler) Compile(ctx context.Context, input CompilerInput) error {
 		return err
 	}
 
+	if emitErr := emitStage(input, StageParsed, feResult.ParsedBuild); emitErr != nil {
+		return emitErr
+	}
+
+	if input.Library != nil {
+		return c.compileLibrary(feResult, input)
+	}
+
 	meResult, err := c.me.Process(feResult)
+	if emitErr := emitStages(input, meResult); emitErr != nil {
+		return emitErr
+	}
 	if err != nil {
 		return err
 	}
@@ -35,9 +119,151 @@ func (c Compiler) Compile(ctx context.Context, input CompilerInput) error {
 	return c.be.Emit(input.Output, meResult.IR, input.Trace)
 }
 
+// emitStages writes results of middleend stages that are requested and available.
+func emitStages(input CompilerInput, meResult MiddleendResult) error {
+	if meResult.AnalyzedBuild.Modules != nil {
+		if err := emitStage(input, StageAnalyzed, meResult.AnalyzedBuild); err != nil {
+			return err
+		}
+	}
+	if meResult.DesugaredBuild.Modules != nil {
+		if err := emitStage(input, StageDesugared, meResult.DesugaredBuild); err != nil {
+			return err
+		}
+	}
+	if meResult.IR != nil {
+		return emitStage(input, StageIR, meResult.IR)
+	}
+	return nil
+}
+
+// emitStage writes result of the stage to <output>/<stage>.json if it's requested.
+func emitStage(input CompilerInput, stage Stage, result any) error {
+	if !slices.Contains(input.Emit, stage) {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("emit %s: %w", stage, err)
+	}
+
+	if err := os.MkdirAll(input.Output, 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(input.Output, string(stage)+".json"), data, 0644)
+}
+
+// CompileToIR is like Compile but returns generated program instead of passing it to the backend.
+// It's used to execute programs in-process.
+func (c Compiler) CompileToIR(ctx context.Context, pkgPath string) (*ir.Program, error) {
+	feResult, err := c.fe.Process(ctx, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	meResult, err := c.me.Process(feResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return meResult.IR, nil
+}
+
+// EmitIR passes program generated by CompileToIR or CompileTests to the backend.
+// It's used to execute programs that can't be executed in-process, e.g. the ones with native code.
+func (c Compiler) EmitIR(output string, prog *ir.Program, trace bool) error {
+	return c.be.Emit(output, prog, trace)
+}
+
+// Test is a test component and the program generated for it.
+type Test struct {
+	Name  string
+	Meta  core.Meta            // Meta of the test component.
+	Nodes map[string]core.Meta // Meta of the nodes executed as runtime functions, by their path in IR.
+	IR    *ir.Program
+}
+
+// CompileTests builds and analyzes package together with its test files
+// and generates program for every test component in it. Tests are sorted by name.
+// Builder of the compiler must be configured to load test files.
+func (c Compiler) CompileTests(ctx context.Context, pkgPath string) ([]Test, error) {
+	feResult, err := c.fe.Process(ctx, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tests, err := c.me.ProcessTests(feResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return tests, nil
+}
+
+// CompileBenchmarks is like CompileTests but generates programs for benchmark components.
+func (c Compiler) CompileBenchmarks(ctx context.Context, pkgPath string) ([]Test, error) {
+	feResult, err := c.fe.Process(ctx, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	benchmarks, err := c.me.ProcessBenchmarks(feResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return benchmarks, nil
+}
+
+// Analyze builds and analyzes program without generating any code.
+// Unlike Compile it doesn't require given package to be executable.
+// Returns analyzed build and name of the given package.
+func (c Compiler) Analyze(ctx context.Context, pkgPath string) (sourcecode.Build, string, error) {
+	feResult, err := c.fe.Process(ctx, pkgPath)
+	if err != nil {
+		return sourcecode.Build{}, "", err
+	}
+
+	analyzedBuild, err := c.me.analyzerFor(feResult).AnalyzeBuild(feResult.ParsedBuild)
+	if err != nil {
+		return sourcecode.Build{}, "", err
+	}
+
+	return analyzedBuild, feResult.MainPkg, nil
+}
+
+// Parse builds and parses program without analyzing it.
+// Returns parsed build and name of the given package.
+func (c Compiler) Parse(ctx context.Context, pkgPath string) (sourcecode.Build, string, error) {
+	feResult, err := c.fe.Process(ctx, pkgPath)
+	if err != nil {
+		return sourcecode.Build{}, "", err
+	}
+	return feResult.ParsedBuild, feResult.MainPkg, nil
+}
+
+// Lint builds and analyzes program and then runs linter over it.
+// Warnings are only returned if program has no errors.
+func (c Compiler) Lint(ctx context.Context, pkgPath string, linter Linter) (*Error, error) {
+	feResult, err := c.fe.Process(ctx, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	analyzedBuild, err := c.me.analyzerFor(feResult).AnalyzeBuild(feResult.ParsedBuild)
+	if err != nil {
+		return nil, err
+	}
+
+	return linter.Lint(feResult.ParsedBuild, analyzedBuild), nil
+}
+
 type Frontend struct {
 	builder Builder
 	parser  Parser
+	cache   Cache // optional
 }
 
 type FrontendResult struct {
@@ -45,6 +271,7 @@ type FrontendResult struct {
 	RawBuild    RawBuild
 	ParsedBuild sourcecode.Build
 	Path        string
+	PackageKeys PackageKeys // nil if caching is disabled
 }
 
 func (f Frontend) Process(ctx context.Context, main string) (FrontendResult, *Error) {
@@ -53,7 +280,7 @@ func (f Frontend) Process(ctx context.Context, main string) (FrontendResult, *Er
 		return FrontendResult{}, err
 	}
 
-	parsedMods, err := f.parser.ParseModules(raw.Modules)
+	parsedMods, keys, err := f.parse(raw)
 	if err != nil {
 		return FrontendResult{}, err
 	}
@@ -71,6 +298,7 @@ func (f Frontend) Process(ctx context.Context, main string) (FrontendResult, *Er
 		RawBuild:    raw,
 		MainPkg:     mainPkg,
 		Path:        moduleRoot,
+		PackageKeys: keys,
 	}, nil
 }
 
@@ -85,8 +313,11 @@ type Middleend struct {
 	desugarer Desugarer
 	analyzer  Analyzer
 	irgen     Irgen
+	cache     Cache // optional
 }
 
+// MiddleendResult contains results of every stage of the middleend.
+// On error it contains results of the stages that succeeded.
 type MiddleendResult struct {
 	AnalyzedBuild  sourcecode.Build
 	DesugaredBuild sourcecode.Build
@@ -94,7 +325,7 @@ type MiddleendResult struct {
 }
 
 func (m Middleend) Process(feResult FrontendResult) (MiddleendResult, *Error) {
-	analyzedBuild, err := m.analyzer.AnalyzeExecutableBuild(
+	analyzedBuild, err := m.analyzerFor(feResult).AnalyzeExecutableBuild(
 		feResult.ParsedBuild,
 		feResult.MainPkg,
 	)
@@ -104,12 +335,47 @@ func (m Middleend) Process(feResult FrontendResult) (MiddleendResult, *Error) {
 
 	desugaredBuild, derr := m.desugarer.Desugar(analyzedBuild)
 	if derr != nil {
-		return MiddleendResult{}, err
+		return MiddleendResult{AnalyzedBuild: analyzedBuild}, &Error{Message: derr.Error()}
 	}
 
 	irProg, irerr := m.irgen.Generate(desugaredBuild, feResult.MainPkg)
 	if irerr != nil {
-		return MiddleendResult{}, &Error{
+		return MiddleendResult{AnalyzedBuild: analyzedBuild, DesugaredBuild: desugaredBuild}, &Error{
+			Message: "internal error: unable to generate IR",
+			Meta: &core.Meta{
+				Location: core.Location{
+					ModRef: desugaredBuild.EntryModRef,
+				},
+			},
+		}
+	}
+
+	return MiddleendResult{
+		AnalyzedBuild:  analyzedBuild,
+		DesugaredBuild: desugaredBuild,
+		IR:             irProg,
+	}, nil
+}
+
+// ProcessLibrary is like Process but generates program for the given component instead of Main.
+func (m Middleend) ProcessLibrary(feResult FrontendResult, componentName string) (MiddleendResult, *Error) {
+	analyzedBuild, err := m.analyzerFor(feResult).AnalyzeLibraryBuild(
+		feResult.ParsedBuild,
+		feResult.MainPkg,
+		componentName,
+	)
+	if err != nil {
+		return MiddleendResult{}, err
+	}
+
+	desugaredBuild, derr := m.desugarer.Desugar(analyzedBuild)
+	if derr != nil {
+		return MiddleendResult{AnalyzedBuild: analyzedBuild}, &Error{Message: derr.Error()}
+	}
+
+	irProg, irerr := m.irgen.GenerateLibrary(desugaredBuild, feResult.MainPkg, componentName)
+	if irerr != nil {
+		return MiddleendResult{AnalyzedBuild: analyzedBuild, DesugaredBuild: desugaredBuild}, &Error{
 			Message: "internal error: unable to generate IR",
 			Meta: &core.Meta{
 				Location: core.Location{
@@ -126,6 +392,93 @@ func (m Middleend) Process(feResult FrontendResult) (MiddleendResult, *Error) {
 	}, nil
 }
 
+// ProcessTests is like Process but generates program for every test component of the package.
+func (m Middleend) ProcessTests(feResult FrontendResult) ([]Test, *Error) {
+	analyzedBuild, err := m.analyzerFor(feResult).AnalyzeTestBuild(feResult.ParsedBuild, feResult.MainPkg)
+	if err != nil {
+		return nil, err
+	}
+	return m.processTestComponents(feResult, analyzedBuild, sourcecode.Package.Tests)
+}
+
+// ProcessBenchmarks is like ProcessTests but for benchmark components.
+func (m Middleend) ProcessBenchmarks(feResult FrontendResult) ([]Test, *Error) {
+	analyzedBuild, err := m.analyzerFor(feResult).AnalyzeBenchmarkBuild(feResult.ParsedBuild, feResult.MainPkg)
+	if err != nil {
+		return nil, err
+	}
+	return m.processTestComponents(feResult, analyzedBuild, sourcecode.Package.Benchmarks)
+}
+
+func (m Middleend) processTestComponents(
+	feResult FrontendResult,
+	analyzedBuild sourcecode.Build,
+	components func(sourcecode.Package) func(func(sourcecode.EntitiesResult) bool),
+) ([]Test, *Error) {
+	desugaredBuild, derr := m.desugarer.Desugar(analyzedBuild)
+	if derr != nil {
+		return nil, &Error{Message: derr.Error()}
+	}
+
+	pkg := desugaredBuild.Modules[desugaredBuild.EntryModRef].Packages[feResult.MainPkg]
+	scope := sourcecode.NewScope(desugaredBuild, core.Location{
+		ModRef:  desugaredBuild.EntryModRef,
+		Package: feResult.MainPkg,
+	})
+
+	tests := []Test{}
+	for result := range components(pkg) {
+		nodes := map[string]core.Meta{}
+		funcNodes(scope, result.Entity.Component, "", nodes)
+		tests = append(tests, Test{
+			Name:  result.EntityName,
+			Meta:  result.Entity.Component.Meta,
+			Nodes: nodes,
+		})
+	}
+	sort.Slice(tests, func(i, j int) bool { return tests[i].Name < tests[j].Name })
+
+	for i := range tests {
+		irProg, irerr := m.irgen.GenerateLibrary(desugaredBuild, feResult.MainPkg, tests[i].Name)
+		if irerr != nil {
+			return nil, &Error{
+				Message: "internal error: unable to generate IR",
+				Meta:    &tests[i].Meta,
+			}
+		}
+		tests[i].IR = irProg
+	}
+
+	return tests, nil
+}
+
+// funcNodes puts meta of the component's nodes that are executed as runtime functions into result,
+// by their paths in IR, e.g. "assert" or "helper/assert".
+func funcNodes(scope sourcecode.Scope, component sourcecode.Component, prefix string, result map[string]core.Meta) {
+	for name, node := range component.Nodes {
+		entity, location, err := scope.Relocate(node.Meta.Location).Entity(node.EntityRef)
+		if err != nil || entity.Kind != sourcecode.ComponentEntity {
+			continue // interface nodes are replaced by dependencies declared somewhere else
+		}
+
+		path := prefix + name
+		if _, ok := entity.Component.Directives[ExternDirective]; ok {
+			result[path] = node.Meta
+			continue
+		}
+
+		funcNodes(scope.Relocate(location), entity.Component, path+"/", result)
+	}
+}
+
+// WithCache returns compiler that stores results of parsing and analysis of packages in the cache
+// and reuses them in the next builds as long as packages and their dependencies don't change.
+func (c Compiler) WithCache(cache Cache) Compiler {
+	c.fe.cache = cache
+	c.me.cache = cache
+	return c
+}
+
 func New(
 	builder Builder,
 	parser Parser,
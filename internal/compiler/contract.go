@@ -6,6 +6,7 @@ import (
 	"github.com/nevalang/neva/internal/compiler/ir"
 	src "github.com/nevalang/neva/internal/compiler/sourcecode"
 	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	ts "github.com/nevalang/neva/internal/compiler/sourcecode/typesystem"
 )
 
 const (
@@ -31,12 +32,42 @@ type (
 	RawModule struct {
 		Manifest src.ModuleManifest    // Manifest must be parsed by builder before passing into compiler
 		Packages map[string]RawPackage // Packages themselves on the other hand can be parsed by compiler
+		Native   RawPackage            // Native is optional Go code implementing module's externs, by file name
 	}
 
 	RawPackage map[string][]byte
 
 	Analyzer interface {
 		AnalyzeExecutableBuild(mod src.Build, mainPkgName string) (src.Build, *Error)
+		AnalyzeLibraryBuild(mod src.Build, pkgName string, componentName string) (src.Build, *Error)
+		AnalyzeTestBuild(build src.Build, pkgName string) (src.Build, *Error)
+		AnalyzeBenchmarkBuild(build src.Build, pkgName string) (src.Build, *Error)
+		AnalyzeBuild(build src.Build) (src.Build, *Error)
+	}
+
+	// CachingAnalyzer is implemented by analyzers that can reuse analysis results of unchanged packages.
+	CachingAnalyzer interface {
+		WithCache(cache PackageCache) Analyzer
+	}
+
+	// PackageCache stores analyzed packages of a single build.
+	PackageCache interface {
+		Get(modRef core.ModuleRef, pkgName string) (src.Package, bool)
+		Put(modRef core.ModuleRef, pkgName string, pkg src.Package)
+	}
+
+	// Cache is a content-addressed storage of compilation results shared between builds.
+	// Keys must cover everything the stored values depend on.
+	Cache interface {
+		Key(parts ...string) string
+		Get(key string, v any) bool
+		Put(key string, v any) error
+	}
+
+	// Linter checks program that is free of errors and reports warnings.
+	// Parsed build keeps references as they are written, analyzed build has resolved types.
+	Linter interface {
+		Lint(parsed src.Build, analyzed src.Build) *Error
 	}
 
 	Desugarer interface {
@@ -45,9 +76,29 @@ type (
 
 	Irgen interface {
 		Generate(build src.Build, mainpkg string) (*ir.Program, error)
+		GenerateLibrary(build src.Build, pkgName string, componentName string) (*ir.Program, error)
 	}
 
 	Backend interface {
 		Emit(dst string, prog *ir.Program, trace bool) error
 	}
+
+	// LibraryBackend is implemented by backends that can emit
+	// reusable library instead of executable program.
+	LibraryBackend interface {
+		EmitLibrary(dst string, prog *ir.Program, lib Library, trace bool) error
+	}
+
+	// Library describes component exported as a library.
+	Library struct {
+		ModulePath string
+		Component  string
+		In         []LibraryPort // sorted by name
+		Out        []LibraryPort // sorted by name
+	}
+
+	LibraryPort struct {
+		Name     string
+		TypeExpr ts.Expr
+	}
 )
@@ -56,9 +56,17 @@ func (d *Desugarer) desugarComponent(
 	// merge real nodes with virtual ones created by network handler
 	maps.Copy(desugaredNodes, desugarNetResult.nodesToInsert)
 
+	// nodes that network doesn't refer to would never run, linter warns about them
+	usedNodesComponent := component
+	usedNodesComponent.Nodes = maps.Clone(component.Nodes)
+	for nodeName := range unusedNodes(component.Nodes, desugarNetResult.desugaredConnections) {
+		delete(desugaredNodes, nodeName)
+		delete(usedNodesComponent.Nodes, nodeName)
+	}
+
 	// create and connect Del nodes to handle unused outports
 	unusedOutports := d.findUnusedOutports(
-		component,
+		usedNodesComponent,
 		scope,
 		desugarNetResult.nodesPortsUsed,
 	)
@@ -69,6 +69,37 @@ func (Desugarer) handleUnusedOutports(
 	return result
 }
 
+// unusedNodes returns names of the nodes that desugared connections don't refer to.
+func unusedNodes(nodes map[string]src.Node, conns []src.Connection) map[string]struct{} {
+	used := map[string]struct{}{}
+	for _, conn := range conns {
+		if conn.ArrayBypass != nil {
+			used[conn.ArrayBypass.SenderOutport.Node] = struct{}{}
+			used[conn.ArrayBypass.ReceiverInport.Node] = struct{}{}
+			continue
+		}
+		for _, sender := range conn.Normal.Senders {
+			if sender.PortAddr != nil {
+				used[sender.PortAddr.Node] = struct{}{}
+			}
+		}
+		for _, receiver := range conn.Normal.Receivers {
+			if receiver.PortAddr != nil {
+				used[receiver.PortAddr.Node] = struct{}{}
+			}
+		}
+	}
+
+	unused := map[string]struct{}{}
+	for nodeName := range nodes {
+		if _, ok := used[nodeName]; !ok {
+			unused[nodeName] = struct{}{}
+		}
+	}
+
+	return unused
+}
+
 func (Desugarer) findUnusedOutports(
 	component src.Component,
 	scope src.Scope,
@@ -21,6 +21,11 @@ type Desugarer struct {
 	fanOutCounter         uint64
 	fanInCounter          uint64
 	rangeCounter          uint64
+	// Unary
+	notCounter uint64
+	incCounter uint64
+	decCounter uint64
+	negCounter uint64
 	// Arithmetic
 	addCounter uint64
 	subCounter uint64
@@ -82,6 +87,7 @@ func (d *Desugarer) desugarModule(
 	modsCopy[modRef] = src.Module{
 		Manifest: desugaredManifest,
 		Packages: mod.Packages,
+		Native:   mod.Native,
 	}
 
 	// create new build with patched modules (current module has patched manifest with std dependency)
@@ -110,6 +116,7 @@ func (d *Desugarer) desugarModule(
 	return src.Module{
 		Manifest: desugaredManifest,
 		Packages: desugaredPkgs,
+		Native:   mod.Native,
 	}, nil
 }
 
@@ -826,6 +826,23 @@ func (d *Desugarer) desugarSingleSender(
 		return desugarSenderResult(result), nil
 	}
 
+	if sender.Unary != nil {
+		result, err := d.desugarUnarySender(
+			iface,
+			*sender.Unary,
+			normConn,
+			nodesToInsert,
+			constsToInsert,
+			usedNodeOutports,
+			scope,
+			nodes,
+		)
+		if err != nil {
+			return desugarSenderResult{}, err
+		}
+		return desugarSenderResult(result), nil
+	}
+
 	result, err := d.desugarRangeSender(
 		*sender.Range,
 		normConn,
@@ -1596,3 +1613,128 @@ func (d *Desugarer) desugarBinarySender(
 		insert:  desugaredInsert,
 	}, nil
 }
+
+type handleUnarySenderResult struct {
+	replace src.Connection
+	insert  []src.Connection
+}
+
+func (d *Desugarer) desugarUnarySender(
+	iface src.Interface,
+	unary src.Unary,
+	normConn src.NormalConnection,
+	nodesToInsert map[string]src.Node,
+	constsToInsert map[string]src.Const,
+	usedNodeOutports nodeOutportsUsed,
+	scope Scope,
+	nodes map[string]src.Node,
+) (handleUnarySenderResult, error) {
+	locOnlyMeta := core.Meta{
+		Location: unary.Meta.Location,
+	}
+
+	var (
+		opNode      string
+		opComponent string
+	)
+
+	switch unary.Operator {
+	case src.NotOp:
+		d.notCounter++
+		opNode = fmt.Sprintf("__not__%d", d.notCounter)
+		opComponent = "Not"
+	case src.IncOp:
+		d.incCounter++
+		opNode = fmt.Sprintf("__inc__%d", d.incCounter)
+		opComponent = "Inc"
+	case src.DecOp:
+		d.decCounter++
+		opNode = fmt.Sprintf("__dec__%d", d.decCounter)
+		opComponent = "Dec"
+	case src.NegOp:
+		d.negCounter++
+		opNode = fmt.Sprintf("__neg__%d", d.negCounter)
+		opComponent = "Neg"
+	default:
+		return handleUnarySenderResult{}, fmt.Errorf(
+			"unsupported unary operator: %s",
+			unary.Operator,
+		)
+	}
+
+	// Not is not generic, unlike the other unary operators
+	var typeArgs []ts.Expr
+	if unary.Operator != src.NotOp {
+		typeArgs = []ts.Expr{unary.AnalyzedType}
+	}
+
+	nodesToInsert[opNode] = src.Node{
+		EntityRef: core.EntityRef{
+			Pkg:  "builtin",
+			Name: opComponent,
+			Meta: locOnlyMeta,
+		},
+		TypeArgs: typeArgs,
+		Meta:     locOnlyMeta,
+	}
+
+	// operand -> op:data
+	sugaredInsert := src.Connection{
+		Normal: &src.NormalConnection{
+			Senders: []src.ConnectionSender{unary.Operand},
+			Receivers: []src.ConnectionReceiver{
+				{
+					PortAddr: &src.PortAddr{
+						Node: opNode,
+						Port: "data",
+						Meta: locOnlyMeta,
+					},
+					Meta: locOnlyMeta,
+				},
+			},
+			Meta: locOnlyMeta,
+		},
+	}
+
+	// operand-sender might be sugared, so we need to desugar it
+	desugarConnRes, err := d.desugarConnection(
+		iface,
+		sugaredInsert,
+		usedNodeOutports,
+		scope,
+		nodes,
+		nodesToInsert,
+		constsToInsert,
+	)
+	if err != nil {
+		return handleUnarySenderResult{}, err
+	}
+
+	desugaredInsert := make([]src.Connection, 0, 1+len(desugarConnRes.insert))
+	desugaredInsert = append(desugaredInsert, *desugarConnRes.replace)
+	desugaredInsert = append(desugaredInsert, desugarConnRes.insert...)
+
+	// op:res -> XXX
+	replace := src.Connection{
+		Normal: &src.NormalConnection{
+			Senders: []src.ConnectionSender{
+				{
+					PortAddr: &src.PortAddr{
+						Node: opNode,
+						Port: "res",
+						Meta: locOnlyMeta,
+					},
+					Meta: locOnlyMeta,
+				},
+			},
+			Receivers: normConn.Receivers, // desugaring of original receivers is job of caller
+			Meta:      locOnlyMeta,
+		},
+		Meta: locOnlyMeta,
+	}
+
+	return handleUnarySenderResult{
+		replace: replace,
+		insert:  desugaredInsert,
+	}, nil
+}
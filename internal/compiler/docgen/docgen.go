@@ -0,0 +1,338 @@
+// Package docgen generates documentation of the module from doc comments of its public entities.
+// Every package with public entities gets its own page, references between entities are cross-linked.
+package docgen
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/nevalang/neva/internal/compiler/printer"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	ts "github.com/nevalang/neva/internal/compiler/sourcecode/typesystem"
+)
+
+//go:embed *.tmpl
+var tmplFS embed.FS
+
+// Format of generated documentation.
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+)
+
+// Module is what documentation is generated for.
+type Module struct {
+	Title  string     // shown on every page
+	Name   string     // how module is referred in imports of its own files, e.g. "@" or "std"
+	Module src.Module // parsed module
+}
+
+// Generate returns content of documentation files by their paths relative to output directory.
+// Package "foo/bar" is documented in "foo/bar.<format>" and there is "index.<format>" with all packages.
+func Generate(mod Module, format Format) (map[string][]byte, error) {
+	tmpl, err := parseTemplates(format)
+	if err != nil {
+		return nil, err
+	}
+
+	g := generator{mod: mod, ext: "." + string(format)}
+
+	idx := index{Title: mod.Title}
+	files := map[string][]byte{}
+
+	for _, pkgName := range sortedKeys(mod.Module.Packages) {
+		page, ok := g.page(pkgName)
+		if !ok {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, "package", page); err != nil {
+			return nil, fmt.Errorf("package %v: %w", pkgName, err)
+		}
+		files[pkgName+g.ext] = buf.Bytes()
+
+		idx.Packages = append(idx.Packages, link{Name: pkgName, Href: pkgName + g.ext})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "index", idx); err != nil {
+		return nil, fmt.Errorf("index: %w", err)
+	}
+	files["index"+g.ext] = buf.Bytes()
+
+	return files, nil
+}
+
+// executor is implemented by both text and html templates.
+type executor interface {
+	ExecuteTemplate(w io.Writer, name string, data any) error
+}
+
+func parseTemplates(format Format) (executor, error) {
+	switch format {
+	case FormatMarkdown:
+		tmpl, err := template.New("").Funcs(template.FuncMap{
+			"cell": func(s string) string { return strings.ReplaceAll(s, "|", `\|`) },
+		}).ParseFS(tmplFS, "*.md.tmpl")
+		return tmpl, err
+	case FormatHTML:
+		tmpl, err := htmltemplate.New("").Funcs(htmltemplate.FuncMap{
+			"paragraphs": func(s string) []string {
+				if s == "" {
+					return nil
+				}
+				return strings.Split(s, "\n\n")
+			},
+		}).ParseFS(tmplFS, "*.html.tmpl")
+		return tmpl, err
+	}
+	return nil, fmt.Errorf("unknown format %v", format)
+}
+
+type index struct {
+	Title    string
+	Packages []link
+}
+
+type page struct {
+	Title      string
+	Package    string
+	Index      string // link to the index page
+	Types      []entity
+	Consts     []entity
+	Interfaces []entity
+	Components []entity
+}
+
+type entity struct {
+	Name      string
+	Signature string
+	Doc       string
+	In, Out   []port
+	Refs      []link // entities used in the signature
+}
+
+type port struct {
+	Name string
+	Type string
+}
+
+type link struct {
+	Name string
+	Href string
+}
+
+type generator struct {
+	mod Module
+	ext string
+}
+
+// page returns documentation of the package, it's false if package has no public entities.
+func (g generator) page(pkgName string) (page, bool) {
+	result := page{
+		Title:   g.mod.Title,
+		Package: pkgName,
+		Index:   relative(pkgName, "index"+g.ext),
+	}
+
+	pkg := g.mod.Module.Packages[pkgName]
+	found := false
+
+	for _, fileName := range sortedKeys(pkg) {
+		file := pkg[fileName]
+		for _, name := range sortedKeys(file.Entities) {
+			e := file.Entities[name]
+			if !e.IsPublic {
+				continue
+			}
+			found = true
+
+			doc := g.entity(pkgName, file, name, e)
+			switch e.Kind {
+			case src.TypeEntity:
+				result.Types = append(result.Types, doc)
+			case src.ConstEntity:
+				result.Consts = append(result.Consts, doc)
+			case src.InterfaceEntity:
+				result.Interfaces = append(result.Interfaces, doc)
+			case src.ComponentEntity:
+				result.Components = append(result.Components, doc)
+			}
+		}
+	}
+
+	for _, entities := range [][]entity{result.Types, result.Consts, result.Interfaces, result.Components} {
+		sort.Slice(entities, func(i, j int) bool { return entities[i].Name < entities[j].Name })
+	}
+
+	return result, found
+}
+
+func (g generator) entity(pkgName string, file src.File, name string, e src.Entity) entity {
+	result := entity{
+		Name: name,
+		Doc:  e.Doc,
+	}
+
+	// signature is the entity without implementation details
+	signature := src.Entity{IsPublic: true, Kind: e.Kind}
+	var (
+		exprs  []ts.Expr
+		params []ts.Param
+	)
+	switch e.Kind {
+	case src.TypeEntity:
+		signature.Type = e.Type
+		params = e.Type.Params
+		if e.Type.BodyExpr != nil {
+			exprs = append(exprs, *e.Type.BodyExpr)
+		}
+	case src.ConstEntity:
+		signature.Const = e.Const
+		exprs = append(exprs, e.Const.TypeExpr)
+	case src.InterfaceEntity:
+		signature.Interface = e.Interface
+		params = e.Interface.TypeParams.Params
+		result.In, result.Out = ports(e.Interface.IO.In, &exprs), ports(e.Interface.IO.Out, &exprs)
+	case src.ComponentEntity:
+		signature.Component = src.Component{Interface: e.Component.Interface}
+		params = e.Component.Interface.TypeParams.Params
+		result.In, result.Out = ports(e.Component.Interface.IO.In, &exprs), ports(e.Component.Interface.IO.Out, &exprs)
+	}
+	result.Signature = strings.TrimSuffix(
+		string(printer.Print(src.File{Entities: map[string]src.Entity{name: signature}})),
+		"\n",
+	)
+
+	isParam := map[string]bool{}
+	for _, param := range params {
+		isParam[param.Name] = true
+		exprs = append(exprs, param.Constr)
+	}
+
+	var refs []core.EntityRef
+	for _, expr := range exprs {
+		refs = append(refs, references(expr)...)
+	}
+	if e.Kind == src.ConstEntity && e.Const.Value.Ref != nil {
+		refs = append(refs, *e.Const.Value.Ref)
+	}
+
+	seen := map[string]bool{} // refs are compared without meta
+	for _, ref := range refs {
+		if seen[ref.String()] || (ref.Pkg == "" && isParam[ref.Name]) {
+			continue
+		}
+		seen[ref.String()] = true
+		if href, ok := g.href(pkgName, file, ref); ok {
+			result.Refs = append(result.Refs, link{Name: ref.String(), Href: href})
+		}
+	}
+
+	return result
+}
+
+// href returns link to documentation of referenced entity if it's documented.
+// References to other modules are not linked, except builtins when stdlib is documented.
+func (g generator) href(pkgName string, file src.File, ref core.EntityRef) (string, bool) {
+	target := pkgName
+	if ref.Pkg != "" {
+		imp, ok := file.Imports[ref.Pkg]
+		if !ok || imp.Module != g.mod.Name {
+			return "", false
+		}
+		target = imp.Package
+	} else if !g.isPublic(pkgName, ref.Name) {
+		target = "builtin" // builtin entities are referred without package
+		if g.mod.Name != "std" {
+			return "", false
+		}
+	}
+
+	if !g.isPublic(target, ref.Name) {
+		return "", false
+	}
+
+	if target == pkgName {
+		return "#" + ref.Name, true
+	}
+
+	return relative(pkgName, target+g.ext) + "#" + ref.Name, true
+}
+
+func (g generator) isPublic(pkgName, entityName string) bool {
+	e, _, ok := g.mod.Module.Packages[pkgName].Entity(entityName)
+	return ok && e.IsPublic
+}
+
+// ports returns ports in the order they are declared and adds their types to exprs.
+func ports(ports map[string]src.Port, exprs *[]ts.Expr) []port {
+	names := sortedKeys(ports)
+	sort.SliceStable(names, func(i, j int) bool {
+		a, b := ports[names[i]].Meta.Start, ports[names[j]].Meta.Start
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	result := make([]port, 0, len(ports))
+	for _, name := range names {
+		p := ports[name]
+		if p.IsArray {
+			name = "[" + name + "]"
+		}
+		result = append(result, port{
+			Name: name,
+			Type: strings.Join(strings.Fields(printer.TypeExpr(p.TypeExpr)), " "),
+		})
+		*exprs = append(*exprs, p.TypeExpr)
+	}
+
+	return result
+}
+
+// references returns entity references of the type expression in the order they appear.
+func references(expr ts.Expr) []core.EntityRef {
+	var result []core.EntityRef
+	switch {
+	case expr.Inst != nil:
+		result = append(result, expr.Inst.Ref)
+		for _, arg := range expr.Inst.Args {
+			result = append(result, references(arg)...)
+		}
+	case expr.Lit != nil:
+		for _, el := range expr.Lit.Union {
+			result = append(result, references(el)...)
+		}
+		for _, name := range sortedKeys(expr.Lit.Struct) {
+			result = append(result, references(expr.Lit.Struct[name])...)
+		}
+	}
+	return result
+}
+
+// relative returns path to the file relative to the page of the package.
+func relative(pkgName, file string) string {
+	return strings.Repeat("../", strings.Count(path.Clean(pkgName), "/")) + file
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,134 @@
+package docgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/internal/compiler/parser"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+func TestGenerate(t *testing.T) {
+	mod := newModule(t, map[string]string{
+		"foo/bar": `
+// Point is a point on a plane.
+pub type Point struct {
+    x int
+    y int
+}
+
+type private int
+`,
+		"baz": `
+import { @:foo/bar }
+
+// Max is the largest coordinate.
+pub const Max int = 100
+
+// Move moves the point.
+//
+// Second paragraph.
+pub def Move<T>(point bar.Point, [by] T) (res bar.Point | int) {
+    :point -> :res
+}
+`,
+		"main": `
+def Main(start any) (stop any) {
+    :start -> :stop
+}
+`,
+	})
+
+	files, err := Generate(Module{Title: "example", Name: "@", Module: mod}, FormatMarkdown)
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"index.md", "baz.md", "foo/bar.md"}, keys(files))
+
+	require.Equal(t, `# example
+
+| Package |
+| --- |
+| [baz](baz.md) |
+| [foo/bar](foo/bar.md) |
+`, string(files["index.md"]))
+
+	require.Equal(t, `# baz
+
+[example](index.md) / baz
+
+## Constants
+
+<a id="Max"></a>
+
+### Max
+
+`+"```neva"+`
+pub const Max int = 100
+`+"```"+`
+
+Max is the largest coordinate.
+
+## Components
+
+<a id="Move"></a>
+
+### Move
+
+`+"```neva"+`
+pub def Move<T any>(point bar.Point, [by] T) (res bar.Point | int)
+`+"```"+`
+
+Move moves the point.
+
+Second paragraph.
+
+| Inport | Type |
+| --- | --- |
+| `+"`point`"+` | `+"`bar.Point`"+` |
+| `+"`[by]`"+` | `+"`T`"+` |
+
+| Outport | Type |
+| --- | --- |
+| `+"`res`"+` | `+"`bar.Point \\| int`"+` |
+
+See also: [`+"`bar.Point`"+`](foo/bar.md#Point)
+`, string(files["baz.md"]))
+
+	bar := string(files["foo/bar.md"])
+	require.Contains(t, bar, "[example](../index.md) / foo/bar")
+	require.Contains(t, bar, "Point is a point on a plane.")
+	require.NotContains(t, bar, "private")
+
+	files, err = Generate(Module{Title: "example", Name: "@", Module: mod}, FormatHTML)
+	require.NoError(t, err)
+
+	html := string(files["baz.html"])
+	require.Contains(t, html, `<a href="foo/bar.html#Point"><code>bar.Point</code></a>`)
+	require.Contains(t, html, "<p>Move moves the point.</p>\n<p>Second paragraph.</p>")
+	require.Contains(t, html, "pub def Move&lt;T any&gt;")
+}
+
+func newModule(t *testing.T, sources map[string]string) src.Module {
+	t.Helper()
+
+	mod := src.Module{Packages: map[string]src.Package{}}
+	for pkgName, source := range sources {
+		files, err := parser.New().ParseFiles(core.ModuleRef{Path: "@"}, pkgName, map[string][]byte{
+			"file": []byte(source),
+		})
+		require.Nil(t, err)
+		mod.Packages[pkgName] = files
+	}
+
+	return mod
+}
+
+func keys[T any](m map[string]T) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
@@ -2,15 +2,62 @@ package compiler
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
 )
 
 type Error struct {
+	Message  string
+	Meta     *core.Meta
+	Severity Severity
+	Notes    []Note // related locations, e.g. where referenced entity is declared
+
+	child  *Error
+	joined []*Error // independent errors, set only by Join
+}
+
+// Severity tells whether the problem prevents compilation.
+type Severity uint8
+
+const (
+	SeverityError   Severity = iota // zero value, every error is fatal unless stated otherwise
+	SeverityWarning                 // problem is reported but program is still compiled
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Note is additional information about the error that points to another place in the source code.
+type Note struct {
 	Message string
 	Meta    *core.Meta
+}
+
+// Join returns error that contains all given non-nil errors or nil if there are none.
+// It's used to report independent problems at once instead of stopping at the first one.
+func Join(errs ...*Error) *Error {
+	var nonNil []*Error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
 
-	child *Error
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	}
+
+	return &Error{joined: nonNil}
 }
 
 func (e Error) Wrap(child *Error) *Error {
@@ -18,24 +65,76 @@ func (e Error) Wrap(child *Error) *Error {
 	return &e
 }
 
+// Unwrap returns deepest child of the error.
+// If error contains several independent errors, the first of them is used.
 func (e Error) Unwrap() *Error {
-	for e.child != nil {
+	return e.Errors()[0]
+}
+
+// Errors returns deepest children of all independent errors, ordered by location.
+// Notes of parent errors are passed down to their children.
+func (e Error) Errors() []*Error {
+	var notes []Note
+	for e.child != nil && len(e.joined) == 0 {
+		notes = append(notes, e.Notes...)
 		e = *e.child
 	}
-	return &e
+
+	if len(e.joined) == 0 {
+		e.Notes = slices.Concat(e.Notes, notes)
+		return []*Error{&e}
+	}
+
+	notes = append(notes, e.Notes...)
+
+	var result []*Error
+	for _, joined := range e.joined {
+		for _, child := range joined.Errors() {
+			child.Notes = slices.Concat(child.Notes, notes)
+			result = append(result, child)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].less(result[j])
+	})
+
+	return result
+}
+
+func (e *Error) less(other *Error) bool {
+	if e.Meta == nil || other.Meta == nil {
+		return e.Meta == nil && other.Meta != nil
+	}
+
+	if a, b := e.Meta.Location.String(), other.Meta.Location.String(); a != b {
+		return a < b
+	}
+
+	if e.Meta.Start.Line != other.Meta.Start.Line {
+		return e.Meta.Start.Line < other.Meta.Start.Line
+	}
+
+	return e.Meta.Start.Column < other.Meta.Start.Column
 }
 
+// Error returns description of every independent error, one per line.
 func (e *Error) Error() string {
-	var s string
+	errs := e.Errors()
 
-	current := e.Unwrap()
-	hasMeta := current.Meta != nil
+	lines := make([]string, 0, len(errs))
+	for _, current := range errs {
+		msg := current.Message
+		if current.Severity == SeverityWarning {
+			msg = "warning: " + msg
+		}
 
-	if hasMeta {
-		s = fmt.Sprintf("%v:%v: %v", current.Meta.Location, current.Meta.Start, current.Message)
-	} else {
-		s = current.Message
+		if current.Meta != nil {
+			lines = append(lines, fmt.Sprintf("%v:%v: %v", current.Meta.Location, current.Meta.Start, msg))
+		} else {
+			lines = append(lines, msg)
+		}
 	}
 
-	return s
+	return strings.Join(lines, "\n")
 }
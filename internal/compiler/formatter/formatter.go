@@ -0,0 +1,417 @@
+// Package formatter implements canonical formatting of Neva source code.
+// Source is parsed with the same ANTLR parser the compiler uses,
+// so only syntactically valid files can be formatted.
+// Formatter keeps line breaks and comments written by the user
+// but normalizes indentation, spacing, blank lines and order of imports.
+package formatter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/parser"
+	generated "github.com/nevalang/neva/internal/compiler/parser/generated"
+)
+
+// Indent is used for every level of nesting.
+const Indent = "    "
+
+// token is a terminal of the parse tree together with the rule it belongs to
+// and the rule that rule belongs to.
+type token struct {
+	text  string
+	ctx   antlr.Tree
+	outer antlr.Tree
+}
+
+// Format returns source code in canonical form.
+// Formatting is idempotent: formatted source doesn't change when formatted again.
+func Format(source []byte) ([]byte, *compiler.Error) {
+	input := antlr.NewInputStream(string(source))
+	lexer := generated.NewnevaLexer(input)
+	lexerErrors := &parser.CustomErrorListener{}
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(lexerErrors)
+	tokenStream := antlr.NewCommonTokenStream(lexer, 0)
+
+	parserErrors := &parser.CustomErrorListener{}
+	prsr := generated.NewnevaParser(tokenStream)
+	prsr.RemoveErrorListeners()
+	prsr.AddErrorListener(parserErrors)
+	prsr.BuildParseTrees = true
+
+	tree := prsr.Prog()
+
+	if len(lexerErrors.Errors) > 0 {
+		return nil, lexerErrors.Errors[0]
+	}
+
+	if len(parserErrors.Errors) > 0 {
+		return nil, parserErrors.Errors[0]
+	}
+
+	var tokens []token
+	collect(tree, nil, nil, &tokens)
+
+	return render(tokens), nil
+}
+
+// collect appends terminals of the tree to tokens in source order.
+// Import statements are replaced with their canonical form.
+// Ancestors are passed explicitly because runtime returns base context instead of the generated one.
+func collect(tree, parent, outer antlr.Tree, tokens *[]token) {
+	switch node := tree.(type) {
+	case *generated.ImportStmtContext:
+		*tokens = append(*tokens, importTokens(node)...)
+	case antlr.TerminalNode:
+		if node.GetSymbol().GetTokenType() == antlr.TokenEOF {
+			return
+		}
+		*tokens = append(*tokens, token{
+			text:  node.GetText(),
+			ctx:   parent,
+			outer: outer,
+		})
+	default:
+		for _, child := range tree.GetChildren() {
+			collect(child, tree, parent, tokens)
+		}
+	}
+}
+
+// importTokens returns tokens of the import statement with sorted imports.
+// Standard library packages go first, then packages of other modules.
+// Multiline statement stays multiline with one import per line.
+func importTokens(stmt *generated.ImportStmtContext) []token {
+	type imp struct {
+		alias, path string
+	}
+
+	defs := stmt.AllImportDef()
+	imports := make([]imp, 0, len(defs))
+	for _, def := range defs {
+		var alias string
+		if def.ImportAlias() != nil {
+			alias = def.ImportAlias().GetText()
+		}
+		imports = append(imports, imp{alias: alias, path: def.ImportPath().GetText()})
+	}
+
+	sort.SliceStable(imports, func(i, j int) bool {
+		iStd := !strings.Contains(imports[i].path, ":")
+		jStd := !strings.Contains(imports[j].path, ":")
+		if iStd != jStd {
+			return iStd
+		}
+		if imports[i].path != imports[j].path {
+			return imports[i].path < imports[j].path
+		}
+		return imports[i].alias < imports[j].alias
+	})
+
+	multiline := strings.Contains(stmt.GetText(), "\n")
+
+	result := []token{{text: "import", ctx: stmt}, {text: "{", ctx: stmt}}
+	if multiline && len(imports) > 0 {
+		result = append(result, token{text: "\n", ctx: stmt})
+	}
+
+	for i, imp := range imports {
+		if imp.alias != "" {
+			result = append(result, token{text: imp.alias, ctx: stmt})
+		}
+		result = append(result, token{text: imp.path, ctx: stmt})
+		switch {
+		case multiline:
+			result = append(result, token{text: "\n", ctx: stmt})
+		case i < len(imports)-1:
+			result = append(result, token{text: ",", ctx: stmt})
+		}
+	}
+
+	return append(result, token{text: "}", ctx: stmt})
+}
+
+// line is a single line of formatted output.
+type line struct {
+	indent int
+	tokens []token
+	opens  bool // line leaves unclosed brackets and increases indentation
+	closes bool // line closes construct that started at top level
+}
+
+func (l line) blank() bool { return len(l.tokens) == 0 }
+
+func (l line) first() token { return l.tokens[0] }
+
+func (l line) last() token { return l.tokens[len(l.tokens)-1] }
+
+func (l line) String() string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat(Indent, l.indent))
+	for i, tok := range l.tokens {
+		if i > 0 && spaceBetween(l.tokens[i-1], tok) {
+			b.WriteByte(' ')
+		}
+		if isComment(tok) {
+			b.WriteString(strings.TrimRight(tok.text, " \t\r"))
+		} else {
+			b.WriteString(tok.text)
+		}
+	}
+	return b.String()
+}
+
+// bracket is an unclosed opening bracket.
+type bracket struct {
+	indents bool // whether bracket increased indentation of the following lines
+}
+
+func render(tokens []token) []byte {
+	lines := splitLines(tokens)
+	indent(lines)
+	lines = normalizeBlankLines(lines)
+
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(l.String())
+		b.WriteByte('\n')
+	}
+
+	return []byte(b.String())
+}
+
+func splitLines(tokens []token) []line {
+	lines := []line{{}}
+	for _, tok := range tokens {
+		if isNewline(tok) {
+			lines = append(lines, line{})
+			continue
+		}
+		lines[len(lines)-1].tokens = append(lines[len(lines)-1].tokens, tok)
+	}
+	return lines
+}
+
+// indent sets indentation of every line based on nesting of brackets.
+// Line that leaves any brackets unclosed increases indentation by one level,
+// line that starts with closing bracket is placed on the level of the line that opened it.
+func indent(lines []line) {
+	var stack []bracket
+
+	depth := func() int {
+		n := 0
+		for _, b := range stack {
+			if b.indents {
+				n++
+			}
+		}
+		return n
+	}
+
+	for i := range lines {
+		l := &lines[i]
+		startDepth := depth()
+
+		j := 0
+		for ; j < len(l.tokens) && isCloser(l.tokens[j]); j++ {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+
+		l.indent = depth()
+		if j == 0 && !l.blank() && isUnionContinuation(l.first()) ||
+			i > 0 && !lines[i-1].blank() && isUnionContinuation(lines[i-1].last()) {
+			l.indent++
+		}
+
+		openedAt := len(stack)
+		for ; j < len(l.tokens); j++ {
+			switch {
+			case isOpener(l.tokens[j]):
+				stack = append(stack, bracket{})
+			case isCloser(l.tokens[j]):
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+				if len(stack) < openedAt {
+					openedAt = len(stack)
+				}
+			}
+		}
+
+		if len(stack) > openedAt {
+			stack[len(stack)-1].indents = true
+			l.opens = true
+		}
+
+		l.closes = startDepth > 0 && depth() == 0
+	}
+}
+
+// normalizeBlankLines removes leading, trailing and repeated blank lines,
+// blank lines right inside brackets and around "---",
+// and separates multiline top level constructs from what follows them.
+func normalizeBlankLines(lines []line) []line {
+	result := make([]line, 0, len(lines))
+
+	for i, l := range lines {
+		if !l.blank() {
+			result = append(result, l)
+			if l.closes && i+1 < len(lines) && !lines[i+1].blank() {
+				result = append(result, line{})
+			}
+			continue
+		}
+
+		if len(result) == 0 || result[len(result)-1].blank() {
+			continue
+		}
+
+		prev := result[len(result)-1]
+		if prev.opens || isNodesDelimiter(prev.first()) {
+			continue
+		}
+
+		next := i + 1
+		for next < len(lines) && lines[next].blank() {
+			next++
+		}
+		if next == len(lines) {
+			break
+		}
+		if isCloser(lines[next].first()) || isNodesDelimiter(lines[next].first()) {
+			continue
+		}
+
+		result = append(result, l)
+	}
+
+	return result
+}
+
+// spaceBetween reports whether two adjacent tokens on the same line must be separated by space.
+func spaceBetween(prev, cur token) bool {
+	switch {
+	case isComment(cur):
+		return true
+	case is[*generated.UnaryOpContext](prev.ctx):
+		return false
+	case prev.text == "-" && isLiteral(prev.ctx):
+		return false
+	case prev.text == "$" && is[*generated.SenderConstRefContext](prev.ctx),
+		prev.text == "#" && is[*generated.CompilerDirectiveContext](prev.ctx):
+		return false
+	case cur.text == "," || cur.text == ")" || cur.text == "]":
+		return false
+	case prev.text == "(" || prev.text == "[":
+		return false
+	case prev.text == "{" && cur.text == "}":
+		return false
+	case is[*generated.NodeDIArgsContext](prev.ctx) && prev.text == "{",
+		is[*generated.NodeDIArgsContext](cur.ctx) && (cur.text == "{" || cur.text == "}"):
+		return false
+	case isTypeBracket(cur):
+		return false
+	case isTypeBracket(prev) && prev.text == "<":
+		return false
+	case cur.text == "(":
+		if is[*generated.CompilerDirectivesArgsContext](cur.ctx) {
+			return false
+		}
+		if is[*generated.PortsDefContext](cur.ctx) {
+			return !is[*generated.InPortsDefContext](cur.outer)
+		}
+		return true
+	case cur.text == "[" && is[*generated.PortAddrIdxContext](cur.ctx):
+		return false
+	case cur.text == "?" && is[*generated.ErrGuardContext](cur.ctx):
+		return false
+	case cur.text == ":" && isPortAddr(cur.ctx):
+		return !is[*generated.PortAddrNodeContext](prev.ctx)
+	case prev.text == ":" && isPortAddr(prev.ctx):
+		return false
+	case cur.text == ":" && is[*generated.StructValueFieldContext](cur.ctx):
+		return false
+	case cur.text == "." && is[*generated.StructSelectorsContext](cur.ctx):
+		return !is[*generated.StructSelectorsContext](prev.ctx)
+	case prev.text == "." && is[*generated.StructSelectorsContext](prev.ctx):
+		return false
+	case isTight(cur) || isTight(prev):
+		return false
+	}
+	return true
+}
+
+// isTight reports whether token is an infix operator that is never surrounded by spaces.
+func isTight(tok token) bool {
+	switch tok.text {
+	case ".":
+		return is[*generated.ImportedEntityRefContext](tok.ctx)
+	case "::":
+		return is[*generated.EnumLitContext](tok.ctx)
+	case "..":
+		return is[*generated.RangeExprContext](tok.ctx)
+	}
+	return false
+}
+
+func isLiteral(ctx antlr.Tree) bool {
+	return is[*generated.ConstLitContext](ctx) ||
+		is[*generated.PrimitiveConstLitContext](ctx) ||
+		is[*generated.RangeMemberContext](ctx)
+}
+
+func isPortAddr(ctx antlr.Tree) bool {
+	return is[*generated.SinglePortAddrContext](ctx) || is[*generated.ArrPortAddrContext](ctx)
+}
+
+func isTypeBracket(tok token) bool {
+	return (tok.text == "<" || tok.text == ">") &&
+		(is[*generated.TypeArgsContext](tok.ctx) || is[*generated.TypeParamsContext](tok.ctx))
+}
+
+func isOpener(tok token) bool {
+	switch tok.text {
+	case "{", "(", "[":
+		return true
+	case "<":
+		return isTypeBracket(tok)
+	}
+	return false
+}
+
+func isCloser(tok token) bool {
+	switch tok.text {
+	case "}", ")", "]":
+		return true
+	case ">":
+		return isTypeBracket(tok)
+	}
+	return false
+}
+
+func isUnionContinuation(tok token) bool {
+	return tok.text == "|" && is[*generated.UnionTypeExprContext](tok.ctx)
+}
+
+func isNodesDelimiter(tok token) bool {
+	return tok.text == "---"
+}
+
+func isComment(tok token) bool {
+	return strings.HasPrefix(tok.text, "//")
+}
+
+func isNewline(tok token) bool {
+	return tok.text == "\n" || tok.text == "\r\n"
+}
+
+func is[T any](tree antlr.Tree) bool {
+	_, ok := tree.(T)
+	return ok
+}
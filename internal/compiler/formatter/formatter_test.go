@@ -0,0 +1,250 @@
+package formatter
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/stretchr/testify/require"
+
+	generated "github.com/nevalang/neva/internal/compiler/parser/generated"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name: "indentation_and_spacing",
+			source: `def Main(start any)(stop any){
+	print   For<int>{ Next2Lines }
+  wait Wait
+---
+	:start->99..-1->print->wait -> :stop
+}
+`,
+			expected: `def Main(start any) (stop any) {
+    print For<int>{Next2Lines}
+    wait Wait
+    ---
+    :start -> 99..-1 -> print -> wait -> :stop
+}
+`,
+		},
+		{
+			name: "imports_are_sorted",
+			source: `import { strings, @:utils, fmt }
+`,
+			expected: `import { fmt, strings, @:utils }
+`,
+		},
+		{
+			name: "multiline_imports",
+			source: `import {
+	time
+  alias github.com/nevalang/x:pkg
+	fmt,
+}
+`,
+			expected: `import {
+    fmt
+    time
+    alias github.com/nevalang/x:pkg
+}
+`,
+		},
+		{
+			name: "blank_lines",
+			source: `
+
+type A int
+
+
+type B int
+def Main(start any) (stop any) {
+
+	Println
+
+	---
+
+	:start -> println -> :stop
+
+}
+def Foo(data int) (res int)
+
+
+`,
+			expected: `type A int
+
+type B int
+def Main(start any) (stop any) {
+    Println
+    ---
+    :start -> println -> :stop
+}
+
+def Foo(data int) (res int)
+`,
+		},
+		{
+			name: "comments_are_preserved",
+			source: `// Main is an entry point.
+def Main(start any) (stop any) {
+	App, fmt.Println, Panic // panic terminates the program
+	---
+	// happy path
+	:start -> app:sig
+	app:err -> panic
+}
+`,
+			expected: `// Main is an entry point.
+def Main(start any) (stop any) {
+    App, fmt.Println, Panic // panic terminates the program
+    ---
+    // happy path
+    :start -> app:sig
+    app:err -> panic
+}
+`,
+		},
+		{
+			name: "nested_connections",
+			source: `def Main(start any) (stop any) {
+	p1 fmt.Println, p2 fmt.Printf, http.Get?
+	---
+	:data -> switch {
+	0 -> 'zero' -> p1
+	_ -> [
+	p2:args[ 0 ],
+	'$0' -> p2:tpl
+	]
+	}
+	:start -> { ((2 == 2) ? 'yes' : 'no') -> p1 }
+	get:res -> .body . text -> p1
+	- :data -> $consts . one -> !:flag -> p1
+}
+`,
+			expected: `def Main(start any) (stop any) {
+    p1 fmt.Println, p2 fmt.Printf, http.Get?
+    ---
+    :data -> switch {
+        0 -> 'zero' -> p1
+        _ -> [
+            p2:args[0],
+            '$0' -> p2:tpl
+        ]
+    }
+    :start -> { ((2 == 2) ? 'yes' : 'no') -> p1 }
+    get:res -> .body.text -> p1
+    -:data -> $consts.one -> !:flag -> p1
+}
+`,
+		},
+		{
+			name: "types_and_consts",
+			source: `type User struct {
+	name string
+	tags list< string >
+}
+pub const user User = {
+	name : 'John',
+	tags: [ 'a', 'b' ]
+}
+const day Day = Day :: Monday
+#extern( int_add , float_add )
+pub def Add<T int|float>(left T, right T) (res T)
+`,
+			expected: `type User struct {
+    name string
+    tags list<string>
+}
+
+pub const user User = {
+    name: 'John',
+    tags: ['a', 'b']
+}
+
+const day Day = Day::Monday
+#extern(int_add, float_add)
+pub def Add<T int | float>(left T, right T) (res T)
+`,
+		},
+		{
+			name: "union_continuation",
+			source: `type bar i32
+| vec<i32>
+`,
+			expected: `type bar i32
+    | vec<i32>
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Format([]byte(tt.source))
+			require.Nil(t, err)
+			require.Equal(t, tt.expected, string(actual))
+		})
+	}
+}
+
+func TestFormat_SyntaxError(t *testing.T) {
+	_, err := Format([]byte("def Main(start any) (stop any) {\n\t---\n"))
+	require.NotNil(t, err)
+	require.Equal(t, 2, err.Meta.Start.Line)
+}
+
+// TestFormat_Sources checks that formatting of every source file in the repository
+// is idempotent and doesn't change anything but whitespace and order of imports.
+func TestFormat_Sources(t *testing.T) {
+	for _, root := range []string{"../../../examples", "../../../e2e", "../../../std"} {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Ext(path) != ".neva" {
+				return err
+			}
+
+			source, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			formatted, compilerErr := Format(source)
+			if compilerErr != nil {
+				return nil // some e2e tests are about syntax errors
+			}
+
+			again, compilerErr := Format(formatted)
+			require.Nil(t, compilerErr, path)
+			require.Equal(t, string(formatted), string(again), path)
+
+			require.Equal(t, significantTokens(source), significantTokens(formatted), path)
+
+			return nil
+		})
+		require.NoError(t, err)
+	}
+}
+
+var importStmt = regexp.MustCompile(`import\s*{[^}]*}`)
+
+// significantTokens returns all tokens except whitespace, newlines and import statements.
+func significantTokens(source []byte) []string {
+	src := importStmt.ReplaceAllString(string(source), "")
+	lexer := generated.NewnevaLexer(antlr.NewInputStream(src))
+	lexer.RemoveErrorListeners()
+
+	var result []string
+	for tok := lexer.NextToken(); tok.GetTokenType() != antlr.TokenEOF; tok = lexer.NextToken() {
+		text := strings.TrimRight(tok.GetText(), " \t\r")
+		if tok.GetChannel() != antlr.TokenDefaultChannel || strings.TrimSpace(text) == "" {
+			continue
+		}
+		result = append(result, text)
+	}
+	return result
+}
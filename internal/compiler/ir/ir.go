@@ -6,6 +6,14 @@ import "fmt"
 type Program struct {
 	Connections map[PortAddr]PortAddr `json:"connections,omitempty"`
 	Funcs       []FuncCall            `json:"funcs,omitempty"`
+	Natives     []NativePackage       `json:"natives,omitempty"`
+}
+
+// NativePackage is Go source code that implements runtime functions of a module.
+// Its functions are referenced by FuncCall.Ref as "<module>.<name>".
+type NativePackage struct {
+	Module string            `json:"module,omitempty"` // Path of the module that owns the code.
+	Files  map[string][]byte `json:"files,omitempty"`  // Go files by name.
 }
 
 // PortAddr is a composite unique identifier for a port.
@@ -38,7 +46,7 @@ type FuncIO struct {
 
 // Message is a data that can be sent and received.
 type Message struct {
-	Type         MsgType            `json:"-"`
+	Type         MsgType            `json:"type,omitempty"`
 	Bool         bool               `json:"bool,omitempty"`
 	Int          int64              `json:"int,omitempty"`
 	Float        float64            `json:"float,omitempty"`
@@ -0,0 +1,52 @@
+package ir
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Connection is an edge of the program graph.
+// It's only used to represent connections in JSON because map keys there must be strings.
+type Connection struct {
+	Sender   PortAddr `json:"sender"`
+	Receiver PortAddr `json:"receiver"`
+}
+
+// jsonProgram is Program with connections represented as a list sorted by senders.
+type jsonProgram struct {
+	Connections []Connection    `json:"connections,omitempty"`
+	Funcs       []FuncCall      `json:"funcs,omitempty"`
+	Natives     []NativePackage `json:"natives,omitempty"`
+}
+
+func (p Program) MarshalJSON() ([]byte, error) {
+	connections := make([]Connection, 0, len(p.Connections))
+	for sender, receiver := range p.Connections {
+		connections = append(connections, Connection{Sender: sender, Receiver: receiver})
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].Sender.String() < connections[j].Sender.String()
+	})
+
+	return json.Marshal(jsonProgram{
+		Connections: connections,
+		Funcs:       p.Funcs,
+		Natives:     p.Natives,
+	})
+}
+
+func (p *Program) UnmarshalJSON(data []byte) error {
+	var prog jsonProgram
+	if err := json.Unmarshal(data, &prog); err != nil {
+		return err
+	}
+
+	p.Connections = make(map[PortAddr]PortAddr, len(prog.Connections))
+	for _, conn := range prog.Connections {
+		p.Connections[conn.Sender] = conn.Receiver
+	}
+	p.Funcs = prog.Funcs
+	p.Natives = prog.Natives
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package ir
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgram_JSON(t *testing.T) {
+	prog := Program{
+		Connections: map[PortAddr]PortAddr{
+			{Path: "b/out", Port: "res"}:                        {Path: "out", Port: "stop"},
+			{Path: "a/out", Port: "res", Idx: 1, IsArray: true}: {Path: "b/in", Port: "data"},
+		},
+		Funcs: []FuncCall{
+			{
+				Ref: "new",
+				IO:  FuncIO{Out: []PortAddr{{Path: "a/out", Port: "res"}}},
+				Msg: &Message{Type: MsgTypeInt, Int: 42},
+			},
+		},
+	}
+
+	data, err := json.Marshal(prog)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"connections": [
+			{"sender": {"path": "a/out", "port": "res", "idx": 1, "isArray": true}, "receiver": {"path": "b/in", "port": "data"}},
+			{"sender": {"path": "b/out", "port": "res"}, "receiver": {"path": "out", "port": "stop"}}
+		],
+		"funcs": [
+			{"ref": "new", "io": {"out": [{"path": "a/out", "port": "res"}]}, "msg": {"type": "int", "int": 42}}
+		]
+	}`, string(data))
+
+	var decoded Program
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, prog, decoded)
+}
@@ -2,6 +2,7 @@ package irgen
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/nevalang/neva/internal/compiler/ir"
 	src "github.com/nevalang/neva/internal/compiler/sourcecode"
@@ -31,10 +32,66 @@ type (
 func (g Generator) Generate(
 	build src.Build,
 	mainPkgName string,
+) (*ir.Program, error) {
+	return g.generate(
+		build,
+		mainPkgName,
+		"Main",
+		portsUsage{
+			in: map[relPortAddr]struct{}{
+				{Port: "start"}: {},
+			},
+			out: map[relPortAddr]struct{}{
+				{Port: "stop"}: {},
+			},
+		},
+	), nil
+}
+
+// GenerateLibrary generates program for the given component instead of Main.
+// All ports of the component are considered used.
+func (g Generator) GenerateLibrary(
+	build src.Build,
+	pkgName string,
+	componentName string,
 ) (*ir.Program, error) {
+	loc := core.Location{
+		ModRef:  build.EntryModRef,
+		Package: pkgName,
+	}
+
+	entity, _, err := src.NewScope(build, loc).Entity(core.EntityRef{Name: componentName})
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.Kind != src.ComponentEntity {
+		return nil, fmt.Errorf("entity is not a component: %v", componentName)
+	}
+
+	usage := portsUsage{
+		in:  make(map[relPortAddr]struct{}, len(entity.Component.Interface.IO.In)),
+		out: make(map[relPortAddr]struct{}, len(entity.Component.Interface.IO.Out)),
+	}
+	for name := range entity.Component.Interface.IO.In {
+		usage.in[relPortAddr{Port: name}] = struct{}{}
+	}
+	for name := range entity.Component.Interface.IO.Out {
+		usage.out[relPortAddr{Port: name}] = struct{}{}
+	}
+
+	return g.generate(build, pkgName, componentName, usage), nil
+}
+
+func (g Generator) generate(
+	build src.Build,
+	pkgName string,
+	componentName string,
+	rootPortsUsage portsUsage,
+) *ir.Program {
 	loc := core.Location{
 		ModRef:   build.EntryModRef,
-		Package:  mainPkgName,
+		Package:  pkgName,
 		Filename: "",
 	}
 
@@ -43,18 +100,11 @@ func (g Generator) Generate(
 		node: src.Node{
 			EntityRef: core.EntityRef{
 				Pkg:  "",
-				Name: "Main",
+				Name: componentName,
 			},
 			Meta: core.Meta{Location: loc}, // it's important to set location for every node, because irgen depends on it
 		},
-		portsUsage: portsUsage{
-			in: map[relPortAddr]struct{}{
-				{Port: "start"}: {},
-			},
-			out: map[relPortAddr]struct{}{
-				{Port: "stop"}: {},
-			},
-		},
+		portsUsage: rootPortsUsage,
 	}
 
 	result := &ir.Program{
@@ -65,18 +115,41 @@ func (g Generator) Generate(
 	g.processNode(
 		rootNodeCtx,
 		src.NewScope(build, loc),
+		nativeFuncs(build),
 		result,
 	)
 
 	return &ir.Program{
 		Connections: result.Connections,
 		Funcs:       result.Funcs,
-	}, nil
+		Natives:     getNativePackages(build),
+	}
+}
+
+// getNativePackages returns native code of all modules in the build, ordered by module path.
+func getNativePackages(build src.Build) []ir.NativePackage {
+	var result []ir.NativePackage
+	for modRef, mod := range build.Modules {
+		if len(mod.Native) == 0 {
+			continue
+		}
+		result = append(result, ir.NativePackage{
+			Module: modRef.Path,
+			Files:  mod.Native,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Module < result[j].Module
+	})
+
+	return result
 }
 
 func (g Generator) processNode(
 	nodeCtx nodeContext,
 	scope src.Scope,
+	natives map[core.ModuleRef]map[string]struct{},
 	result *ir.Program,
 ) {
 	entity, location, err := scope.
@@ -95,6 +168,11 @@ func (g Generator) processNode(
 		panic(err)
 	}
 
+	// externs declared by native code of the module are implemented by that code
+	if _, ok := natives[location.ModRef][runtimeFuncRef]; ok && runtimeFuncRef != "" {
+		runtimeFuncRef = location.ModRef.Path + "." + runtimeFuncRef
+	}
+
 	if runtimeFuncRef != "" {
 		cfgMsg, err := getConfigMsg(nodeCtx.node, scope)
 		if err != nil {
@@ -189,7 +267,7 @@ func (g Generator) processNode(
 			scopeToUse = scope.Relocate(location)
 		}
 
-		g.processNode(subNodeCtx, scopeToUse, result)
+		g.processNode(subNodeCtx, scopeToUse, natives, result)
 	}
 }
 
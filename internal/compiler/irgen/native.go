@@ -0,0 +1,22 @@
+package irgen
+
+import (
+	"github.com/nevalang/neva/internal/compiler"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+// nativeFuncs returns names of runtime functions declared by native code of each module in the build.
+// Problems with native code are reported by analyzer, so modules that have them are skipped here.
+func nativeFuncs(build src.Build) map[core.ModuleRef]map[string]struct{} {
+	result := map[core.ModuleRef]map[string]struct{}{}
+	for modRef, mod := range build.Modules {
+		if len(mod.Native) == 0 {
+			continue
+		}
+		if funcs, err := compiler.NativeFuncs(mod.Native); err == nil {
+			result[modRef] = funcs
+		}
+	}
+	return result
+}
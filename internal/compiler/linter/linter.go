@@ -0,0 +1,182 @@
+// Package linter implements checks of the program that is free of errors.
+// Unlike analyzer, linter never prevents compilation, it only reports warnings
+// about code that is valid but most likely not what the author intended.
+package linter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nevalang/neva/internal/compiler"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+// Rule is a single check that can be enabled or disabled in module's manifest.
+type Rule struct {
+	Name string
+	Doc  string
+	run  func(pass) []*compiler.Error
+}
+
+// Rules are all lint rules, every rule is enabled unless disabled in the manifest.
+var Rules = []Rule{
+	{
+		Name: "unused-import",
+		Doc:  "Imported package is not used in the file",
+		run:  unusedImports,
+	},
+	{
+		Name: "unused-entity",
+		Doc:  "Private entity is not used in its package",
+		run:  unusedEntities,
+	},
+	{
+		Name: "unused-type-param",
+		Doc:  "Type parameter is not used by the entity that declares it",
+		run:  unusedTypeParams,
+	},
+	{
+		Name: "shadowed-builtin",
+		Doc:  "Entity has the same name as builtin entity and makes it inaccessible",
+		run:  shadowedBuiltins,
+	},
+	{
+		Name: "shadowed-entity",
+		Doc:  "Type parameter has the same name as entity and makes it inaccessible",
+		run:  shadowedEntities,
+	},
+	{
+		Name: "unused-node",
+		Doc:  "Node is not used in the network or none of its outports are used",
+		run:  unusedNodes,
+	},
+	{
+		Name: "unused-inport",
+		Doc:  "Inport is not used in the network of its component",
+		run:  unusedInports,
+	},
+}
+
+// pass is what rules are executed over.
+type pass struct {
+	parsed   src.Build
+	analyzed src.Build
+}
+
+// entryPkgs iterates over packages of the entry module as they are written in source code.
+func (p pass) entryPkgs() func(func(string, src.Package) bool) {
+	return func(yield func(string, src.Package) bool) {
+		pkgs := p.parsed.Modules[p.parsed.EntryModRef].Packages
+		for _, name := range sortedKeys(pkgs) {
+			if !yield(name, pkgs[name]) {
+				return
+			}
+		}
+	}
+}
+
+type Linter struct{}
+
+// Lint runs enabled rules over the entry module and returns joined warnings.
+// Dependencies are never linted.
+func (l Linter) Lint(parsed src.Build, analyzed src.Build) *compiler.Error {
+	config := parsed.Modules[parsed.EntryModRef].Manifest.Lint
+
+	var warnings []*compiler.Error
+
+	known := make(map[string]bool, len(Rules))
+	for _, rule := range Rules {
+		known[rule.Name] = true
+		if enabled, ok := config[rule.Name]; ok && !enabled {
+			continue
+		}
+		for _, warning := range rule.run(pass{parsed: parsed, analyzed: analyzed}) {
+			warning.Severity = compiler.SeverityWarning
+			warning.Message = fmt.Sprintf("%v (%v)", warning.Message, rule.Name)
+			warnings = append(warnings, warning)
+		}
+	}
+
+	for _, name := range sortedKeys(config) {
+		if !known[name] {
+			warnings = append(warnings, &compiler.Error{
+				Message:  fmt.Sprintf("Unknown lint rule in manifest: %v", name),
+				Severity: compiler.SeverityWarning,
+			})
+		}
+	}
+
+	return compiler.Join(warnings...)
+}
+
+func New() Linter {
+	return Linter{}
+}
+
+var (
+	entityRefType = reflect.TypeOf(core.EntityRef{})
+	nodeType      = reflect.TypeOf(src.Node{})
+)
+
+// references returns all entity references found in the given source code abstraction.
+// Arguments of #bind directive are references to constants too.
+func references(v any) []core.EntityRef {
+	var result []core.EntityRef
+
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			if !v.IsNil() {
+				walk(v.Elem())
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				walk(v.Index(i))
+			}
+		case reflect.Map:
+			iter := v.MapRange()
+			for iter.Next() {
+				walk(iter.Value())
+			}
+		case reflect.Struct:
+			if v.Type() == entityRefType {
+				result = append(result, core.EntityRef{
+					Pkg:  v.FieldByName("Pkg").String(),
+					Name: v.FieldByName("Name").String(),
+				})
+				return
+			}
+			if v.Type() == nodeType && v.CanInterface() {
+				for _, arg := range v.Interface().(src.Node).Directives[compiler.BindDirective] {
+					result = append(result, parseRef(arg))
+				}
+			}
+			for i := 0; i < v.NumField(); i++ {
+				walk(v.Field(i))
+			}
+		}
+	}
+	walk(reflect.ValueOf(v))
+
+	return result
+}
+
+func parseRef(s string) core.EntityRef {
+	if pkg, name, ok := strings.Cut(s, "."); ok {
+		return core.EntityRef{Pkg: pkg, Name: name}
+	}
+	return core.EntityRef{Name: s}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
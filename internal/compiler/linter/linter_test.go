@@ -0,0 +1,219 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/internal/compiler/parser"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+const builtinSource = `
+pub type any
+pub type int
+pub type string
+
+#extern(del)
+pub def Del(data any) ()
+
+#extern(len)
+pub def Len<T string>(data T) (res int)
+`
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		config   map[string]bool
+		expected []string
+	}{
+		{
+			name: "clean",
+			source: `
+def Main(start any) (stop any) {
+    :start -> :stop
+}
+`,
+			expected: nil,
+		},
+		{
+			name: "unused_import",
+			source: `
+import { fmt, strings }
+
+def Main(start any) (stop any) {
+    println fmt.Println<any>
+    ---
+    :start -> println -> :stop
+}
+`,
+			expected: []string{"Unused import: strings (unused-import)"},
+		},
+		{
+			name: "unused_entity",
+			source: `
+const greeting string = 'hello'
+const unused string = 'bye'
+type unusedType int
+pub type Used int
+
+def Main(start any) (stop any) {
+    #bind(greeting)
+    new New<string>
+    del Del
+    ---
+    :start -> del
+    new -> :stop
+}
+`,
+			expected: []string{
+				"Unused entity: unused (unused-entity)",
+				"Unused entity: unusedType (unused-entity)",
+			},
+		},
+		{
+			name: "unused_type_param",
+			source: `
+pub type Pair<A, B> A
+pub def Pass<T, Y int, Z Y>(data T) (res T) {
+    :data -> :res
+}
+`,
+			expected: []string{
+				"Unused type parameter B of Pair (unused-type-param)",
+				"Unused type parameter Z of Pass (unused-type-param)",
+			},
+		},
+		{
+			name: "shadowed_builtin",
+			source: `
+pub type Len int
+`,
+			expected: []string{"Entity Len shadows builtin entity with the same name (shadowed-builtin)"},
+		},
+		{
+			name: "shadowed_entity",
+			source: `
+pub type Data int
+pub type Box<Data> Data
+pub def Pass<int>(data int) (res int) {
+    :data -> :res
+}
+`,
+			expected: []string{
+				"Type parameter Data of Box shadows entity with the same name (shadowed-entity)",
+				"Type parameter int of Pass shadows entity with the same name (shadowed-entity)",
+			},
+		},
+		{
+			name: "unused_node",
+			source: `
+def Main(start any) (stop any) {
+    pass1 Pass
+    pass2 Pass
+    unused Pass
+    del Del
+    ---
+    :start -> pass1 -> :stop
+    :start -> pass2
+    pass1 -> del
+}
+
+def Pass(data any) (res any) {
+    :data -> :res
+}
+`,
+			expected: []string{
+				"All outports of node are unused: pass2 (unused-node)",
+				"Unused node: unused (unused-node)",
+			},
+		},
+		{
+			name: "unused_inport",
+			source: `
+def Main(start any) (stop any) {
+    first First
+    ---
+    :start -> [first:data, first:extra]
+    first -> :stop
+}
+
+def First(data any, extra any) (res any) {
+    :data -> :res
+}
+
+#extern(del)
+def Sink(data any) ()
+`,
+			config:   map[string]bool{"unused-entity": false},
+			expected: []string{"Unused inport of First: extra (unused-inport)"},
+		},
+		{
+			name: "disabled_rule",
+			source: `
+import { strings }
+
+pub type Len int
+`,
+			config:   map[string]bool{"unused-import": false, "shadowed-builtin": true},
+			expected: []string{"Entity Len shadows builtin entity with the same name (shadowed-builtin)"},
+		},
+		{
+			name: "unknown_rule",
+			source: `
+pub type Foo int
+`,
+			config:   map[string]bool{"no-such-rule": true},
+			expected: []string{"Unknown lint rule in manifest: no-such-rule"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := newBuild(t, tt.source, tt.config)
+
+			err := New().Lint(build, build)
+			if tt.expected == nil {
+				require.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+
+			var actual []string
+			for _, warning := range err.Errors() {
+				require.Equal(t, "warning", warning.Severity.String())
+				actual = append(actual, warning.Message)
+			}
+			require.ElementsMatch(t, tt.expected, actual)
+		})
+	}
+}
+
+func newBuild(t *testing.T, source string, config map[string]bool) src.Build {
+	t.Helper()
+
+	p := parser.New()
+
+	entryRef := core.ModuleRef{Path: "@"}
+	files, err := p.ParseFiles(entryRef, "main", map[string][]byte{"main": []byte(source)})
+	require.Nil(t, err)
+
+	stdRef := core.ModuleRef{Path: "std"}
+	builtin, err := p.ParseFiles(stdRef, "builtin", map[string][]byte{"builtin": []byte(builtinSource)})
+	require.Nil(t, err)
+
+	return src.Build{
+		EntryModRef: entryRef,
+		Modules: map[core.ModuleRef]src.Module{
+			entryRef: {
+				Manifest: src.ModuleManifest{Lint: config},
+				Packages: map[string]src.Package{"main": files},
+			},
+			stdRef: {
+				Packages: map[string]src.Package{"builtin": builtin},
+			},
+		},
+	}
+}
@@ -0,0 +1,348 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nevalang/neva/internal/compiler"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	ts "github.com/nevalang/neva/internal/compiler/sourcecode/typesystem"
+)
+
+// unusedImports reports imports that no entity of the file refers to.
+func unusedImports(p pass) []*compiler.Error {
+	var result []*compiler.Error
+
+	for _, pkg := range p.entryPkgs() {
+		for _, fileName := range sortedKeys(pkg) {
+			file := pkg[fileName]
+
+			used := map[string]bool{}
+			for _, ref := range references(file.Entities) {
+				used[ref.Pkg] = true
+			}
+
+			for _, alias := range sortedKeys(file.Imports) {
+				if used[alias] {
+					continue
+				}
+				imp := file.Imports[alias]
+				result = append(result, &compiler.Error{
+					Message: fmt.Sprintf("Unused import: %v", alias),
+					Meta:    &imp.Meta,
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// unusedEntities reports private entities that are not referenced from their package.
+// Main and test components are used by the compiler itself.
+func unusedEntities(p pass) []*compiler.Error {
+	var result []*compiler.Error
+
+	for _, pkg := range p.entryPkgs() {
+		used := map[string]bool{}
+		for _, fileName := range sortedKeys(pkg) {
+			for _, ref := range references(pkg[fileName].Entities) {
+				if ref.Pkg == "" {
+					used[ref.Name] = true
+				}
+			}
+		}
+
+		for _, fileName := range sortedKeys(pkg) {
+			entities := pkg[fileName].Entities
+			for _, name := range sortedKeys(entities) {
+				entity := entities[name]
+				if entity.IsPublic || used[name] || name == "Main" {
+					continue
+				}
+				if strings.HasSuffix(fileName, "_test") && strings.HasPrefix(name, "Test") {
+					continue
+				}
+				result = append(result, &compiler.Error{
+					Message: fmt.Sprintf("Unused entity: %v", name),
+					Meta:    entity.Meta(),
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// unusedTypeParams reports type parameters that are not referenced by their entity.
+// Components implemented by runtime are skipped because their parameters are used by the runtime.
+func unusedTypeParams(p pass) []*compiler.Error {
+	var result []*compiler.Error
+
+	for _, pkg := range p.entryPkgs() {
+		for _, fileName := range sortedKeys(pkg) {
+			entities := pkg[fileName].Entities
+			for _, name := range sortedKeys(entities) {
+				entity := entities[name]
+
+				var (
+					params []ts.Param
+					body   any
+				)
+				switch entity.Kind {
+				case src.TypeEntity:
+					if entity.Type.BodyExpr == nil {
+						continue
+					}
+					params, body = entity.Type.Params, entity.Type.BodyExpr
+				case src.InterfaceEntity:
+					params, body = entity.Interface.TypeParams.Params, entity.Interface.IO
+				case src.ComponentEntity:
+					if _, ok := entity.Component.Directives[compiler.ExternDirective]; ok {
+						continue
+					}
+					component := entity.Component
+					params = component.Interface.TypeParams.Params
+					body = []any{component.Interface.IO, component.Nodes, component.Net}
+				default:
+					continue
+				}
+
+				used := map[string]bool{}
+				for _, ref := range references(body) {
+					if ref.Pkg == "" {
+						used[ref.Name] = true
+					}
+				}
+				for _, param := range params { // constraints can refer to other params
+					for _, ref := range references(param.Constr) {
+						if ref.Pkg == "" && ref.Name != param.Name {
+							used[ref.Name] = true
+						}
+					}
+				}
+
+				for _, param := range params {
+					if used[param.Name] {
+						continue
+					}
+					result = append(result, &compiler.Error{
+						Message: fmt.Sprintf("Unused type parameter %v of %v", param.Name, name),
+						Meta:    entity.Meta(),
+					})
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// shadowedBuiltins reports entities with the same names as entities of the std/builtin package.
+func shadowedBuiltins(p pass) []*compiler.Error {
+	builtins := builtinNames(p)
+
+	var result []*compiler.Error
+
+	for _, pkg := range p.entryPkgs() {
+		for _, fileName := range sortedKeys(pkg) {
+			entities := pkg[fileName].Entities
+			for _, name := range sortedKeys(entities) {
+				if !builtins[name] {
+					continue
+				}
+				result = append(result, &compiler.Error{
+					Message: fmt.Sprintf("Entity %v shadows builtin entity with the same name", name),
+					Meta:    entities[name].Meta(),
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// builtinNames returns names of entities of the std/builtin package.
+func builtinNames(p pass) map[string]bool {
+	builtins := map[string]bool{}
+	for modRef, mod := range p.analyzed.Modules {
+		if modRef.Path != "std" {
+			continue
+		}
+		for result := range mod.Packages["builtin"].Entities() {
+			builtins[result.EntityName] = true
+		}
+	}
+	return builtins
+}
+
+// shadowedEntities reports type parameters with the same names as entities of their package or builtin ones.
+// Entities can't be referred to from the entity that declares such parameter.
+func shadowedEntities(p pass) []*compiler.Error {
+	builtins := builtinNames(p)
+
+	var result []*compiler.Error
+
+	for _, pkg := range p.entryPkgs() {
+		local := map[string]bool{}
+		for entity := range pkg.Entities() {
+			local[entity.EntityName] = true
+		}
+
+		for _, fileName := range sortedKeys(pkg) {
+			entities := pkg[fileName].Entities
+			for _, name := range sortedKeys(entities) {
+				entity := entities[name]
+
+				var params []ts.Param
+				switch entity.Kind {
+				case src.TypeEntity:
+					params = entity.Type.Params
+				case src.InterfaceEntity:
+					params = entity.Interface.TypeParams.Params
+				case src.ComponentEntity:
+					params = entity.Component.Interface.TypeParams.Params
+				}
+
+				for _, param := range params {
+					if !local[param.Name] && !builtins[param.Name] {
+						continue
+					}
+					result = append(result, &compiler.Error{
+						Message: fmt.Sprintf("Type parameter %v of %v shadows entity with the same name", param.Name, name),
+						Meta:    entity.Meta(),
+					})
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// unusedNodes reports nodes that the network doesn't refer to and nodes none of which outports are used.
+// Compiler removes unused nodes and discards messages from unused outports.
+func unusedNodes(p pass) []*compiler.Error {
+	var result []*compiler.Error
+
+	for pkgName, pkg := range p.entryPkgs() {
+		for _, fileName := range sortedKeys(pkg) {
+			scope := src.NewScope(p.analyzed, core.Location{
+				ModRef:   p.parsed.EntryModRef,
+				Package:  pkgName,
+				Filename: fileName,
+			})
+
+			entities := pkg[fileName].Entities
+			for _, name := range sortedKeys(entities) {
+				entity := entities[name]
+				if entity.Kind != src.ComponentEntity {
+					continue
+				}
+
+				usage := netUsage(entity.Component.Net)
+
+				for _, nodeName := range sortedKeys(entity.Component.Nodes) {
+					node := entity.Component.Nodes[nodeName]
+
+					nodeUsage, ok := usage[nodeName]
+					if !ok {
+						result = append(result, &compiler.Error{
+							Message: fmt.Sprintf("Unused node: %v", nodeName),
+							Meta:    &node.Meta,
+						})
+						continue
+					}
+
+					if len(nodeUsage.out) > 0 {
+						continue
+					}
+
+					nodeEntity, _, err := scope.Entity(node.EntityRef)
+					if err != nil {
+						continue
+					}
+
+					var outports map[string]src.Port
+					switch nodeEntity.Kind {
+					case src.ComponentEntity:
+						outports = nodeEntity.Component.Interface.IO.Out
+					case src.InterfaceEntity:
+						outports = nodeEntity.Interface.IO.Out
+					}
+					if len(outports) == 0 { // e.g. Del
+						continue
+					}
+
+					result = append(result, &compiler.Error{
+						Message: fmt.Sprintf("All outports of node are unused: %v", nodeName),
+						Meta:    &node.Meta,
+					})
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// unusedInports reports inports that the network of their component doesn't refer to.
+// Components implemented by runtime don't have network.
+func unusedInports(p pass) []*compiler.Error {
+	var result []*compiler.Error
+
+	for _, pkg := range p.entryPkgs() {
+		for _, fileName := range sortedKeys(pkg) {
+			entities := pkg[fileName].Entities
+			for _, name := range sortedKeys(entities) {
+				entity := entities[name]
+				if entity.Kind != src.ComponentEntity {
+					continue
+				}
+				if _, ok := entity.Component.Directives[compiler.ExternDirective]; ok {
+					continue
+				}
+
+				used := netUsage(entity.Component.Net)["in"].out // self inports are outports for the network
+
+				inports := entity.Component.Interface.IO.In
+				for _, portName := range sortedKeys(inports) {
+					if used[portName] {
+						continue
+					}
+					port := inports[portName]
+					result = append(result, &compiler.Error{
+						Message: fmt.Sprintf("Unused inport of %v: %v", name, portName),
+						Meta:    &port.Meta,
+					})
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+type nodeUsage struct {
+	in, out map[string]bool
+}
+
+// netUsage returns ports of every node the network refers to, by node name.
+// Ports used without name are stored as empty strings.
+func netUsage(net []src.Connection) map[string]nodeUsage {
+	usage := map[string]nodeUsage{}
+	for _, conn := range net {
+		conn.WalkPorts(func(addr src.PortAddr, out bool) {
+			if _, ok := usage[addr.Node]; !ok {
+				usage[addr.Node] = nodeUsage{in: map[string]bool{}, out: map[string]bool{}}
+			}
+			if out {
+				usage[addr.Node].out[addr.Port] = true
+			} else {
+				usage[addr.Node].in[addr.Port] = true
+			}
+		})
+	}
+	return usage
+}
@@ -0,0 +1,144 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"maps"
+	"path"
+	"slices"
+)
+
+// nativeRuntimePkg is the only non-standard package that native code of the module can depend on.
+const nativeRuntimePkg = "github.com/nevalang/neva/pkg/runtime"
+
+// NativeFuncs returns names of runtime functions that Registry function of the module's native code declares.
+// Keys are evaluated as Go constants, so they can be literals, constants or constant expressions.
+// Keys of map literals, map index assignments and first arguments of Register and MustRegister calls are used.
+// Native code that can't be parsed, lacks Registry function or has keys that are not constant is an error,
+// because otherwise externs of the module would silently refer to the standard library.
+func NativeFuncs(files map[string][]byte) (map[string]struct{}, error) {
+	fset := token.NewFileSet()
+
+	var (
+		parsed []*ast.File
+		errs   []error
+	)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		file, err := parser.ParseFile(fset, path.Join("native", name), files[name], 0)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		parsed = append(parsed, file)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	// Only constants are evaluated, so type errors, e.g. in uses of runtime package that is not available here,
+	// are ignored. Go compiler reports them when the program is built.
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+	conf := types.Config{
+		Importer: nativeImporter{},
+		Error:    func(error) {},
+	}
+	_, _ = conf.Check("native", fset, parsed, info)
+
+	keys := map[string]struct{}{}
+	addKey := func(expr ast.Expr) {
+		tv := info.Types[expr]
+		if tv.Value == nil || tv.Value.Kind() != constant.String {
+			errs = append(errs, fmt.Errorf("%v: registry key must be a constant string", fset.Position(expr.Pos())))
+			return
+		}
+		keys[constant.StringVal(tv.Value)] = struct{}{}
+	}
+
+	hasRegistry := false
+	for _, file := range parsed {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Name.Name != "Registry" || fn.Body == nil {
+				continue
+			}
+			hasRegistry = true
+
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.CompositeLit:
+					if !isStringMap(info.TypeOf(n)) {
+						return true
+					}
+					for _, elt := range n.Elts {
+						if kv, ok := elt.(*ast.KeyValueExpr); ok {
+							addKey(kv.Key)
+						}
+					}
+				case *ast.AssignStmt:
+					for _, lhs := range n.Lhs {
+						if idx, ok := lhs.(*ast.IndexExpr); ok && isStringMap(info.TypeOf(idx.X)) {
+							addKey(idx.Index)
+						}
+					}
+				case *ast.CallExpr:
+					sel, ok := n.Fun.(*ast.SelectorExpr)
+					if ok && (sel.Sel.Name == "Register" || sel.Sel.Name == "MustRegister") && len(n.Args) == 2 {
+						addKey(n.Args[0])
+					}
+				case *ast.FuncLit:
+					return false // function bodies of the registry's values are not part of it
+				}
+				return true
+			})
+		}
+	}
+
+	if !hasRegistry {
+		errs = append(errs, errors.New("native code must declare func Registry() runtime.Registry"))
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return keys, nil
+}
+
+func isStringMap(typ types.Type) bool {
+	if typ == nil {
+		return false
+	}
+	m, ok := typ.Underlying().(*types.Map)
+	if !ok {
+		return false
+	}
+	key, ok := m.Key().Underlying().(*types.Basic)
+	return ok && key.Info()&types.IsString != 0
+}
+
+// nativeImporter imports empty packages, except for the runtime package
+// that only declares Registry type, so keys of its literals are typed as strings.
+type nativeImporter struct{}
+
+func (nativeImporter) Import(importPath string) (*types.Package, error) {
+	pkg := types.NewPackage(importPath, path.Base(importPath))
+
+	if importPath == nativeRuntimePkg {
+		creator := types.NewTypeName(token.NoPos, pkg, "FuncCreator", nil)
+		types.NewNamed(creator, types.NewInterfaceType(nil, nil), nil)
+		pkg.Scope().Insert(creator)
+
+		registry := types.NewTypeName(token.NoPos, pkg, "Registry", nil)
+		types.NewNamed(registry, types.NewMap(types.Typ[types.String], creator.Type()), nil)
+		pkg.Scope().Insert(registry)
+	}
+
+	pkg.MarkComplete()
+
+	return pkg, nil
+}
@@ -0,0 +1,71 @@
+package compiler_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/internal/compiler"
+)
+
+func TestNativeFuncs(t *testing.T) {
+	files := map[string][]byte{
+		"registry.go": []byte(`package native
+
+import "github.com/nevalang/neva/pkg/runtime"
+
+const prefix = "str_"
+
+const upperName = prefix + "upper"
+
+func Registry() runtime.Registry {
+	r := runtime.Registry{
+		"reverse": reverse{size: 1},
+		upperName: upper{},
+	}
+	r[prefix+"lower"] = lower{}
+	r.MustRegister("trim", trim{})
+	return r
+}
+
+func helpers() map[string]int {
+	return map[string]int{"helper": 1}
+}
+`),
+	}
+
+	funcs, err := compiler.NativeFuncs(files)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{
+		"reverse":   {},
+		"str_upper": {},
+		"str_lower": {},
+		"trim":      {},
+	}, funcs)
+}
+
+func TestNativeFuncs_Errors(t *testing.T) {
+	_, err := compiler.NativeFuncs(map[string][]byte{
+		"registry.go": []byte(`package native
+
+import "github.com/nevalang/neva/pkg/runtime"
+
+var name = "reverse"
+
+func Registry() runtime.Registry {
+	return runtime.Registry{name: reverse{}}
+}
+`),
+	})
+	require.EqualError(t, err, "native/registry.go:8:26: registry key must be a constant string")
+
+	_, err = compiler.NativeFuncs(map[string][]byte{
+		"broken.go": []byte("package native\nfunc {"),
+	})
+	require.ErrorContains(t, err, "native/broken.go:2:6: expected 'IDENT', found '{'")
+
+	_, err = compiler.NativeFuncs(map[string][]byte{
+		"funcs.go": []byte("package native\n"),
+	})
+	require.EqualError(t, err, "native code must declare func Registry() runtime.Registry")
+}
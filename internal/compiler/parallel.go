@@ -0,0 +1,45 @@
+package compiler
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Parallel calls f for every index in [0, n) using at most workers goroutines.
+// Non-positive number of workers means one worker per available CPU.
+// Each call must only write to its own index of the results so that they don't depend on scheduling.
+func Parallel(workers, n int, f func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				f(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+}
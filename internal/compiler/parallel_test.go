@@ -0,0 +1,182 @@
+package compiler_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/internal/builder"
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/analyzer"
+	"github.com/nevalang/neva/internal/compiler/parser"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	ts "github.com/nevalang/neva/internal/compiler/sourcecode/typesystem"
+	"github.com/nevalang/neva/pkg"
+)
+
+func TestParallel(t *testing.T) {
+	for _, workers := range []int{0, 1, 3, 100} {
+		results := make([]int, 50)
+		compiler.Parallel(workers, len(results), func(i int) {
+			results[i] = i * i
+		})
+		for i, result := range results {
+			require.Equal(t, i*i, result)
+		}
+	}
+}
+
+func TestParallelErrorsAreDeterministic(t *testing.T) {
+	raw := loadGeneratedBuild(t, 20, 3, true)
+	prsr := parser.New()
+
+	parsed, err := prsr.WithWorkers(1).ParseModules(raw.Modules)
+	require.Nil(t, err)
+	build := src.Build{EntryModRef: raw.EntryModRef, Modules: parsed}
+
+	_, expected := newAnalyzer().WithWorkers(1).AnalyzeBuild(build)
+	require.NotNil(t, expected)
+	require.Len(t, expected.Errors(), 20)
+
+	for range 10 {
+		_, actual := newAnalyzer().AnalyzeBuild(build)
+		require.Equal(t, expected.Error(), actual.Error())
+	}
+
+	// every generated file is broken but only the first one by location is reported
+	for _, mod := range raw.Modules {
+		for _, files := range mod.Packages {
+			for name, content := range files {
+				files[name] = append(content, "}"...)
+			}
+		}
+	}
+
+	_, expected = prsr.WithWorkers(1).ParseModules(raw.Modules)
+	require.NotNil(t, expected)
+
+	for range 10 {
+		_, actual := prsr.ParseModules(raw.Modules)
+		require.Equal(t, expected.Error(), actual.Error())
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	raw := loadGeneratedBuild(b, 200, 5, false)
+
+	for _, bench := range []struct {
+		name   string
+		parser parser.Parser
+	}{
+		{"sequential", parser.New().WithWorkers(1)},
+		{"parallel", parser.New()},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for range b.N {
+				if _, err := bench.parser.ParseModules(raw.Modules); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAnalyze(b *testing.B) {
+	raw := loadGeneratedBuild(b, 200, 5, false)
+
+	parsed, err := parser.New().ParseModules(raw.Modules)
+	require.Nil(b, err)
+	build := src.Build{EntryModRef: raw.EntryModRef, Modules: parsed}
+
+	for _, bench := range []struct {
+		name     string
+		analyzer analyzer.Analyzer
+	}{
+		{"sequential", newAnalyzer().WithWorkers(1)},
+		{"parallel", newAnalyzer()},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for range b.N {
+				if _, err := bench.analyzer.AnalyzeBuild(build); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func newAnalyzer() analyzer.Analyzer {
+	terminator := ts.Terminator{}
+	checker := ts.MustNewSubtypeChecker(terminator)
+	return analyzer.MustNew(ts.MustNewResolver(ts.Validator{}, checker, terminator))
+}
+
+// loadGeneratedBuild generates module with a chain of packages where each package imports the previous one.
+// If broken is true, every package contains a component with incompatible types.
+func loadGeneratedBuild(tb testing.TB, pkgs, filesPerPkg int, broken bool) compiler.RawBuild {
+	tb.Helper()
+
+	dir := tb.TempDir()
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		require.NoError(tb, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(tb, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write("neva.yml", "neva: "+pkg.Version+"\n")
+
+	for i := range pkgs {
+		for j := range filesPerPkg {
+			var sb strings.Builder
+
+			if i == 0 {
+				sb.WriteString("import { fmt }\n")
+			} else {
+				fmt.Fprintf(&sb, "import {\n\tfmt\n\t@:p%d\n}\n", i-1)
+			}
+
+			fmt.Fprintf(&sb, `
+pub type Data%d struct {
+	id int
+	name string
+}
+
+pub def Print%d(data any) (sig any) {
+	fmt.Println
+	---
+	:data -> println -> :sig
+}
+`, j, j)
+
+			if i > 0 {
+				fmt.Fprintf(&sb, `
+pub def Chain%d(data any) (sig any) {
+	prev p%d.Print%d
+	---
+	:data -> prev -> :sig
+}
+`, j, i-1, j)
+			}
+
+			if broken && j == 0 {
+				sb.WriteString(`
+def Broken(data int) (res string) {
+	:data -> :res
+}
+`)
+			}
+
+			write(fmt.Sprintf("p%d/f%d.neva", i, j), sb.String())
+		}
+	}
+
+	raw, _, err := builder.MustNew(parser.New()).Build(context.Background(), dir)
+	require.Nil(tb, err)
+
+	return raw
+}
@@ -18,13 +18,23 @@ func (c *CustomErrorListener) SyntaxError(
 	msg string,
 	e antlr.RecognitionException,
 ) {
+	meta := &core.Meta{
+		Start: core.Position{
+			Line:   line,
+			Column: column,
+		},
+	}
+
+	// underline offending token if it's known
+	if token, ok := offendingSymbol.(antlr.Token); ok && token.GetTokenType() != antlr.TokenEOF {
+		meta.Stop = core.Position{
+			Line:   token.GetLine(),
+			Column: stopColumn(token),
+		}
+	}
+
 	c.Errors = append(c.Errors, &compiler.Error{
 		Message: msg,
-		Meta: &core.Meta{
-			Start: core.Position{
-				Line:   line,
-				Column: column,
-			},
-		},
+		Meta:    meta,
 	})
 }
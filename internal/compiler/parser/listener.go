@@ -1,6 +1,10 @@
 package parser
 
 import (
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+
 	generated "github.com/nevalang/neva/internal/compiler/parser/generated"
 	src "github.com/nevalang/neva/internal/compiler/sourcecode"
 	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
@@ -10,11 +14,41 @@ type treeShapeListener struct {
 	*generated.BasenevaListener
 	loc   core.Location
 	state src.File
+	docs  map[int]string // doc comments by index of the first token of the statement
 }
 
 func (s *treeShapeListener) EnterProg(actx *generated.ProgContext) {
 	s.state.Entities = map[string]src.Entity{}
 	s.state.Imports = map[string]src.Import{}
+	s.docs = map[int]string{}
+
+	// doc comment is a block of comments on consecutive lines directly above the statement,
+	// statements absorb trailing newlines so lines of tokens are compared instead
+	var (
+		lines    []string
+		lastLine int // line of the last comment in the block
+		stmtLine int // line where previous statement ends
+	)
+	for _, child := range actx.GetChildren() {
+		switch child := child.(type) {
+		case generated.IStmtContext:
+			if len(lines) > 0 && child.GetStart().GetLine() == lastLine+1 {
+				s.docs[child.GetStart().GetTokenIndex()] = strings.Join(lines, "\n")
+			}
+			lines, stmtLine = nil, child.GetStop().GetLine()
+		case antlr.TerminalNode:
+			token := child.GetSymbol()
+			if !strings.HasPrefix(token.GetText(), "//") || token.GetLine() == stmtLine {
+				continue // not a comment or comment after the code on the same line
+			}
+			if len(lines) > 0 && token.GetLine() != lastLine+1 {
+				lines = nil
+			}
+			text := strings.TrimPrefix(strings.TrimPrefix(token.GetText(), "//"), " ")
+			lines = append(lines, strings.TrimRight(text, " \t\r"))
+			lastLine = token.GetLine()
+		}
+	}
 }
 
 func (s *treeShapeListener) EnterImportDef(actx *generated.ImportDefContext) {
@@ -34,6 +68,7 @@ func (s *treeShapeListener) EnterTypeStmt(actx *generated.TypeStmtContext) {
 	}
 
 	parsedEntity.IsPublic = actx.PUB_KW() != nil
+	parsedEntity.Doc = s.docs[actx.GetStart().GetTokenIndex()]
 	name := typeDef.IDENTIFIER().GetText()
 	s.state.Entities[name] = parsedEntity
 }
@@ -47,6 +82,7 @@ func (s *treeShapeListener) EnterConstStmt(actx *generated.ConstStmtContext) {
 	}
 
 	parsedEntity.IsPublic = actx.PUB_KW() != nil
+	parsedEntity.Doc = s.docs[actx.GetStart().GetTokenIndex()]
 	name := constDef.IDENTIFIER().GetText()
 	s.state.Entities[name] = parsedEntity
 }
@@ -59,6 +95,7 @@ func (s *treeShapeListener) EnterInterfaceStmt(actx *generated.InterfaceStmtCont
 	}
 	s.state.Entities[name] = src.Entity{
 		IsPublic:  actx.PUB_KW() != nil,
+		Doc:       s.docs[actx.GetStart().GetTokenIndex()],
 		Kind:      src.InterfaceEntity,
 		Interface: v,
 	}
@@ -73,6 +110,7 @@ func (s *treeShapeListener) EnterCompStmt(actx *generated.CompStmtContext) {
 	}
 
 	parsedCompEntity.IsPublic = actx.PUB_KW() != nil
+	parsedCompEntity.Doc = s.docs[actx.GetStart().GetTokenIndex()]
 	parsedCompEntity.Component.Directives = s.parseCompilerDirectives(
 		actx.CompilerDirectives(),
 	)
@@ -43,7 +43,7 @@ func (s *treeShapeListener) parseImport(actx generated.IImportDefContext) (src.I
 			},
 			Stop: core.Position{
 				Line:   actx.GetStop().GetLine(),
-				Column: actx.GetStop().GetColumn(),
+				Column: stopColumn(actx.GetStop()),
 			},
 			Location: s.loc,
 		},
@@ -80,7 +80,7 @@ func (s *treeShapeListener) parseTypeParams(
 			},
 			Stop: core.Position{
 				Line:   params.GetStop().GetLine(),
-				Column: params.GetStop().GetColumn(),
+				Column: stopColumn(params.GetStop()),
 			},
 			Location: s.loc,
 		},
@@ -114,7 +114,7 @@ func (s *treeShapeListener) parseTypeExpr(expr generated.ITypeExprContext) (ts.E
 					},
 					Stop: core.Position{
 						Line:   expr.GetStop().GetLine(),
-						Column: expr.GetStop().GetColumn(),
+						Column: stopColumn(expr.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -140,11 +140,11 @@ func (s *treeShapeListener) parseTypeExpr(expr generated.ITypeExprContext) (ts.E
 				Text: expr.GetText(),
 				Start: core.Position{
 					Line:   expr.GetStart().GetLine(),
-					Column: expr.GetStart().GetLine(),
+					Column: expr.GetStart().GetColumn(),
 				},
 				Stop: core.Position{
 					Line:   expr.GetStop().GetLine(),
-					Column: expr.GetStop().GetLine(),
+					Column: stopColumn(expr.GetStop()),
 				},
 				Location: s.loc,
 			},
@@ -172,7 +172,7 @@ func (s *treeShapeListener) getTypeExprMeta(expr generated.ITypeExprContext) cor
 		},
 		Stop: core.Position{
 			Line:   stop.GetLine(),
-			Column: stop.GetColumn(),
+			Column: stopColumn(stop),
 		},
 		Location: s.loc,
 	}
@@ -212,7 +212,7 @@ func (s *treeShapeListener) parseUnionExpr(unionExpr generated.IUnionTypeExprCon
 			},
 			Stop: core.Position{
 				Line:   unionExpr.GetStop().GetLine(),
-				Column: unionExpr.GetStop().GetColumn(),
+				Column: stopColumn(unionExpr.GetStop()),
 			},
 			Location: s.loc,
 		},
@@ -240,7 +240,7 @@ func (s *treeShapeListener) parseLitExpr(litExpr generated.ITypeLitExprContext)
 			},
 			Stop: core.Position{
 				Line:   litExpr.GetStop().GetLine(),
-				Column: litExpr.GetStop().GetColumn(),
+				Column: stopColumn(litExpr.GetStop()),
 			},
 			Location: s.loc,
 		},
@@ -265,7 +265,7 @@ func (s *treeShapeListener) parseEnumExpr(enumExpr generated.IEnumTypeExprContex
 		},
 		Stop: core.Position{
 			Line:   enumExpr.GetStop().GetLine(),
-			Column: enumExpr.GetStop().GetColumn(),
+			Column: stopColumn(enumExpr.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -306,7 +306,7 @@ func (s *treeShapeListener) parseStructExpr(
 		},
 		Stop: core.Position{
 			Line:   structExpr.GetStop().GetLine(),
-			Column: structExpr.GetStop().GetColumn(),
+			Column: stopColumn(structExpr.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -327,7 +327,7 @@ func (s *treeShapeListener) parseTypeInstExpr(instExpr generated.ITypeInstExprCo
 				},
 				Stop: core.Position{
 					Line:   instExpr.GetStop().GetLine(),
-					Column: instExpr.GetStop().GetColumn(),
+					Column: stopColumn(instExpr.GetStop()),
 				},
 				Location: s.loc,
 			},
@@ -364,7 +364,7 @@ func (s *treeShapeListener) parseTypeInstExpr(instExpr generated.ITypeInstExprCo
 		},
 		Stop: core.Position{
 			Line:   instExpr.GetStop().GetLine(),
-			Column: instExpr.GetStop().GetColumn(),
+			Column: stopColumn(instExpr.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -381,7 +381,7 @@ func (s *treeShapeListener) parseEntityRef(expr generated.IEntityRefContext) (co
 		},
 		Stop: core.Position{
 			Line:   expr.GetStart().GetLine(),
-			Column: expr.GetStop().GetColumn(),
+			Column: stopColumn(expr.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -451,7 +451,7 @@ func (s *treeShapeListener) parsePorts(
 				},
 				Stop: core.Position{
 					Line:   port.GetStop().GetLine(),
-					Column: port.GetStop().GetColumn(),
+					Column: stopColumn(port.GetStop()),
 				},
 				Location: s.loc,
 			},
@@ -485,7 +485,7 @@ func (s *treeShapeListener) parseInterfaceDef(
 		},
 		Stop: core.Position{
 			Line:   actx.GetStop().GetLine(),
-			Column: actx.GetStop().GetColumn(),
+			Column: stopColumn(actx.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -524,7 +524,7 @@ func (s *treeShapeListener) parseNodes(
 					},
 					Stop: core.Position{
 						Line:   node.GetStop().GetLine(),
-						Column: node.GetStop().GetColumn(),
+						Column: stopColumn(node.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -575,7 +575,7 @@ func (s *treeShapeListener) parseNodes(
 				},
 				Stop: core.Position{
 					Line:   node.GetStop().GetLine(),
-					Column: node.GetStop().GetColumn(),
+					Column: stopColumn(node.GetStop()),
 				},
 				Location: s.loc,
 			},
@@ -613,7 +613,7 @@ func (s *treeShapeListener) parsePortAddr(
 		},
 		Stop: core.Position{
 			Line:   expr.GetStart().GetLine(),
-			Column: expr.GetStop().GetColumn(),
+			Column: stopColumn(expr.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -730,7 +730,7 @@ func (s *treeShapeListener) parsePrimitiveConstLiteral(
 			},
 			Stop: core.Position{
 				Line:   lit.GetStop().GetLine(),
-				Column: lit.GetStop().GetColumn(),
+				Column: stopColumn(lit.GetStop()),
 			},
 			Location: s.loc,
 		},
@@ -750,7 +750,7 @@ func (s *treeShapeListener) parsePrimitiveConstLiteral(
 					},
 					Stop: core.Position{
 						Line:   lit.GetStop().GetLine(),
-						Column: lit.GetStop().GetColumn(),
+						Column: stopColumn(lit.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -773,7 +773,7 @@ func (s *treeShapeListener) parsePrimitiveConstLiteral(
 					},
 					Stop: core.Position{
 						Line:   lit.GetStop().GetLine(),
-						Column: lit.GetStop().GetColumn(),
+						Column: stopColumn(lit.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -799,7 +799,7 @@ func (s *treeShapeListener) parsePrimitiveConstLiteral(
 					},
 					Stop: core.Position{
 						Line:   lit.GetStop().GetLine(),
-						Column: lit.GetStop().GetColumn(),
+						Column: stopColumn(lit.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -858,7 +858,7 @@ func (s *treeShapeListener) parseMessage(
 			},
 			Stop: core.Position{
 				Line:   constVal.GetStop().GetLine(),
-				Column: constVal.GetStop().GetColumn(),
+				Column: stopColumn(constVal.GetStop()),
 			},
 			Location: s.loc,
 		},
@@ -878,7 +878,7 @@ func (s *treeShapeListener) parseMessage(
 					},
 					Stop: core.Position{
 						Line:   constVal.GetStop().GetLine(),
-						Column: constVal.GetStop().GetColumn(),
+						Column: stopColumn(constVal.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -898,7 +898,7 @@ func (s *treeShapeListener) parseMessage(
 					},
 					Stop: core.Position{
 						Line:   constVal.GetStop().GetLine(),
-						Column: constVal.GetStop().GetColumn(),
+						Column: stopColumn(constVal.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -921,7 +921,7 @@ func (s *treeShapeListener) parseMessage(
 					},
 					Stop: core.Position{
 						Line:   constVal.GetStop().GetLine(),
-						Column: constVal.GetStop().GetColumn(),
+						Column: stopColumn(constVal.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -965,11 +965,11 @@ func (s *treeShapeListener) parseMessage(
 					Text: item.GetText(),
 					Start: core.Position{
 						Line:   item.GetStart().GetLine(),
-						Column: item.GetStart().GetLine(),
+						Column: item.GetStart().GetColumn(),
 					},
 					Stop: core.Position{
 						Line:   item.GetStop().GetLine(),
-						Column: item.GetStop().GetLine(),
+						Column: stopColumn(item.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -1091,7 +1091,7 @@ func (s *treeShapeListener) parseTypeDef(
 				},
 				Stop: core.Position{
 					Line:   actx.GetStop().GetLine(),
-					Column: actx.GetStop().GetColumn(),
+					Column: stopColumn(actx.GetStop()),
 				},
 				Location: s.loc,
 			},
@@ -1117,7 +1117,7 @@ func (s *treeShapeListener) parseConstDef(
 		},
 		Stop: core.Position{
 			Line:   actx.GetStop().GetLine(),
-			Column: actx.GetStop().GetColumn(),
+			Column: stopColumn(actx.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -1188,7 +1188,7 @@ func (s *treeShapeListener) parseCompDef(actx generated.ICompDefContext) (src.En
 		},
 		Stop: core.Position{
 			Line:   actx.GetStop().GetLine(),
-			Column: actx.GetStop().GetColumn(),
+			Column: stopColumn(actx.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -1265,7 +1265,7 @@ func (s *treeShapeListener) parseConnection(connDef generated.IConnDefContext) (
 		},
 		Stop: core.Position{
 			Line:   connDef.GetStop().GetLine(),
-			Column: connDef.GetStop().GetColumn(),
+			Column: stopColumn(connDef.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -1301,7 +1301,7 @@ func (s *treeShapeListener) parseArrayBypassConn(
 		},
 		Stop: core.Position{
 			Line:   arrBypassConn.GetStop().GetLine(),
-			Column: arrBypassConn.GetStop().GetColumn(),
+			Column: stopColumn(arrBypassConn.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -1344,7 +1344,7 @@ func (s *treeShapeListener) parseNormConn(
 		},
 		Stop: core.Position{
 			Line:   actx.GetStop().GetLine(),
-			Column: actx.GetStop().GetColumn(),
+			Column: stopColumn(actx.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -1382,7 +1382,7 @@ func (s *treeShapeListener) parseSenderSide(
 		},
 		Stop: core.Position{
 			Line:   actx.GetStop().GetLine(),
-			Column: actx.GetStop().GetColumn(),
+			Column: stopColumn(actx.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -1429,7 +1429,7 @@ func (s *treeShapeListener) parseSingleReceiverSide(
 		},
 		Stop: core.Position{
 			Line:   actx.GetStop().GetLine(),
-			Column: actx.GetStop().GetColumn(),
+			Column: stopColumn(actx.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -1462,7 +1462,7 @@ func (s *treeShapeListener) parseSwitchStmt(
 		},
 		Stop: core.Position{
 			Line:   switchStmt.GetStop().GetLine(),
-			Column: switchStmt.GetStop().GetColumn(),
+			Column: stopColumn(switchStmt.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -1525,7 +1525,7 @@ func (s *treeShapeListener) parseReceiverSide(
 		},
 		Stop: core.Position{
 			Line:   actx.GetStop().GetLine(),
-			Column: actx.GetStop().GetColumn(),
+			Column: stopColumn(actx.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -1578,7 +1578,7 @@ func (s *treeShapeListener) parseDeferredConn(
 		},
 		Stop: core.Position{
 			Line:   deferredConns.GetStop().GetLine(),
-			Column: deferredConns.GetStop().GetColumn(),
+			Column: stopColumn(deferredConns.GetStop()),
 		},
 		Location: s.loc,
 	}
@@ -1604,6 +1604,7 @@ func (s *treeShapeListener) parseSingleSender(
 	rangeExprSender := senderSide.RangeExpr()
 	ternaryExprSender := senderSide.TernaryExpr()
 	binaryExprSender := senderSide.BinaryExpr()
+	unaryExprSender := senderSide.UnaryExpr()
 
 	if portSender == nil &&
 		constRefSender == nil &&
@@ -1611,7 +1612,8 @@ func (s *treeShapeListener) parseSingleSender(
 		rangeExprSender == nil &&
 		structSelectors == nil &&
 		ternaryExprSender == nil &&
-		binaryExprSender == nil {
+		binaryExprSender == nil &&
+		unaryExprSender == nil {
 		return src.ConnectionSender{}, &compiler.Error{
 			Message: "Sender side is missing in connection",
 			Meta: &core.Meta{
@@ -1622,7 +1624,7 @@ func (s *treeShapeListener) parseSingleSender(
 				},
 				Stop: core.Position{
 					Line:   senderSide.GetStop().GetLine(),
-					Column: senderSide.GetStop().GetColumn(),
+					Column: stopColumn(senderSide.GetStop()),
 				},
 				Location: s.loc,
 			},
@@ -1669,7 +1671,7 @@ func (s *treeShapeListener) parseSingleSender(
 			},
 			Stop: core.Position{
 				Line:   rangeExprSender.GetStop().GetLine(),
-				Column: rangeExprSender.GetStop().GetColumn(),
+				Column: stopColumn(rangeExprSender.GetStop()),
 			},
 			Location: s.loc,
 		}
@@ -1697,7 +1699,7 @@ func (s *treeShapeListener) parseSingleSender(
 					},
 					Stop: core.Position{
 						Line:   rangeExprSender.GetStop().GetLine(),
-						Column: rangeExprSender.GetStop().GetColumn(),
+						Column: stopColumn(rangeExprSender.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -1719,7 +1721,7 @@ func (s *treeShapeListener) parseSingleSender(
 					},
 					Stop: core.Position{
 						Line:   rangeExprSender.GetStop().GetLine(),
-						Column: rangeExprSender.GetStop().GetColumn(),
+						Column: stopColumn(rangeExprSender.GetStop()),
 					},
 					Location: s.loc,
 				},
@@ -1769,7 +1771,7 @@ func (s *treeShapeListener) parseSingleSender(
 				},
 				Stop: core.Position{
 					Line:   ternaryExprSender.GetStop().GetLine(),
-					Column: ternaryExprSender.GetStop().GetColumn(),
+					Column: stopColumn(ternaryExprSender.GetStop()),
 				},
 				Location: s.loc,
 			},
@@ -1781,11 +1783,21 @@ func (s *treeShapeListener) parseSingleSender(
 		binaryExpr = s.parseBinaryExpr(binaryExprSender)
 	}
 
+	var unaryExpr *src.Unary
+	if unaryExprSender != nil {
+		parsedUnary, err := s.parseUnaryExpr(unaryExprSender)
+		if err != nil {
+			return src.ConnectionSender{}, err
+		}
+		unaryExpr = parsedUnary
+	}
+
 	parsedSender := src.ConnectionSender{
 		PortAddr:       senderSidePortAddr,
 		Const:          constant,
 		Range:          rangeExpr,
 		StructSelector: senderSelectors,
+		Unary:          unaryExpr,
 		Ternary:        ternaryExpr,
 		Binary:         binaryExpr,
 		Meta: core.Meta{
@@ -1796,7 +1808,7 @@ func (s *treeShapeListener) parseSingleSender(
 			},
 			Stop: core.Position{
 				Line:   senderSide.GetStop().GetLine(),
-				Column: senderSide.GetStop().GetColumn(),
+				Column: stopColumn(senderSide.GetStop()),
 			},
 			Location: s.loc,
 		},
@@ -1826,13 +1838,58 @@ func (s *treeShapeListener) parsePortAddrReceiver(
 			},
 			Stop: core.Position{
 				Line:   singleReceiver.GetStop().GetLine(),
-				Column: singleReceiver.GetStop().GetColumn(),
+				Column: stopColumn(singleReceiver.GetStop()),
 			},
 			Location: s.loc,
 		},
 	}, nil
 }
 
+func (s *treeShapeListener) parseUnaryExpr(
+	ctx generated.IUnaryExprContext,
+) (*src.Unary, *compiler.Error) {
+	meta := core.Meta{
+		Text: ctx.GetText(),
+		Start: core.Position{
+			Line:   ctx.GetStart().GetLine(),
+			Column: ctx.GetStart().GetColumn(),
+		},
+		Stop: core.Position{
+			Line:   ctx.GetStop().GetLine(),
+			Column: stopColumn(ctx.GetStop()),
+		},
+		Location: s.loc,
+	}
+
+	var op src.UnaryOperator
+	switch ctx.UnaryOp().GetText() {
+	case "!":
+		op = src.NotOp
+	case "++":
+		op = src.IncOp
+	case "--":
+		op = src.DecOp
+	case "-":
+		op = src.NegOp
+	default:
+		return nil, &compiler.Error{
+			Message: fmt.Sprintf("Unknown unary operator: %v", ctx.UnaryOp().GetText()),
+			Meta:    &meta,
+		}
+	}
+
+	operand, err := s.parseSingleSender(ctx.SingleSenderSide())
+	if err != nil {
+		return nil, err
+	}
+
+	return &src.Unary{
+		Operand:  operand,
+		Operator: op,
+		Meta:     meta,
+	}, nil
+}
+
 func (s *treeShapeListener) parseBinaryExpr(ctx generated.IBinaryExprContext) *src.Binary {
 	var op src.BinaryOperator
 	switch ctx.BinaryOp().GetText() {
@@ -1904,9 +1961,15 @@ func (s *treeShapeListener) parseBinaryExpr(ctx generated.IBinaryExprContext) *s
 			},
 			Stop: core.Position{
 				Line:   ctx.GetStop().GetLine(),
-				Column: ctx.GetStop().GetColumn(),
+				Column: stopColumn(ctx.GetStop()),
 			},
 			Location: s.loc,
 		},
 	}
 }
+
+// stopColumn returns column right after the last character of the token,
+// so meta's Start and Stop positions span the whole source code fragment.
+func stopColumn(token antlr.Token) int {
+	return token.GetColumn() + len(token.GetText())
+}
@@ -34,5 +34,6 @@ func processParsedManifest(manifest src.ModuleManifest) src.ModuleManifest {
 	return src.ModuleManifest{
 		LanguageVersion: manifest.LanguageVersion,
 		Deps:            deps,
+		Lint:            manifest.Lint,
 	}
 }
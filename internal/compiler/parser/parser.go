@@ -5,6 +5,7 @@ package parser
 import (
 	"fmt"
 	"runtime/debug"
+	"sort"
 
 	"github.com/antlr4-go/antlr/v4"
 
@@ -14,22 +15,42 @@ import (
 	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
 )
 
-type Parser struct{}
+// Parser parses files of different packages concurrently.
+type Parser struct {
+	workers int // non-positive means one per CPU
+}
+
+// WithWorkers returns parser that parses at most n files at a time.
+func (p Parser) WithWorkers(n int) Parser {
+	p.workers = n
+	return p
+}
 
 func (p Parser) ParseModules(
 	rawMods map[core.ModuleRef]compiler.RawModule,
 ) (map[core.ModuleRef]src.Module, *compiler.Error) {
-	parsedMods := make(map[core.ModuleRef]src.Module, len(rawMods))
-
+	var jobs []parseJob
 	for modRef, rawMod := range rawMods {
-		parsedPkgs, err := p.ParsePackages(modRef, rawMod.Packages)
-		if err != nil {
-			return nil, err
+		for pkgName, pkgFiles := range rawMod.Packages {
+			jobs = appendParseJobs(jobs, modRef, pkgName, pkgFiles)
 		}
+	}
 
+	parsed, err := p.parse(jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedMods := make(map[core.ModuleRef]src.Module, len(rawMods))
+	for modRef, rawMod := range rawMods {
+		parsedPkgs := make(map[string]src.Package, len(rawMod.Packages))
+		for pkgName := range rawMod.Packages {
+			parsedPkgs[pkgName] = parsed[modRef][pkgName]
+		}
 		parsedMods[modRef] = src.Module{
 			Manifest: rawMod.Manifest,
 			Packages: parsedPkgs,
+			Native:   rawMod.Native,
 		}
 	}
 
@@ -43,14 +64,19 @@ func (p Parser) ParsePackages(
 	map[string]src.Package,
 	*compiler.Error,
 ) {
-	packages := make(map[string]src.Package, len(rawPkgs))
-
+	var jobs []parseJob
 	for pkgName, pkgFiles := range rawPkgs {
-		parsedFiles, err := p.ParseFiles(modRef, pkgName, pkgFiles)
-		if err != nil {
-			return nil, err
-		}
-		packages[pkgName] = parsedFiles
+		jobs = appendParseJobs(jobs, modRef, pkgName, pkgFiles)
+	}
+
+	parsed, err := p.parse(jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make(map[string]src.Package, len(rawPkgs))
+	for pkgName := range rawPkgs {
+		packages[pkgName] = parsed[modRef][pkgName]
 	}
 
 	return packages, nil
@@ -61,19 +87,74 @@ func (p Parser) ParseFiles(
 	pkgName string,
 	files map[string][]byte,
 ) (map[string]src.File, *compiler.Error) {
+	parsed, err := p.parse(appendParseJobs(nil, modRef, pkgName, files))
+	if err != nil {
+		return nil, err
+	}
+
 	result := make(map[string]src.File, len(files))
+	for fileName, file := range parsed[modRef][pkgName] {
+		result[fileName] = file
+	}
 
-	for fileName, fileBytes := range files {
-		parsedFile, err := p.parseFile(modRef, pkgName, fileName, fileBytes)
-		if err != nil {
-			err.Meta.Location = core.Location{
+	return result, nil
+}
+
+type parseJob struct {
+	loc     core.Location
+	content []byte
+}
+
+func appendParseJobs(jobs []parseJob, modRef core.ModuleRef, pkgName string, files map[string][]byte) []parseJob {
+	for fileName, content := range files {
+		jobs = append(jobs, parseJob{
+			loc: core.Location{
 				ModRef:   modRef,
 				Package:  pkgName,
 				Filename: fileName,
+			},
+			content: content,
+		})
+	}
+	return jobs
+}
+
+// parse parses files concurrently. Files are independent so the only thing that depends on the order is
+// which error is reported, that's why jobs are sorted by location and the first failed one wins.
+func (p Parser) parse(jobs []parseJob) (map[core.ModuleRef]map[string]src.Package, *compiler.Error) {
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].loc.String() < jobs[j].loc.String()
+	})
+
+	files := make([]src.File, len(jobs))
+	errs := make([]*compiler.Error, len(jobs))
+
+	compiler.Parallel(p.workers, len(jobs), func(i int) {
+		job := jobs[i]
+		files[i], errs[i] = p.parseFile(job.loc.ModRef, job.loc.Package, job.loc.Filename, job.content)
+		if errs[i] != nil {
+			if errs[i].Meta == nil {
+				errs[i].Meta = &core.Meta{}
 			}
+			errs[i].Meta.Location = job.loc
+		}
+	})
+
+	for _, err := range errs {
+		if err != nil {
 			return nil, err
 		}
-		result[fileName] = parsedFile
+	}
+
+	result := map[core.ModuleRef]map[string]src.Package{}
+	for i, job := range jobs {
+		if result[job.loc.ModRef] == nil {
+			result[job.loc.ModRef] = map[string]src.Package{}
+		}
+		if result[job.loc.ModRef][job.loc.Package] == nil {
+			result[job.loc.ModRef][job.loc.Package] = src.Package{}
+		}
+		result[job.loc.ModRef][job.loc.Package][job.loc.Filename] = files[i]
 	}
 
 	return result, nil
@@ -296,6 +296,42 @@ func TestParser_ParseFile_Comments(t *testing.T) {
 	require.True(t, err == nil)
 }
 
+func TestParser_ParseFile_DocComments(t *testing.T) {
+	text := []byte(`// not a doc comment
+
+// Foo is documented.
+//   Indentation is kept.
+pub type Foo int // not a part of the doc
+
+// Bar is documented too.
+#extern(bar)
+pub def Bar(data any) (res any)
+// Baz is not separated from Bar by blank line.
+pub def Baz(data any) (res any) {
+    :data -> :res
+}
+
+// detached comment
+
+const qux int = 42
+
+interface IQux() () // trailing comment
+interface IQuux() ()
+`)
+
+	p := New()
+
+	got, err := p.parseFile(location.ModRef, location.Package, location.Filename, text)
+	require.True(t, err == nil)
+
+	require.Equal(t, "Foo is documented.\n  Indentation is kept.", got.Entities["Foo"].Doc)
+	require.Equal(t, "Bar is documented too.", got.Entities["Bar"].Doc)
+	require.Equal(t, "Baz is not separated from Bar by blank line.", got.Entities["Baz"].Doc)
+	require.Empty(t, got.Entities["qux"].Doc)
+	require.Empty(t, got.Entities["IQux"].Doc)
+	require.Empty(t, got.Entities["IQuux"].Doc)
+}
+
 func TestParser_ParseFile_Directives(t *testing.T) {
 	text := []byte(`
 		#extern(d1)
@@ -709,6 +745,73 @@ func TestParser_ParseFile_Binary(t *testing.T) {
 	}
 }
 
+func TestParser_ParseFile_Unary(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		operator string
+	}{
+		{
+			name: "not",
+			text: `
+				def C1() () {
+					!a -> receiver
+				}
+			`,
+			operator: "!",
+		},
+		{
+			name: "increment",
+			text: `
+				def C1() () {
+					++a -> receiver
+				}
+			`,
+			operator: "++",
+		},
+		{
+			name: "decrement",
+			text: `
+				def C1() () {
+					--a -> receiver
+				}
+			`,
+			operator: "--",
+		},
+		{
+			name: "negation",
+			text: `
+				def C1() () {
+					-a -> receiver
+				}
+			`,
+			operator: "-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+
+			got, err := p.parseFile(location.ModRef, location.Package, location.Filename, []byte(tt.text))
+			require.Nil(t, err)
+
+			net := got.Entities["C1"].Component.Net
+			require.Equal(t, 1, len(net))
+
+			conn := net[0].Normal
+			require.Equal(t, 1, len(conn.Senders))
+
+			unary := conn.Senders[0].Unary
+			require.NotNil(t, unary)
+
+			require.Equal(t, "a", unary.Operand.PortAddr.Node)
+			require.Equal(t, src.UnaryOperator(tt.operator), unary.Operator)
+			require.Equal(t, "receiver", conn.Receivers[0].PortAddr.Node)
+		})
+	}
+}
+
 func TestParser_ParseFile_ComplexBinaryAndTernary(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -0,0 +1,652 @@
+// Package printer turns source code abstractions back into Neva source code.
+// Output is in canonical form, the same as produced by formatter.
+// Source code abstractions only contain doc comments of entities, other comments and blank lines are lost.
+package printer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nevalang/neva/internal/compiler/formatter"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	ts "github.com/nevalang/neva/internal/compiler/sourcecode/typesystem"
+)
+
+// Print returns source code of the file.
+// Entities, nodes and ports are printed in the order they appear in the source code
+// (according to their meta), entities without meta are ordered by kind and name.
+func Print(file src.File) []byte {
+	p := &printer{}
+	p.file(file)
+	return []byte(p.b.String())
+}
+
+// TypeExpr returns source code of the type expression.
+func TypeExpr(expr ts.Expr) string {
+	p := &printer{}
+	p.typeExpr(expr)
+	return p.b.String()
+}
+
+type printer struct {
+	b       strings.Builder
+	indent  int
+	pending bool // indentation must be written before next text
+}
+
+func (p *printer) write(ss ...string) {
+	for _, s := range ss {
+		if s == "" {
+			continue
+		}
+		if p.pending {
+			p.b.WriteString(strings.Repeat(formatter.Indent, p.indent))
+			p.pending = false
+		}
+		p.b.WriteString(s)
+	}
+}
+
+func (p *printer) newline() {
+	p.b.WriteByte('\n')
+	p.pending = true
+}
+
+func (p *printer) file(file src.File) {
+	hasImports := len(file.Imports) > 0
+	if hasImports {
+		p.imports(file.Imports)
+	}
+
+	for i, name := range sortedEntities(file.Entities) {
+		if i > 0 || hasImports {
+			p.newline()
+		}
+		p.entity(name, file.Entities[name])
+	}
+}
+
+func (p *printer) imports(imports map[string]src.Import) {
+	aliases := make([]string, 0, len(imports))
+	for alias := range imports {
+		aliases = append(aliases, alias)
+	}
+
+	sort.Slice(aliases, func(i, j int) bool {
+		a, b := imports[aliases[i]], imports[aliases[j]]
+		if aStd, bStd := a.Module == "std", b.Module == "std"; aStd != bStd {
+			return aStd
+		}
+		if importPath(a) != importPath(b) {
+			return importPath(a) < importPath(b)
+		}
+		return aliases[i] < aliases[j]
+	})
+
+	if len(aliases) == 1 {
+		p.write("import { ")
+		p.importDef(aliases[0], imports[aliases[0]])
+		p.write(" }")
+		p.newline()
+		return
+	}
+
+	p.write("import {")
+	p.newline()
+	p.indent++
+	for _, alias := range aliases {
+		p.importDef(alias, imports[alias])
+		p.newline()
+	}
+	p.indent--
+	p.write("}")
+	p.newline()
+}
+
+func (p *printer) importDef(alias string, imp src.Import) {
+	parts := strings.Split(imp.Package, "/")
+	if alias != parts[len(parts)-1] {
+		p.write(alias, " ")
+	}
+	p.write(importPath(imp))
+}
+
+func importPath(imp src.Import) string {
+	if imp.Module == "std" {
+		return imp.Package
+	}
+	return imp.Module + ":" + imp.Package
+}
+
+// kindOrder is used to sort entities without meta.
+var kindOrder = map[src.EntityKind]int{
+	src.TypeEntity:      0,
+	src.ConstEntity:     1,
+	src.InterfaceEntity: 2,
+	src.ComponentEntity: 3,
+}
+
+func sortedEntities(entities map[string]src.Entity) []string {
+	names := make([]string, 0, len(entities))
+	for name := range entities {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		a, b := entities[names[i]], entities[names[j]]
+		if c := comparePositions(a.Meta().Start, b.Meta().Start); c != 0 {
+			return c < 0
+		}
+		if kindOrder[a.Kind] != kindOrder[b.Kind] {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+func (p *printer) entity(name string, entity src.Entity) {
+	if entity.Doc != "" {
+		for _, line := range strings.Split(entity.Doc, "\n") {
+			p.write("//")
+			if line != "" {
+				p.write(" ", line)
+			}
+			p.newline()
+		}
+	}
+
+	if entity.Kind == src.ComponentEntity {
+		p.directives(entity.Component.Directives)
+	}
+
+	if entity.IsPublic {
+		p.write("pub ")
+	}
+
+	switch entity.Kind {
+	case src.TypeEntity:
+		p.write("type ", name)
+		p.typeParams(entity.Type.Params)
+		if entity.Type.BodyExpr != nil {
+			p.write(" ")
+			p.typeExpr(*entity.Type.BodyExpr)
+		}
+	case src.ConstEntity:
+		p.write("const ", name, " ")
+		p.typeExpr(entity.Const.TypeExpr)
+		p.write(" = ")
+		p.constValue(entity.Const.Value)
+	case src.InterfaceEntity:
+		p.write("interface ", name)
+		p.iface(entity.Interface)
+	case src.ComponentEntity:
+		p.write("def ", name)
+		p.iface(entity.Component.Interface)
+		if entity.Component.Nodes != nil || entity.Component.Net != nil {
+			p.write(" ")
+			p.componentBody(entity.Component)
+		}
+	}
+
+	p.newline()
+}
+
+func (p *printer) directives(directives map[src.Directive][]string) {
+	names := make([]string, 0, len(directives))
+	for directive := range directives {
+		names = append(names, string(directive))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p.write("#", name)
+		if args := directives[src.Directive(name)]; len(args) > 0 {
+			p.write("(", strings.Join(args, ", "), ")")
+		}
+		p.newline()
+	}
+}
+
+func (p *printer) typeParams(params []ts.Param) {
+	if len(params) == 0 {
+		return
+	}
+	p.write("<")
+	for i, param := range params {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.write(param.Name, " ")
+		p.typeExpr(param.Constr)
+	}
+	p.write(">")
+}
+
+func (p *printer) typeExpr(expr ts.Expr) {
+	switch {
+	case expr.Inst != nil:
+		p.write(expr.Inst.Ref.String())
+		if len(expr.Inst.Args) > 0 {
+			p.typeArgs(expr.Inst.Args)
+		}
+	case expr.Lit == nil:
+		p.write("any")
+	case expr.Lit.Enum != nil:
+		if len(expr.Lit.Enum) == 0 {
+			p.write("enum {}")
+			return
+		}
+		p.write("enum { ", strings.Join(expr.Lit.Enum, ", "), " }")
+	case expr.Lit.Union != nil:
+		for i, el := range expr.Lit.Union {
+			if i > 0 {
+				p.write(" | ")
+			}
+			p.typeExpr(el)
+		}
+	case expr.Lit.Struct != nil:
+		p.structType(expr.Lit.Struct)
+	}
+}
+
+func (p *printer) typeArgs(args []ts.Expr) {
+	p.write("<")
+	for i, arg := range args {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.typeExpr(arg)
+	}
+	p.write(">")
+}
+
+// structType prints struct type with one field per line because grammar doesn't allow separators.
+func (p *printer) structType(fields map[string]ts.Expr) {
+	if len(fields) == 0 {
+		p.write("struct {}")
+		return
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if c := comparePositions(fields[names[i]].Meta.Start, fields[names[j]].Meta.Start); c != 0 {
+			return c < 0
+		}
+		return names[i] < names[j]
+	})
+
+	p.write("struct {")
+	p.newline()
+	p.indent++
+	for _, name := range names {
+		p.write(name, " ")
+		p.typeExpr(fields[name])
+		p.newline()
+	}
+	p.indent--
+	p.write("}")
+}
+
+func (p *printer) iface(iface src.Interface) {
+	p.typeParams(iface.TypeParams.Params)
+	p.ports(iface.IO.In)
+	p.write(" ")
+	p.ports(iface.IO.Out)
+}
+
+func (p *printer) ports(ports map[string]src.Port) {
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if c := comparePositions(ports[names[i]].Meta.Start, ports[names[j]].Meta.Start); c != 0 {
+			return c < 0
+		}
+		return names[i] < names[j]
+	})
+
+	p.write("(")
+	for i, name := range names {
+		if i > 0 {
+			p.write(", ")
+		}
+		port := ports[name]
+		switch {
+		case port.IsArray:
+			p.write("[", name, "] ")
+		case name != "":
+			p.write(name, " ")
+		}
+		p.typeExpr(port.TypeExpr)
+	}
+	p.write(")")
+}
+
+func (p *printer) componentBody(comp src.Component) {
+	p.write("{")
+	if len(comp.Nodes) == 0 && len(comp.Net) == 0 {
+		p.write("}")
+		return
+	}
+
+	p.newline()
+	p.indent++
+
+	if len(comp.Nodes) > 0 {
+		for _, name := range sortedNodes(comp.Nodes) {
+			node := comp.Nodes[name]
+			p.directives(node.Directives)
+			if name != defaultNodeName(node) {
+				p.write(name, " ")
+			}
+			p.node(node)
+			p.newline()
+		}
+		p.write("---")
+		p.newline()
+	}
+
+	for _, conn := range comp.Net {
+		p.connection(conn)
+		p.newline()
+	}
+
+	p.indent--
+	p.write("}")
+}
+
+func sortedNodes(nodes map[string]src.Node) []string {
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if c := comparePositions(nodes[names[i]].Meta.Start, nodes[names[j]].Meta.Start); c != 0 {
+			return c < 0
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// defaultNodeName returns name that parser gives to the root level node declared without name.
+func defaultNodeName(node src.Node) string {
+	if node.EntityRef.Name == "" {
+		return ""
+	}
+	return strings.ToLower(node.EntityRef.Name[:1]) + node.EntityRef.Name[1:]
+}
+
+func (p *printer) node(node src.Node) {
+	p.write(node.EntityRef.String())
+
+	if len(node.TypeArgs) > 0 {
+		p.typeArgs(node.TypeArgs)
+	}
+
+	if len(node.DIArgs) > 0 {
+		p.diArgs(node.DIArgs)
+	}
+
+	if node.ErrGuard {
+		p.write("?")
+	}
+}
+
+// diArgs prints dependency injection arguments on a single line
+// unless some of them have directives that require their own lines.
+func (p *printer) diArgs(args map[string]src.Node) {
+	multiline := false
+	for _, arg := range args {
+		multiline = multiline || len(arg.Directives) > 0
+	}
+
+	p.write("{")
+	if multiline {
+		p.newline()
+		p.indent++
+	}
+
+	for i, name := range sortedNodes(args) {
+		if i > 0 && !multiline {
+			p.write(", ")
+		}
+		arg := args[name]
+		p.directives(arg.Directives)
+		if name != "" {
+			p.write(name, " ")
+		}
+		p.node(arg)
+		if multiline {
+			p.newline()
+		}
+	}
+
+	if multiline {
+		p.indent--
+	}
+	p.write("}")
+}
+
+func (p *printer) connection(conn src.Connection) {
+	if conn.ArrayBypass != nil {
+		p.portAddr(conn.ArrayBypass.SenderOutport)
+		p.write(" => ")
+		p.portAddr(conn.ArrayBypass.ReceiverInport)
+		return
+	}
+	if conn.Normal != nil {
+		p.normalConnection(*conn.Normal)
+	}
+}
+
+func (p *printer) normalConnection(conn src.NormalConnection) {
+	if len(conn.Senders) == 1 {
+		p.sender(conn.Senders[0])
+	} else {
+		p.write("[")
+		for i, sender := range conn.Senders {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.sender(sender)
+		}
+		p.write("]")
+	}
+
+	p.write(" -> ")
+	p.receivers(conn.Receivers)
+}
+
+func (p *printer) receivers(receivers []src.ConnectionReceiver) {
+	if len(receivers) == 1 {
+		p.receiver(receivers[0])
+		return
+	}
+
+	p.write("[")
+	for i, receiver := range receivers {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.receiver(receiver)
+	}
+	p.write("]")
+}
+
+func (p *printer) receiver(receiver src.ConnectionReceiver) {
+	switch {
+	case receiver.PortAddr != nil:
+		p.portAddr(*receiver.PortAddr)
+	case receiver.ChainedConnection != nil:
+		p.connection(*receiver.ChainedConnection)
+	case receiver.DeferredConnection != nil:
+		p.write("{ ")
+		p.connection(*receiver.DeferredConnection)
+		p.write(" }")
+	case receiver.Switch != nil:
+		p.switchStmt(*receiver.Switch)
+	}
+}
+
+func (p *printer) switchStmt(switchStmt src.Switch) {
+	p.write("switch {")
+	p.newline()
+	p.indent++
+
+	for _, c := range switchStmt.Cases {
+		p.normalConnection(c)
+		p.newline()
+	}
+
+	if switchStmt.Default != nil {
+		p.write("_ -> ")
+		p.receivers(switchStmt.Default)
+		p.newline()
+	}
+
+	p.indent--
+	p.write("}")
+}
+
+func (p *printer) sender(sender src.ConnectionSender) {
+	switch {
+	case sender.PortAddr != nil:
+		p.portAddr(*sender.PortAddr)
+	case sender.Const != nil:
+		if sender.Const.Value.Ref != nil {
+			p.write("$", sender.Const.Value.Ref.String())
+		} else {
+			p.msg(sender.Const.Value.Message)
+		}
+	case sender.Range != nil:
+		p.write(fmt.Sprintf("%d..%d", sender.Range.From, sender.Range.To))
+	case sender.Unary != nil:
+		p.write(string(sender.Unary.Operator))
+		p.sender(sender.Unary.Operand)
+	case sender.Binary != nil:
+		p.write("(")
+		p.sender(sender.Binary.Left)
+		p.write(" ", string(sender.Binary.Operator), " ")
+		p.sender(sender.Binary.Right)
+		p.write(")")
+	case sender.Ternary != nil:
+		p.write("(")
+		p.sender(sender.Ternary.Condition)
+		p.write(" ? ")
+		p.sender(sender.Ternary.Left)
+		p.write(" : ")
+		p.sender(sender.Ternary.Right)
+		p.write(")")
+	case len(sender.StructSelector) > 0:
+		p.write(".", strings.Join(sender.StructSelector, "."))
+	}
+}
+
+// portAddr prints port address, ports of the component itself are printed without node name.
+func (p *printer) portAddr(addr src.PortAddr) {
+	if addr.Port == "" || addr.Node != "in" && addr.Node != "out" {
+		p.write(addr.Node)
+	}
+	if addr.Port != "" {
+		p.write(":", addr.Port)
+	}
+	if addr.Idx != nil {
+		p.write("[", strconv.Itoa(int(*addr.Idx)), "]")
+	}
+}
+
+func (p *printer) constValue(value src.ConstValue) {
+	if value.Ref != nil {
+		p.write(value.Ref.String())
+		return
+	}
+	p.msg(value.Message)
+}
+
+func (p *printer) msg(msg *src.MsgLiteral) {
+	switch {
+	case msg == nil:
+		p.write("[]")
+	case msg.Bool != nil:
+		p.write(strconv.FormatBool(*msg.Bool))
+	case msg.Int != nil:
+		p.write(strconv.Itoa(*msg.Int))
+	case msg.Float != nil:
+		s := strconv.FormatFloat(*msg.Float, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0" // otherwise it's parsed as int
+		}
+		p.write(s)
+	case msg.Str != nil:
+		p.write("'", strings.ReplaceAll(*msg.Str, "\n", `\n`), "'")
+	case msg.Enum != nil:
+		p.write(msg.Enum.EnumRef.String(), "::", msg.Enum.MemberName)
+	case msg.DictOrStruct != nil:
+		p.structLit(msg.DictOrStruct)
+	default:
+		// parser doesn't keep anything for empty list
+		p.write("[")
+		for i, item := range msg.List {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.constValue(item)
+		}
+		p.write("]")
+	}
+}
+
+func (p *printer) structLit(fields map[string]src.ConstValue) {
+	if len(fields) == 0 {
+		p.write("{}")
+		return
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if c := comparePositions(valuePosition(fields[names[i]]), valuePosition(fields[names[j]])); c != 0 {
+			return c < 0
+		}
+		return names[i] < names[j]
+	})
+
+	p.write("{ ")
+	for i, name := range names {
+		if i > 0 {
+			p.write(", ")
+		}
+		p.write(name, ": ")
+		p.constValue(fields[name])
+	}
+	p.write(" }")
+}
+
+func valuePosition(value src.ConstValue) core.Position {
+	if value.Ref != nil {
+		return value.Ref.Meta.Start
+	}
+	if value.Message != nil {
+		return value.Message.Meta.Start
+	}
+	return core.Position{}
+}
+
+func comparePositions(a, b core.Position) int {
+	if a.Line != b.Line {
+		return a.Line - b.Line
+	}
+	return a.Column - b.Column
+}
@@ -0,0 +1,202 @@
+package printer
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/formatter"
+	"github.com/nevalang/neva/internal/compiler/parser"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	ts "github.com/nevalang/neva/internal/compiler/sourcecode/typesystem"
+)
+
+func TestPrint(t *testing.T) {
+	intType := ts.Expr{Inst: &ts.InstExpr{Ref: core.EntityRef{Name: "int"}}}
+	anyType := ts.Expr{Inst: &ts.InstExpr{Ref: core.EntityRef{Name: "any"}}}
+
+	file := src.File{
+		Imports: map[string]src.Import{
+			"fmt":   {Module: "std", Package: "fmt"},
+			"utils": {Module: "@", Package: "pkg/utils"},
+		},
+		Entities: map[string]src.Entity{
+			"Main": {
+				Kind: src.ComponentEntity,
+				Component: src.Component{
+					Interface: src.Interface{
+						IO: src.IO{
+							In:  map[string]src.Port{"start": {TypeExpr: anyType}},
+							Out: map[string]src.Port{"stop": {TypeExpr: anyType}},
+						},
+					},
+					Nodes: map[string]src.Node{
+						"println": {EntityRef: core.EntityRef{Pkg: "fmt", Name: "Println"}},
+						"double":  {EntityRef: core.EntityRef{Pkg: "utils", Name: "Mul"}},
+					},
+					Net: []src.Connection{
+						{
+							Normal: &src.NormalConnection{
+								Senders: []src.ConnectionSender{{PortAddr: &src.PortAddr{Node: "in", Port: "start"}}},
+								Receivers: []src.ConnectionReceiver{{
+									ChainedConnection: &src.Connection{
+										Normal: &src.NormalConnection{
+											Senders: []src.ConnectionSender{{
+												Const: &src.Const{Value: src.ConstValue{Ref: &core.EntityRef{Name: "two"}}},
+											}},
+											Receivers: []src.ConnectionReceiver{{PortAddr: &src.PortAddr{Node: "double"}}},
+										},
+									},
+								}},
+							},
+						},
+						{
+							Normal: &src.NormalConnection{
+								Senders: []src.ConnectionSender{{PortAddr: &src.PortAddr{Node: "double", Port: "res"}}},
+								Receivers: []src.ConnectionReceiver{{
+									Switch: &src.Switch{
+										Cases: []src.NormalConnection{{
+											Senders: []src.ConnectionSender{{
+												Const: &src.Const{Value: src.ConstValue{Message: &src.MsgLiteral{Int: compiler.Pointer(4)}}},
+											}},
+											Receivers: []src.ConnectionReceiver{{PortAddr: &src.PortAddr{Node: "println"}}},
+										}},
+										Default: []src.ConnectionReceiver{{PortAddr: &src.PortAddr{Node: "out", Port: "stop"}}},
+									},
+								}},
+							},
+						},
+						{
+							Normal: &src.NormalConnection{
+								Senders:   []src.ConnectionSender{{PortAddr: &src.PortAddr{Node: "println", Port: "res"}}},
+								Receivers: []src.ConnectionReceiver{{PortAddr: &src.PortAddr{Node: "out", Port: "stop"}}},
+							},
+						},
+					},
+				},
+			},
+			"two": {
+				Kind: src.ConstEntity,
+				Const: src.Const{
+					TypeExpr: intType,
+					Value:    src.ConstValue{Message: &src.MsgLiteral{Int: compiler.Pointer(2)}},
+				},
+			},
+			"Point": {
+				IsPublic: true,
+				Doc:      "Point is a point on a plane.\n\nCoordinates are integers.",
+				Kind:     src.TypeEntity,
+				Type: ts.Def{
+					BodyExpr: &ts.Expr{
+						Lit: &ts.LitExpr{Struct: map[string]ts.Expr{"x": intType, "y": intType}},
+					},
+				},
+			},
+		},
+	}
+
+	expected := `import {
+    fmt
+    @:pkg/utils
+}
+
+// Point is a point on a plane.
+//
+// Coordinates are integers.
+pub type Point struct {
+    x int
+    y int
+}
+
+const two int = 2
+
+def Main(start any) (stop any) {
+    double utils.Mul
+    fmt.Println
+    ---
+    :start -> $two -> double
+    double:res -> switch {
+        4 -> println
+        _ -> :stop
+    }
+    println:res -> :stop
+}
+`
+
+	require.Equal(t, expected, string(Print(file)))
+}
+
+// TestPrint_RoundTrip checks that printed source code of every file in examples, e2e tests and stdlib
+// is formatted and parses back to the same file.
+func TestPrint_RoundTrip(t *testing.T) {
+	for _, root := range []string{"../../../examples", "../../../e2e", "../../../std"} {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Ext(path) != ".neva" {
+				return err
+			}
+
+			source, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			parsed, compilerErr := parse(source)
+			if compilerErr != nil {
+				return nil // some e2e tests are about syntax errors
+			}
+
+			printed := Print(parsed)
+
+			reparsed, compilerErr := parse(printed)
+			require.Nil(t, compilerErr, "%v\n%s", path, printed)
+			require.Equal(t, withoutMeta(t, parsed), withoutMeta(t, reparsed), path)
+
+			formatted, compilerErr := formatter.Format(printed)
+			require.Nil(t, compilerErr, path)
+			require.Equal(t, string(formatted), string(printed), path)
+
+			return nil
+		})
+		require.NoError(t, err)
+	}
+}
+
+func parse(source []byte) (src.File, *compiler.Error) {
+	files, err := parser.Parser{}.ParseFiles(core.ModuleRef{Path: "@"}, "pkg", map[string][]byte{"file": source})
+	if err != nil {
+		return src.File{}, err
+	}
+	return files["file"], nil
+}
+
+// withoutMeta returns json representation of the file without meta fields.
+func withoutMeta(t *testing.T, file src.File) any {
+	bb, err := json.Marshal(file)
+	require.NoError(t, err)
+
+	var v any
+	require.NoError(t, json.Unmarshal(bb, &v))
+
+	var strip func(v any)
+	strip = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			delete(v, "meta")
+			delete(v, "Meta")
+			for _, child := range v {
+				strip(child)
+			}
+		case []any:
+			for _, child := range v {
+				strip(child)
+			}
+		}
+	}
+	strip(v)
+
+	return v
+}
@@ -0,0 +1,50 @@
+package sourcecode
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+)
+
+// jsonBuild is Build with modules represented as a list sorted by references
+// because map keys in JSON must be strings.
+type jsonBuild struct {
+	EntryModRef core.ModuleRef `json:"entryModRef,omitempty"`
+	Modules     []jsonModule   `json:"modules,omitempty"`
+}
+
+type jsonModule struct {
+	Ref core.ModuleRef `json:"ref"`
+	Module
+}
+
+func (b Build) MarshalJSON() ([]byte, error) {
+	modules := make([]jsonModule, 0, len(b.Modules))
+	for ref, mod := range b.Modules {
+		modules = append(modules, jsonModule{Ref: ref, Module: mod})
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Ref.String() < modules[j].Ref.String()
+	})
+
+	return json.Marshal(jsonBuild{
+		EntryModRef: b.EntryModRef,
+		Modules:     modules,
+	})
+}
+
+func (b *Build) UnmarshalJSON(data []byte) error {
+	var build jsonBuild
+	if err := json.Unmarshal(data, &build); err != nil {
+		return err
+	}
+
+	b.EntryModRef = build.EntryModRef
+	b.Modules = make(map[core.ModuleRef]Module, len(build.Modules))
+	for _, mod := range build.Modules {
+		b.Modules[mod.Ref] = mod.Module
+	}
+
+	return nil
+}
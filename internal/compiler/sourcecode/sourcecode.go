@@ -4,6 +4,7 @@ package sourcecode
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
 	ts "github.com/nevalang/neva/internal/compiler/sourcecode/typesystem"
@@ -20,6 +21,9 @@ type Build struct {
 type Module struct {
 	Manifest ModuleManifest     `json:"manifest,omitempty"`
 	Packages map[string]Package `json:"packages,omitempty"`
+	// Native is Go source code of module's externs, by file name.
+	// Externs declared by its registry are resolved in module's own namespace.
+	Native map[string][]byte `json:"native,omitempty"`
 }
 
 func (mod Module) Entity(entityRef core.EntityRef) (entity Entity, filename string, err error) {
@@ -39,6 +43,8 @@ func (mod Module) Entity(entityRef core.EntityRef) (entity Entity, filename stri
 type ModuleManifest struct {
 	LanguageVersion string                    `json:"neva,omitempty" yaml:"neva,omitempty"`
 	Deps            map[string]core.ModuleRef `json:"deps,omitempty" yaml:"deps,omitempty"`
+	// Lint enables or disables lint rules by their names, rules are enabled by default.
+	Lint map[string]bool `json:"lint,omitempty" yaml:"lint,omitempty"`
 }
 
 type Package map[string]File
@@ -77,6 +83,33 @@ func (pkg Package) Entities() func(func(EntitiesResult) bool) {
 	}
 }
 
+// Tests iterates over test components of the package.
+// Test component is a component declared in "*_test.neva" file with name starting with "Test".
+func (pkg Package) Tests() func(func(EntitiesResult) bool) {
+	return pkg.testFileComponents("Test")
+}
+
+// Benchmarks iterates over benchmark components of the package.
+// Benchmark component is a component declared in "*_test.neva" file with name starting with "Bench".
+func (pkg Package) Benchmarks() func(func(EntitiesResult) bool) {
+	return pkg.testFileComponents("Bench")
+}
+
+func (pkg Package) testFileComponents(prefix string) func(func(EntitiesResult) bool) {
+	return func(yield func(EntitiesResult) bool) {
+		for result := range pkg.Entities() {
+			if result.Entity.Kind != ComponentEntity ||
+				!strings.HasSuffix(result.FileName, "_test") ||
+				!strings.HasPrefix(result.EntityName, prefix) {
+				continue
+			}
+			if !yield(result) {
+				return
+			}
+		}
+	}
+}
+
 type File struct {
 	Imports  map[string]Import `json:"imports,omitempty"`
 	Entities map[string]Entity `json:"entities,omitempty"`
@@ -90,6 +123,7 @@ type Import struct {
 
 type Entity struct {
 	IsPublic  bool       `json:"exported,omitempty"`
+	Doc       string     `json:"doc,omitempty"` // text of comments directly above the entity
 	Kind      EntityKind `json:"kind,omitempty"`
 	Const     Const      `json:"const,omitempty"`
 	Type      ts.Def     `json:"type,omitempty"`
@@ -275,12 +309,36 @@ type Connection struct {
 	Meta        core.Meta              `json:"meta,omitempty"`
 }
 
+// WalkPorts calls visit for every port address of the connection, including nested ones.
+// Senders are visited as outports and receivers as inports.
+// Chained receivers are visited as both inports and outports.
+func (c Connection) WalkPorts(visit func(addr PortAddr, out bool)) {
+	if c.ArrayBypass != nil {
+		visit(c.ArrayBypass.SenderOutport, true)
+		visit(c.ArrayBypass.ReceiverInport, false)
+		return
+	}
+	if c.Normal != nil {
+		c.Normal.WalkPorts(visit)
+	}
+}
+
 type NormalConnection struct {
 	Senders   []ConnectionSender   `json:"sender,omitempty"`
 	Receivers []ConnectionReceiver `json:"receiver,omitempty"`
 	Meta      core.Meta            `json:"meta,omitempty"`
 }
 
+// WalkPorts calls visit for every port address of the connection, including nested ones.
+func (c NormalConnection) WalkPorts(visit func(addr PortAddr, out bool)) {
+	for _, sender := range c.Senders {
+		sender.walkPorts(visit)
+	}
+	for _, receiver := range c.Receivers {
+		receiver.walkPorts(visit)
+	}
+}
+
 type ArrayBypassConnection struct {
 	SenderOutport  PortAddr `json:"senderOutport,omitempty"`
 	ReceiverInport PortAddr `json:"receiverOutport,omitempty"`
@@ -294,6 +352,31 @@ type ConnectionReceiver struct { // TODO rename to Receiver
 	Meta               core.Meta   `json:"meta,omitempty"`
 }
 
+func (r ConnectionReceiver) walkPorts(visit func(addr PortAddr, out bool)) {
+	switch {
+	case r.PortAddr != nil:
+		visit(*r.PortAddr, false)
+	case r.DeferredConnection != nil:
+		r.DeferredConnection.WalkPorts(visit)
+	case r.ChainedConnection != nil:
+		if chain := r.ChainedConnection.Normal; chain != nil {
+			for _, sender := range chain.Senders {
+				if sender.PortAddr != nil {
+					visit(*sender.PortAddr, false)
+				}
+			}
+		}
+		r.ChainedConnection.WalkPorts(visit)
+	case r.Switch != nil:
+		for _, c := range r.Switch.Cases {
+			c.WalkPorts(visit)
+		}
+		for _, d := range r.Switch.Default {
+			d.walkPorts(visit)
+		}
+	}
+}
+
 type Switch struct {
 	Cases   []NormalConnection   `json:"case,omitempty"`
 	Default []ConnectionReceiver `json:"default,omitempty"`
@@ -327,6 +410,22 @@ type ConnectionSender struct {
 	Meta           core.Meta `json:"meta,omitempty"`
 }
 
+func (s ConnectionSender) walkPorts(visit func(addr PortAddr, out bool)) {
+	switch {
+	case s.PortAddr != nil:
+		visit(*s.PortAddr, true)
+	case s.Unary != nil:
+		s.Unary.Operand.walkPorts(visit)
+	case s.Binary != nil:
+		s.Binary.Left.walkPorts(visit)
+		s.Binary.Right.walkPorts(visit)
+	case s.Ternary != nil:
+		s.Ternary.Condition.walkPorts(visit)
+		s.Ternary.Left.walkPorts(visit)
+		s.Ternary.Right.walkPorts(visit)
+	}
+}
+
 type Binary struct {
 	Left     ConnectionSender `json:"left,omitempty"`
 	Right    ConnectionSender `json:"right,omitempty"`
@@ -356,6 +455,9 @@ type Unary struct {
 	Operand  ConnectionSender `json:"expr,omitempty"`
 	Operator UnaryOperator    `json:"operator,omitempty"`
 	Meta     core.Meta        `json:"meta,omitempty"`
+	// This field is result of semantic analysis and is unknown at parsing time.
+	// It's used by desugarer to correctly handle overloaded components.
+	AnalyzedType ts.Expr `json:"type,omitempty"`
 }
 
 func (u Unary) String() string {
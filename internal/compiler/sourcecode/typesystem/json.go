@@ -0,0 +1,26 @@
+package typesystem
+
+import "encoding/json"
+
+// jsonLitExpr is LitExpr with pointers to fields, so only nil fields are omitted.
+type jsonLitExpr struct {
+	Struct *map[string]Expr `json:"struct,omitempty"`
+	Enum   *[]string        `json:"enum,omitempty"`
+	Union  *[]Expr          `json:"union,omitempty"`
+}
+
+// MarshalJSON omits nil fields but keeps empty ones
+// because empty struct literal is different from the one that is not a struct.
+func (lit LitExpr) MarshalJSON() ([]byte, error) {
+	var result jsonLitExpr
+	if lit.Struct != nil {
+		result.Struct = &lit.Struct
+	}
+	if lit.Enum != nil {
+		result.Enum = &lit.Enum
+	}
+	if lit.Union != nil {
+		result.Union = &lit.Union
+	}
+	return json.Marshal(result)
+}
@@ -1,6 +1,7 @@
 package typesystem_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -50,6 +51,39 @@ func TestLiteralExpr_Empty(t *testing.T) {
 	}
 }
 
+func TestLiteralExpr_JSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		lit  ts.LitExpr
+		json string
+	}{
+		{
+			name: "nil fields are omitted",
+			lit:  ts.LitExpr{Enum: []string{"a"}},
+			json: `{"enum":["a"]}`,
+		},
+		{
+			name: "empty struct is kept",
+			lit:  ts.LitExpr{Struct: map[string]ts.Expr{}},
+			json: `{"struct":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.lit)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.json, string(data))
+
+			var decoded ts.LitExpr
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			require.Equal(t, tt.lit, decoded)
+		})
+	}
+}
+
 func TestLiteralExpr_Type(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,159 @@
+package visualizer
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// EncodeDOT writes graph in Graphviz DOT format.
+// Drilled down nodes are rendered as clusters with their own networks inside.
+func EncodeDOT(w io.Writer, g *Graph) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "digraph %s {\n", strconv.Quote(g.Component))
+	sb.WriteString("\trankdir=LR;\n")
+	sb.WriteString("\tnode [shape=plaintext fontname=\"Helvetica\"];\n")
+	sb.WriteString("\tedge [fontname=\"Helvetica\" fontsize=10];\n")
+
+	writeDOTVertices(&sb, g, "\t")
+
+	for _, edge := range collectEdges(g) {
+		writeDOTEdge(&sb, edge)
+	}
+
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeDOTVertices(sb *strings.Builder, g *Graph, indent string) {
+	writeDOTVertex(sb, g.In, indent)
+	for _, vertex := range g.Vertices {
+		if vertex.Sub == nil {
+			writeDOTVertex(sb, vertex, indent)
+			continue
+		}
+		fmt.Fprintf(sb, "%ssubgraph %s {\n", indent, strconv.Quote("cluster_"+vertex.ID))
+		fmt.Fprintf(sb, "%s\tlabel=%s;\n", indent, strconv.Quote(vertex.Label))
+		writeDOTVertices(sb, vertex.Sub, indent+"\t")
+		fmt.Fprintf(sb, "%s}\n", indent)
+	}
+	writeDOTVertex(sb, g.Out, indent)
+}
+
+func writeDOTVertex(sb *strings.Builder, vertex *Vertex, indent string) {
+	id := strconv.Quote(vertex.ID)
+
+	switch vertex.Kind {
+	case ConstVertex, ExprVertex:
+		fmt.Fprintf(sb, "%s%s [shape=box style=rounded label=%s];\n", indent, id, strconv.Quote(vertex.Label))
+		return
+	case SwitchVertex:
+		fmt.Fprintf(sb, "%s%s [shape=diamond label=%s];\n", indent, id, strconv.Quote(vertex.Label))
+		return
+	case DeferVertex:
+		fmt.Fprintf(sb, "%s%s [shape=circle label=%s];\n", indent, id, strconv.Quote(vertex.Label))
+		return
+	}
+
+	var table strings.Builder
+	table.WriteString(`<table border="0" cellborder="1" cellspacing="0">`)
+	fmt.Fprintf(&table, `<tr><td colspan="2"><b>%s</b></td></tr>`, html.EscapeString(vertex.Label))
+
+	rows := max(len(vertex.In), len(vertex.Out))
+	for i := 0; i < rows; i++ {
+		table.WriteString("<tr>")
+		writeDOTPortCell(&table, vertex.In, i, "in_")
+		writeDOTPortCell(&table, vertex.Out, i, "out_")
+		table.WriteString("</tr>")
+	}
+
+	table.WriteString("</table>")
+
+	fmt.Fprintf(sb, "%s%s [label=<%s>];\n", indent, id, table.String())
+}
+
+func writeDOTPortCell(sb *strings.Builder, ports []Port, i int, anchorPrefix string) {
+	if i >= len(ports) {
+		sb.WriteString("<td></td>")
+		return
+	}
+	port := ports[i]
+	typ := port.Type
+	if port.IsArray {
+		typ = "[]" + typ
+	}
+	fmt.Fprintf(
+		sb,
+		`<td port="%s">%s <i>%s</i></td>`,
+		anchorPrefix+port.Name,
+		html.EscapeString(port.Name),
+		html.EscapeString(typ),
+	)
+}
+
+func writeDOTEdge(sb *strings.Builder, edge Edge) {
+	attrs := []string{}
+	if edge.Kind == TriggerEdge {
+		attrs = append(attrs, "style=dashed")
+	}
+
+	from, label := dotEndpoint(edge.From, true)
+	if label != "" {
+		attrs = append(attrs, "taillabel="+strconv.Quote(label))
+	}
+
+	to, label := dotEndpoint(edge.To, false)
+	if label != "" {
+		attrs = append(attrs, "headlabel="+strconv.Quote(label))
+	}
+
+	if len(attrs) == 0 {
+		fmt.Fprintf(sb, "\t%s -> %s;\n", from, to)
+		return
+	}
+
+	fmt.Fprintf(sb, "\t%s -> %s [%s];\n", from, to, strings.Join(attrs, " "))
+}
+
+// dotEndpoint returns node id with port anchor if the vertex has such port, and label otherwise.
+// Slot index of array port is also returned as label.
+func dotEndpoint(endpoint Endpoint, isSender bool) (string, string) {
+	id := strconv.Quote(endpoint.Vertex.ID)
+	if endpoint.Port == "" {
+		return id, ""
+	}
+
+	ports, anchorPrefix := endpoint.Vertex.In, "in_"
+	if isSender {
+		ports, anchorPrefix = endpoint.Vertex.Out, "out_"
+	}
+
+	name := baseName(endpoint.Port)
+	for _, port := range ports {
+		if port.Name != name {
+			continue
+		}
+		if name == endpoint.Port {
+			return id + ":" + strconv.Quote(anchorPrefix+name), ""
+		}
+		return id + ":" + strconv.Quote(anchorPrefix+name), endpoint.Port[len(name):]
+	}
+
+	return id, endpoint.Port
+}
+
+// collectEdges returns edges of the graph and all its subgraphs.
+func collectEdges(g *Graph) []Edge {
+	edges := append([]Edge{}, g.Edges...)
+	for _, vertex := range g.Vertices {
+		if vertex.Sub != nil {
+			edges = append(edges, collectEdges(vertex.Sub)...)
+		}
+	}
+	return edges
+}
@@ -0,0 +1,526 @@
+// Package visualizer renders networks of analyzed components.
+// Unlike dot backend, it works with source code rather than IR,
+// so the graph looks the way the program is written.
+package visualizer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nevalang/neva/internal/compiler"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	ts "github.com/nevalang/neva/internal/compiler/sourcecode/typesystem"
+)
+
+// Graph is a network of a single component.
+type Graph struct {
+	Component string    // Reference to the component with type arguments.
+	In        *Vertex   // In sends messages from component's inports.
+	Out       *Vertex   // Out receives messages for component's outports.
+	Vertices  []*Vertex // Nodes and virtual vertices in order of appearance.
+	Edges     []Edge
+}
+
+type VertexKind string
+
+const (
+	IOVertex     VertexKind = "io"     // Inports or outports of the component itself.
+	NodeVertex   VertexKind = "node"   // Node of the component.
+	ConstVertex  VertexKind = "const"  // Constant sender.
+	ExprVertex   VertexKind = "expr"   // Range, operator or struct selector.
+	SwitchVertex VertexKind = "switch" // Switch receiver.
+	DeferVertex  VertexKind = "defer"  // Deferred connection.
+)
+
+type Vertex struct {
+	ID    string // ID is unique within the root graph.
+	Kind  VertexKind
+	Label string
+	In    []Port
+	Out   []Port
+	Sub   *Graph // Sub is network of node's component when node is drilled down.
+}
+
+type Port struct {
+	Name    string
+	Type    string
+	IsArray bool
+}
+
+type EdgeKind string
+
+const (
+	DataEdge    EdgeKind = "data"    // Message goes from sender to receiver.
+	TriggerEdge EdgeKind = "trigger" // Message only unblocks deferred connection.
+)
+
+type Edge struct {
+	From Endpoint
+	To   Endpoint
+	Kind EdgeKind
+}
+
+// Endpoint is a port of a vertex. Port is empty when it's not known or vertex has no ports.
+type Endpoint struct {
+	Vertex *Vertex
+	Port   string
+}
+
+// Build returns graph of the given component from the given package of the entry module.
+// Nodes with non-extern components are drilled down recursively up to the given depth.
+func Build(build src.Build, pkgName, componentName string, depth int) (*Graph, error) {
+	scope := src.NewScope(build, core.Location{
+		ModRef:  build.EntryModRef,
+		Package: pkgName,
+	})
+
+	entity, location, err := scope.Entity(core.EntityRef{Name: componentName})
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.Kind != src.ComponentEntity {
+		return nil, fmt.Errorf("entity is not a component: %v", componentName)
+	}
+
+	b := &builder{}
+
+	return b.buildGraph(
+		scope.Relocate(location),
+		entity.Component,
+		pkgName+"."+componentName,
+		"",
+		nil,
+		depth,
+	)
+}
+
+type builder struct {
+	virtualCounter int
+}
+
+// graphBuilder builds graph of one component.
+type graphBuilder struct {
+	*builder
+	graph  *Graph
+	prefix string
+	nodes  map[string]*Vertex
+}
+
+func (b *builder) buildGraph(
+	scope src.Scope,
+	component src.Component,
+	ref string,
+	prefix string,
+	typeArgs src.TypeArgs,
+	depth int,
+) (*Graph, error) {
+	if _, ok := component.Directives[compiler.ExternDirective]; ok {
+		return nil, errors.New("extern component has no network")
+	}
+
+	params := component.Interface.TypeParams.Params
+
+	gb := graphBuilder{
+		builder: b,
+		graph: &Graph{
+			Component: ref,
+			In: &Vertex{
+				ID:    prefix + "in",
+				Kind:  IOVertex,
+				Label: "in",
+				Out:   getPorts(component.Interface.IO.In, params, typeArgs),
+			},
+			Out: &Vertex{
+				ID:    prefix + "out",
+				Kind:  IOVertex,
+				Label: "out",
+				In:    getPorts(component.Interface.IO.Out, params, typeArgs),
+			},
+		},
+		prefix: prefix,
+		nodes:  make(map[string]*Vertex, len(component.Nodes)),
+	}
+
+	nodeNames := make([]string, 0, len(component.Nodes))
+	for name := range component.Nodes {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+
+	for _, name := range nodeNames {
+		vertex, err := gb.buildNodeVertex(scope, name, component.Nodes[name], depth)
+		if err != nil {
+			return nil, fmt.Errorf("node %v: %w", name, err)
+		}
+		gb.nodes[name] = vertex
+		gb.graph.Vertices = append(gb.graph.Vertices, vertex)
+	}
+
+	for _, conn := range component.Net {
+		if err := gb.processConnection(conn); err != nil {
+			return nil, err
+		}
+	}
+
+	return gb.graph, nil
+}
+
+func (gb *graphBuilder) buildNodeVertex(
+	scope src.Scope,
+	name string,
+	node src.Node,
+	depth int,
+) (*Vertex, error) {
+	entity, location, err := scope.Entity(node.EntityRef)
+	if err != nil {
+		return nil, err
+	}
+
+	var iface src.Interface
+	switch entity.Kind {
+	case src.ComponentEntity:
+		iface = entity.Component.Interface
+	case src.InterfaceEntity:
+		iface = entity.Interface
+	default:
+		return nil, fmt.Errorf("node entity is not a component or interface: %v", node.EntityRef)
+	}
+
+	ref := node.EntityRef.String()
+	if len(node.TypeArgs) > 0 {
+		ref += node.TypeArgs.String()
+	}
+
+	vertex := &Vertex{
+		ID:    gb.prefix + name,
+		Kind:  NodeVertex,
+		Label: name + " " + ref,
+		In:    getPorts(iface.IO.In, iface.TypeParams.Params, node.TypeArgs),
+		Out:   getPorts(iface.IO.Out, iface.TypeParams.Params, node.TypeArgs),
+	}
+
+	if depth == 0 || entity.Kind != src.ComponentEntity {
+		return vertex, nil
+	}
+
+	if _, ok := entity.Component.Directives[compiler.ExternDirective]; ok {
+		return vertex, nil
+	}
+
+	sub, err := gb.buildGraph(
+		scope.Relocate(location),
+		entity.Component,
+		ref,
+		vertex.ID+"/",
+		node.TypeArgs,
+		depth-1,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	vertex.Sub = sub
+
+	return vertex, nil
+}
+
+func (gb *graphBuilder) processConnection(conn src.Connection) error {
+	if conn.ArrayBypass != nil {
+		from, err := gb.portAddrEndpoint(conn.ArrayBypass.SenderOutport, true)
+		if err != nil {
+			return err
+		}
+		to, err := gb.portAddrEndpoint(conn.ArrayBypass.ReceiverInport, false)
+		if err != nil {
+			return err
+		}
+		gb.addEdge(from, to, DataEdge)
+		return nil
+	}
+
+	if conn.Normal == nil {
+		return nil
+	}
+
+	froms, err := gb.senderEndpoints(conn.Normal.Senders)
+	if err != nil {
+		return err
+	}
+
+	return gb.processReceivers(froms, conn.Normal.Receivers)
+}
+
+func (gb *graphBuilder) senderEndpoints(senders []src.ConnectionSender) ([]Endpoint, error) {
+	result := make([]Endpoint, 0, len(senders))
+	for _, sender := range senders {
+		from, err := gb.senderEndpoint(sender)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, from)
+	}
+	return result, nil
+}
+
+func (gb *graphBuilder) senderEndpoint(sender src.ConnectionSender) (Endpoint, error) {
+	switch {
+	case sender.PortAddr != nil:
+		return gb.portAddrEndpoint(*sender.PortAddr, true)
+	case sender.Const != nil:
+		return Endpoint{Vertex: gb.addVirtualVertex(ConstVertex, sender.String())}, nil
+	case sender.Range != nil, len(sender.StructSelector) != 0:
+		return Endpoint{Vertex: gb.addVirtualVertex(ExprVertex, sender.String())}, nil
+	}
+
+	var operands []src.ConnectionSender
+	switch {
+	case sender.Unary != nil:
+		operands = []src.ConnectionSender{sender.Unary.Operand}
+	case sender.Binary != nil:
+		operands = []src.ConnectionSender{sender.Binary.Left, sender.Binary.Right}
+	case sender.Ternary != nil:
+		operands = []src.ConnectionSender{
+			sender.Ternary.Condition,
+			sender.Ternary.Left,
+			sender.Ternary.Right,
+		}
+	default:
+		return Endpoint{}, fmt.Errorf("unknown sender: %v", sender)
+	}
+
+	vertex := gb.addVirtualVertex(ExprVertex, sender.String())
+
+	// constant operands are already visible in the label
+	for _, operand := range operands {
+		if operand.Const != nil {
+			continue
+		}
+		from, err := gb.senderEndpoint(operand)
+		if err != nil {
+			return Endpoint{}, err
+		}
+		gb.addEdge(from, Endpoint{Vertex: vertex}, DataEdge)
+	}
+
+	return Endpoint{Vertex: vertex}, nil
+}
+
+func (gb *graphBuilder) processReceivers(froms []Endpoint, receivers []src.ConnectionReceiver) error {
+	for _, receiver := range receivers {
+		var err error
+		switch {
+		case receiver.PortAddr != nil:
+			err = gb.processPortAddrReceiver(froms, *receiver.PortAddr)
+		case receiver.ChainedConnection != nil:
+			err = gb.processChainedReceiver(froms, *receiver.ChainedConnection)
+		case receiver.DeferredConnection != nil:
+			err = gb.processDeferredReceiver(froms, *receiver.DeferredConnection)
+		case receiver.Switch != nil:
+			err = gb.processSwitchReceiver(froms, *receiver.Switch)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gb *graphBuilder) processPortAddrReceiver(froms []Endpoint, portAddr src.PortAddr) error {
+	to, err := gb.portAddrEndpoint(portAddr, false)
+	if err != nil {
+		return err
+	}
+	for _, from := range froms {
+		gb.addEdge(from, to, DataEdge)
+	}
+	return nil
+}
+
+// processChainedReceiver handles `a -> b -> c` where chain head `b` both receives and sends.
+func (gb *graphBuilder) processChainedReceiver(froms []Endpoint, chained src.Connection) error {
+	if chained.Normal == nil || len(chained.Normal.Senders) == 0 {
+		return errors.New("chained connection without head")
+	}
+
+	head := chained.Normal.Senders[0]
+
+	var headIn, headOut Endpoint
+	if head.PortAddr != nil {
+		var err error
+		if headIn, err = gb.portAddrEndpoint(*head.PortAddr, false); err != nil {
+			return err
+		}
+		if headOut, err = gb.portAddrEndpoint(*head.PortAddr, true); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if headOut, err = gb.senderEndpoint(head); err != nil {
+			return err
+		}
+		headIn = headOut
+	}
+
+	for _, from := range froms {
+		gb.addEdge(from, headIn, DataEdge)
+	}
+
+	return gb.processReceivers([]Endpoint{headOut}, chained.Normal.Receivers)
+}
+
+// processDeferredReceiver handles `a -> { b -> c }` where `b` is sent to `c` only after `a`.
+func (gb *graphBuilder) processDeferredReceiver(froms []Endpoint, deferred src.Connection) error {
+	if deferred.Normal == nil {
+		return errors.New("deferred connection must be normal")
+	}
+
+	vertex := gb.addVirtualVertex(DeferVertex, "defer")
+
+	for _, from := range froms {
+		gb.addEdge(from, Endpoint{Vertex: vertex, Port: "sig"}, TriggerEdge)
+	}
+
+	senders, err := gb.senderEndpoints(deferred.Normal.Senders)
+	if err != nil {
+		return err
+	}
+	for _, sender := range senders {
+		gb.addEdge(sender, Endpoint{Vertex: vertex, Port: "data"}, DataEdge)
+	}
+
+	return gb.processReceivers([]Endpoint{{Vertex: vertex, Port: "data"}}, deferred.Normal.Receivers)
+}
+
+// processSwitchReceiver handles `a -> switch { b -> c, _ -> d }`.
+func (gb *graphBuilder) processSwitchReceiver(froms []Endpoint, sw src.Switch) error {
+	vertex := gb.addVirtualVertex(SwitchVertex, "switch")
+
+	for _, from := range froms {
+		gb.addEdge(from, Endpoint{Vertex: vertex, Port: "data"}, DataEdge)
+	}
+
+	for i, caseConn := range sw.Cases {
+		casePort := Endpoint{Vertex: vertex, Port: fmt.Sprintf("case[%d]", i)}
+
+		senders, err := gb.senderEndpoints(caseConn.Senders)
+		if err != nil {
+			return err
+		}
+		for _, sender := range senders {
+			gb.addEdge(sender, casePort, DataEdge)
+		}
+
+		if err := gb.processReceivers([]Endpoint{casePort}, caseConn.Receivers); err != nil {
+			return err
+		}
+	}
+
+	return gb.processReceivers([]Endpoint{{Vertex: vertex, Port: "else"}}, sw.Default)
+}
+
+// portAddrEndpoint returns endpoint for the port address.
+// If node is drilled down, endpoint points to its network's inports or outports.
+func (gb *graphBuilder) portAddrEndpoint(portAddr src.PortAddr, isSender bool) (Endpoint, error) {
+	port := portAddr.Port
+	if portAddr.Idx != nil {
+		port = fmt.Sprintf("%s[%d]", port, *portAddr.Idx)
+	}
+
+	if isSender && portAddr.Node == "in" {
+		return Endpoint{Vertex: gb.graph.In, Port: port}, nil
+	}
+	if !isSender && portAddr.Node == "out" {
+		return Endpoint{Vertex: gb.graph.Out, Port: port}, nil
+	}
+
+	vertex, ok := gb.nodes[portAddr.Node]
+	if !ok {
+		return Endpoint{}, fmt.Errorf("node not found: %v", portAddr.Node)
+	}
+
+	if vertex.Sub != nil {
+		if isSender {
+			return Endpoint{Vertex: vertex.Sub.Out, Port: port}, nil
+		}
+		return Endpoint{Vertex: vertex.Sub.In, Port: port}, nil
+	}
+
+	return Endpoint{Vertex: vertex, Port: port}, nil
+}
+
+func (gb *graphBuilder) addVirtualVertex(kind VertexKind, label string) *Vertex {
+	gb.virtualCounter++
+	vertex := &Vertex{
+		ID:    fmt.Sprintf("%s__%s__%d", gb.prefix, kind, gb.virtualCounter),
+		Kind:  kind,
+		Label: label,
+	}
+	gb.graph.Vertices = append(gb.graph.Vertices, vertex)
+	return vertex
+}
+
+func (gb *graphBuilder) addEdge(from, to Endpoint, kind EdgeKind) {
+	gb.graph.Edges = append(gb.graph.Edges, Edge{From: from, To: to, Kind: kind})
+}
+
+// getPorts returns ports sorted by name with type parameters replaced by type arguments.
+func getPorts(ports map[string]src.Port, params []ts.Param, args src.TypeArgs) []Port {
+	result := make([]Port, 0, len(ports))
+	for name, port := range ports {
+		result = append(result, Port{
+			Name:    name,
+			Type:    getTypeString(port.TypeExpr, params, args),
+			IsArray: port.IsArray,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func getTypeString(expr ts.Expr, params []ts.Param, args src.TypeArgs) string {
+	return substituteTypeParams(expr, params, args).String()
+}
+
+// substituteTypeParams replaces references to type parameters with type arguments.
+// Only instantiations are traversed, literal types are left as is.
+func substituteTypeParams(expr ts.Expr, params []ts.Param, args src.TypeArgs) ts.Expr {
+	if expr.Inst == nil {
+		return expr
+	}
+
+	if expr.Inst.Ref.Pkg == "" && len(expr.Inst.Args) == 0 {
+		for i, param := range params {
+			if param.Name == expr.Inst.Ref.Name && i < len(args) {
+				return args[i]
+			}
+		}
+		return expr
+	}
+
+	substitutedArgs := make([]ts.Expr, len(expr.Inst.Args))
+	for i, arg := range expr.Inst.Args {
+		substitutedArgs[i] = substituteTypeParams(arg, params, args)
+	}
+
+	return ts.Expr{
+		Inst: &ts.InstExpr{
+			Ref:  expr.Inst.Ref,
+			Args: substitutedArgs,
+		},
+		Meta: expr.Meta,
+	}
+}
+
+// baseName returns port name without slot index.
+func baseName(port string) string {
+	if i := strings.IndexByte(port, '['); i != -1 {
+		return port[:i]
+	}
+	return port
+}
@@ -0,0 +1,71 @@
+package visualizer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/internal/compiler"
+	"github.com/nevalang/neva/internal/compiler/parser"
+	src "github.com/nevalang/neva/internal/compiler/sourcecode"
+	"github.com/nevalang/neva/internal/compiler/sourcecode/core"
+	"github.com/nevalang/neva/internal/compiler/visualizer"
+)
+
+func TestBuild(t *testing.T) {
+	text := `
+def Main(start any) (stop any) {
+	first Pass<any>, second Pass<any>
+	---
+	:start -> first -> switch {
+		true -> second
+		_ -> :stop
+	}
+	second -> { 'done' -> :stop }
+}
+
+def Pass<T>(data T) (res T) {
+	:data -> :res
+}`
+
+	modRef := core.ModuleRef{Path: "@"}
+	pkgs, err := parser.New().ParsePackages(modRef, map[string]compiler.RawPackage{
+		"main": {"main": []byte(text)},
+	})
+	require.True(t, err == nil)
+
+	build := src.Build{
+		EntryModRef: modRef,
+		Modules: map[core.ModuleRef]src.Module{
+			modRef: {Packages: pkgs},
+		},
+	}
+
+	graph, buildErr := visualizer.Build(build, "main", "Main", 1)
+	require.NoError(t, buildErr)
+
+	require.Equal(t, "main.Main", graph.Component)
+	require.Len(t, graph.Vertices, 6) // 2 nodes, switch, case const, defer, deferred const
+
+	first := graph.Vertices[0]
+	require.Equal(t, "first Pass<any>", first.Label)
+	require.NotNil(t, first.Sub)
+	require.Equal(t, "first/in", first.Sub.In.ID)
+	require.Equal(t, []visualizer.Port{{Name: "data", Type: "any"}}, first.Sub.In.Out)
+
+	// edge to drilled down node goes to its network
+	require.Equal(t, graph.In, graph.Edges[0].From.Vertex)
+	require.Equal(t, first.Sub.In, graph.Edges[0].To.Vertex)
+
+	var dot bytes.Buffer
+	require.NoError(t, visualizer.EncodeDOT(&dot, graph))
+	require.Contains(t, dot.String(), `subgraph "cluster_first"`)
+	require.Contains(t, dot.String(), `style=dashed`)
+
+	var mermaid bytes.Buffer
+	require.NoError(t, visualizer.EncodeMermaid(&mermaid, graph))
+	require.Contains(t, mermaid.String(), "subgraph v_first")
+	require.Contains(t, mermaid.String(), `-.->`)
+	require.Contains(t, mermaid.String(), `|"else → stop"|`)
+}
@@ -0,0 +1,123 @@
+package visualizer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EncodeMermaid writes graph as Mermaid flowchart.
+// Drilled down nodes are rendered as subgraphs with their own networks inside.
+func EncodeMermaid(w io.Writer, g *Graph) error {
+	var sb strings.Builder
+
+	sb.WriteString("flowchart LR\n")
+	fmt.Fprintf(&sb, "\t%%%% %s\n", g.Component)
+
+	writeMermaidVertices(&sb, g, "\t")
+
+	for _, edge := range collectEdges(g) {
+		writeMermaidEdge(&sb, edge)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeMermaidVertices(sb *strings.Builder, g *Graph, indent string) {
+	writeMermaidVertex(sb, g.In, indent)
+	for _, vertex := range g.Vertices {
+		if vertex.Sub == nil {
+			writeMermaidVertex(sb, vertex, indent)
+			continue
+		}
+		fmt.Fprintf(sb, "%ssubgraph %s [\"%s\"]\n", indent, mermaidID(vertex.ID), mermaidText(vertex.Label))
+		writeMermaidVertices(sb, vertex.Sub, indent+"\t")
+		fmt.Fprintf(sb, "%send\n", indent)
+	}
+	writeMermaidVertex(sb, g.Out, indent)
+}
+
+func writeMermaidVertex(sb *strings.Builder, vertex *Vertex, indent string) {
+	id := mermaidID(vertex.ID)
+
+	switch vertex.Kind {
+	case ConstVertex, ExprVertex:
+		fmt.Fprintf(sb, "%s%s([\"%s\"])\n", indent, id, mermaidText(vertex.Label))
+		return
+	case SwitchVertex:
+		fmt.Fprintf(sb, "%s%s{\"%s\"}\n", indent, id, mermaidText(vertex.Label))
+		return
+	case DeferVertex:
+		fmt.Fprintf(sb, "%s%s((\"%s\"))\n", indent, id, mermaidText(vertex.Label))
+		return
+	}
+
+	lines := []string{"<b>" + mermaidText(vertex.Label) + "</b>"}
+	for _, port := range vertex.In {
+		lines = append(lines, "▶ "+mermaidPort(port))
+	}
+	for _, port := range vertex.Out {
+		lines = append(lines, mermaidPort(port)+" ▶")
+	}
+
+	fmt.Fprintf(sb, "%s%s[\"%s\"]\n", indent, id, strings.Join(lines, "<br/>"))
+}
+
+func mermaidPort(port Port) string {
+	typ := port.Type
+	if port.IsArray {
+		typ = "[]" + typ
+	}
+	return mermaidText(port.Name + " " + typ)
+}
+
+func writeMermaidEdge(sb *strings.Builder, edge Edge) {
+	arrow := "-->"
+	if edge.Kind == TriggerEdge {
+		arrow = "-.->"
+	}
+
+	var label string
+	switch {
+	case edge.From.Port != "" && edge.To.Port != "":
+		label = edge.From.Port + " → " + edge.To.Port
+	case edge.From.Port != "":
+		label = edge.From.Port
+	case edge.To.Port != "":
+		label = edge.To.Port
+	}
+
+	from, to := mermaidID(edge.From.Vertex.ID), mermaidID(edge.To.Vertex.ID)
+
+	if label == "" {
+		fmt.Fprintf(sb, "\t%s %s %s\n", from, arrow, to)
+		return
+	}
+
+	fmt.Fprintf(sb, "\t%s %s|\"%s\"| %s\n", from, arrow, mermaidText(label), to)
+}
+
+// mermaidID turns vertex id into identifier that can't clash with mermaid keywords.
+func mermaidID(id string) string {
+	var sb strings.Builder
+	sb.WriteString("v_")
+	for _, r := range id {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteString("__")
+		}
+	}
+	return sb.String()
+}
+
+var mermaidReplacer = strings.NewReplacer(
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+)
+
+func mermaidText(s string) string {
+	return mermaidReplacer.Replace(s)
+}
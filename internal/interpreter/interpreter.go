@@ -0,0 +1,121 @@
+// Package interpreter executes IR programs in the current process
+// instead of generating and compiling Go code for them.
+package interpreter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nevalang/neva/internal/compiler/ir"
+	"github.com/nevalang/neva/pkg/runtime"
+)
+
+var ErrNativeCode = errors.New("native code can't be executed in-process")
+
+// Run executes program until it sends message to the stop port or ctx is done.
+func Run(ctx context.Context, prog *ir.Program, opts ...runtime.Option) error {
+	rprog, err := Program(prog)
+	if err != nil {
+		return err
+	}
+	return runtime.Run(ctx, rprog, opts...)
+}
+
+// Program turns IR program into the one runtime can execute.
+// Programs that depend on modules with native code are not supported.
+func Program(prog *ir.Program) (runtime.Program, error) {
+	if len(prog.Natives) > 0 {
+		return runtime.Program{}, fmt.Errorf("%w: %v", ErrNativeCode, prog.Natives[0].Module)
+	}
+
+	b := runtime.NewProgramBuilder()
+
+	// graph must not contain intermediate connections to be supported by runtime
+	for sender, receiver := range ir.GraphReduction(prog.Connections) {
+		b.Connect(slotAddr(sender), slotAddr(receiver))
+	}
+
+	for _, call := range prog.Funcs {
+		var cfg runtime.Msg
+		if call.Msg != nil {
+			var err error
+			cfg, err = Msg(*call.Msg)
+			if err != nil {
+				return runtime.Program{}, fmt.Errorf("%v: %w", call.Ref, err)
+			}
+		}
+
+		in := make([]runtime.PortSlotAddr, len(call.IO.In))
+		for i, addr := range call.IO.In {
+			in[i] = slotAddr(addr)
+		}
+
+		out := make([]runtime.PortSlotAddr, len(call.IO.Out))
+		for i, addr := range call.IO.Out {
+			out[i] = slotAddr(addr)
+		}
+
+		b.Call(call.Ref, in, out, cfg)
+	}
+
+	return b.Build()
+}
+
+func slotAddr(addr ir.PortAddr) runtime.PortSlotAddr {
+	if addr.IsArray {
+		return runtime.Slot(addr.Path, addr.Port, addr.Idx)
+	}
+	return runtime.Port(addr.Path, addr.Port)
+}
+
+// Msg turns IR message into runtime message.
+func Msg(msg ir.Message) (runtime.Msg, error) {
+	switch msg.Type {
+	case ir.MsgTypeBool:
+		return runtime.NewBoolMsg(msg.Bool), nil
+	case ir.MsgTypeInt:
+		return runtime.NewIntMsg(msg.Int), nil
+	case ir.MsgTypeFloat:
+		return runtime.NewFloatMsg(msg.Float), nil
+	case ir.MsgTypeString:
+		return runtime.NewStringMsg(msg.String), nil
+	case ir.MsgTypeList:
+		list := make([]runtime.Msg, len(msg.List))
+		for i, el := range msg.List {
+			v, err := Msg(el)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return runtime.NewListMsg(list), nil
+	case ir.MsgTypeDict:
+		dict := make(map[string]runtime.Msg, len(msg.DictOrStruct))
+		for k, el := range msg.DictOrStruct {
+			v, err := Msg(el)
+			if err != nil {
+				return nil, err
+			}
+			dict[k] = v
+		}
+		return runtime.NewDictMsg(dict), nil
+	case ir.MsgTypeStruct:
+		names := make([]string, 0, len(msg.DictOrStruct))
+		for k := range msg.DictOrStruct {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		fields := make([]runtime.Msg, len(names))
+		for i, name := range names {
+			v, err := Msg(msg.DictOrStruct[name])
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = v
+		}
+		return runtime.NewStructMsg(names, fields), nil
+	}
+	return nil, fmt.Errorf("unknown msg type: %v", msg.Type)
+}
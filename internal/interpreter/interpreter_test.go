@@ -0,0 +1,85 @@
+package interpreter
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/internal/compiler/ir"
+	"github.com/nevalang/neva/pkg/runtime"
+)
+
+func TestRun(t *testing.T) {
+	var got atomic.Value
+
+	registry := runtime.NewRegistry()
+	registry.MustRegister("capture", runtime.FuncCreatorFunc(
+		func(io runtime.IO, cfg runtime.Msg) (func(context.Context), error) {
+			dataIn, err := io.In.Single("data")
+			if err != nil {
+				return nil, err
+			}
+			resOut, err := io.Out.Single("res")
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) {
+				if _, ok := dataIn.Receive(ctx); !ok {
+					return
+				}
+				got.Store(cfg)
+				resOut.Send(ctx, cfg)
+			}, nil
+		},
+	))
+
+	// in:start -> capture/in:data (intermediate connection) -> capture:data, capture:res -> out:stop
+	prog := &ir.Program{
+		Connections: map[ir.PortAddr]ir.PortAddr{
+			{Path: "in", Port: "start"}:        {Path: "capture/in", Port: "data"},
+			{Path: "capture/in", Port: "data"}: {Path: "capture", Port: "data"},
+			{Path: "capture", Port: "res"}:     {Path: "out", Port: "stop"},
+		},
+		Funcs: []ir.FuncCall{
+			{
+				Ref: "capture",
+				IO: ir.FuncIO{
+					In:  []ir.PortAddr{{Path: "capture", Port: "data"}},
+					Out: []ir.PortAddr{{Path: "capture", Port: "res"}},
+				},
+				Msg: &ir.Message{
+					Type: ir.MsgTypeStruct,
+					DictOrStruct: map[string]ir.Message{
+						"b": {Type: ir.MsgTypeList, List: []ir.Message{{Type: ir.MsgTypeInt, Int: 1}}},
+						"a": {Type: ir.MsgTypeString, String: "x"},
+					},
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, Run(ctx, prog, runtime.WithRegistry(registry)))
+	require.NoError(t, ctx.Err())
+
+	expected := runtime.NewStructMsg(
+		[]string{"a", "b"},
+		[]runtime.Msg{
+			runtime.NewStringMsg("x"),
+			runtime.NewListMsg([]runtime.Msg{runtime.NewIntMsg(1)}),
+		},
+	)
+	require.True(t, expected.Equal(got.Load().(runtime.Msg)))
+}
+
+func TestProgram_Natives(t *testing.T) {
+	_, err := Program(&ir.Program{
+		Natives: []ir.NativePackage{{Module: "github.com/foo/bar"}},
+	})
+	require.ErrorIs(t, err, ErrNativeCode)
+}
@@ -26,7 +26,7 @@ func (a args) Create(io runtime.IO, _ runtime.Msg) (func(ctx context.Context), e
 			return
 		}
 
-		result := make([]runtime.Msg, len(os.Args))
+		result := make([]runtime.Msg, 0, len(os.Args))
 		for i := range os.Args {
 			result = append(result, runtime.NewStringMsg(os.Args[i]))
 		}
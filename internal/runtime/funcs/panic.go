@@ -28,6 +28,11 @@ func (p panicker) Create(
 		cancel := ctx.Value("cancel").(context.CancelFunc)
 		cancel()
 
+		if reporter, ok := runtime.TestReporterFromContext(ctx); ok {
+			reporter.Fail(nodePath(msgIn.Addr()), fmt.Sprint("panic: ", panicMsg))
+			return
+		}
+
 		if _, err := fmt.Fprintln(os.Stderr, "panic:", panicMsg); err != nil {
 			panic(err)
 		}
@@ -65,9 +65,13 @@ func NewRegistry() map[string]runtime.FuncCreator {
 		"float_div":  floatDiv{},
 		"string_add": stringAdd{},
 
-		"int_inc": intInc{},
-		"int_dec": intDec{},
-		"int_mod": intMod{},
+		"int_inc":   intInc{},
+		"int_dec":   intDec{},
+		"int_neg":   intNeg{},
+		"int_mod":   intMod{},
+		"float_inc": floatInc{},
+		"float_dec": floatDec{},
+		"float_neg": floatNeg{},
 
 		"parse_int": parseInt{},
 
@@ -104,6 +108,10 @@ func NewRegistry() map[string]runtime.FuncCreator {
 
 		"int_pow": intPow{},
 
+		"testing_assert_equal": testingAssertEqual{},
+		"testing_expect_error": testingExpectError{},
+		"testing_fail":         testingFail{},
+
 		"int_bitwise_and": intBitwiseAnd{},
 		"int_bitwise_or":  intBitwiseOr{},
 		"int_bitwise_xor": intBitwiseXor{},
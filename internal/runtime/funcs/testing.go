@@ -0,0 +1,145 @@
+package funcs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/nevalang/neva/internal/runtime"
+)
+
+// failTest reports failure of the node that owns given inport to the test reporter and terminates the program.
+// If program is not executed as a test, path of the node and message are printed to stderr.
+func failTest(ctx context.Context, in runtime.SingleInport, msg string) {
+	cancel := ctx.Value("cancel").(context.CancelFunc)
+	cancel()
+
+	node := nodePath(in.Addr())
+
+	if reporter, ok := runtime.TestReporterFromContext(ctx); ok {
+		reporter.Fail(node, msg)
+		return
+	}
+
+	if _, err := fmt.Fprintf(os.Stderr, "fail: %s: %s\n", node, msg); err != nil {
+		panic(err)
+	}
+}
+
+// nodePath returns path of the node that owns the port.
+func nodePath(addr runtime.PortAddr) string {
+	path, _ := strings.CutSuffix(addr.Path, "/in")
+	return path
+}
+
+type testingAssertEqual struct{}
+
+func (testingAssertEqual) Create(io runtime.IO, _ runtime.Msg) (func(ctx context.Context), error) {
+	actualIn, err := io.In.Single("actual")
+	if err != nil {
+		return nil, err
+	}
+
+	expectedIn, err := io.In.Single("expected")
+	if err != nil {
+		return nil, err
+	}
+
+	resOut, err := io.Out.Single("res")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) {
+		for {
+			var (
+				wg                   sync.WaitGroup
+				actual, expected     runtime.Msg
+				actualOk, expectedOk bool
+			)
+
+			wg.Add(2)
+			go func() {
+				actual, actualOk = actualIn.Receive(ctx)
+				wg.Done()
+			}()
+			go func() {
+				expected, expectedOk = expectedIn.Receive(ctx)
+				wg.Done()
+			}()
+			wg.Wait()
+
+			if !actualOk || !expectedOk {
+				return
+			}
+
+			if !actual.Equal(expected) {
+				failTest(ctx, actualIn, fmt.Sprintf("expected %v, got %v", expected, actual))
+				return
+			}
+
+			if !resOut.Send(ctx, actual) {
+				return
+			}
+		}
+	}, nil
+}
+
+type testingExpectError struct{}
+
+func (testingExpectError) Create(io runtime.IO, _ runtime.Msg) (func(ctx context.Context), error) {
+	dataIn, err := io.In.Single("data")
+	if err != nil {
+		return nil, err
+	}
+
+	errIn, err := io.In.Single("err")
+	if err != nil {
+		return nil, err
+	}
+
+	errOut, err := io.Out.Single("err")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) {
+		go func() {
+			data, ok := dataIn.Receive(ctx)
+			if !ok {
+				return
+			}
+			failTest(ctx, dataIn, fmt.Sprintf("expected error, got %v", data))
+		}()
+
+		for {
+			errMsg, ok := errIn.Receive(ctx)
+			if !ok {
+				return
+			}
+
+			if !errOut.Send(ctx, errMsg) {
+				return
+			}
+		}
+	}, nil
+}
+
+type testingFail struct{}
+
+func (testingFail) Create(io runtime.IO, _ runtime.Msg) (func(ctx context.Context), error) {
+	msgIn, err := io.In.Single("msg")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) {
+		msg, ok := msgIn.Receive(ctx)
+		if !ok {
+			return
+		}
+		failTest(ctx, msgIn, msg.Str())
+	}, nil
+}
@@ -3,6 +3,7 @@ package runtime
 import (
 	"context"
 	"fmt"
+	gort "runtime"
 	"sort"
 	"sync"
 )
@@ -111,6 +112,11 @@ func (s SingleInport) Receive(ctx context.Context) (Msg, bool) {
 	return msg, true
 }
 
+// Addr returns address of the port.
+func (s SingleInport) Addr() PortAddr {
+	return s.addr
+}
+
 func (f Inports) Array(name string) (ArrayInport, error) {
 	ports, ok := f.ports[name]
 	if !ok {
@@ -268,6 +274,12 @@ func (a ArrayInport) _select(ctx context.Context) ([]SelectedMsg, bool) {
 			}
 		}
 
+		// let senders run, otherwise this loop starves them on platforms
+		// without goroutine preemption (e.g. wasm)
+		if len(buf) == 0 {
+			gort.Gosched()
+		}
+
 		i++
 	}
 
@@ -14,35 +14,72 @@ type FuncCreator interface {
 }
 
 func Run(ctx context.Context, prog Program, registry map[string]FuncCreator) error {
-	// debugValidation(prog)
+	run, err := Prepare(prog, registry)
+	if err != nil {
+		return err
+	}
+	return run(ctx)
+}
 
-	ctx, cancel := context.WithCancel(ctx)
-	go func() {
-		prog.Stop.Receive(ctx)
-		cancel() // normal termination
-	}()
+// Prepare creates function calls of the program without starting them.
+// Returned function runs the program like Run does, it must be called once.
+func Prepare(prog Program, registry map[string]FuncCreator) (func(ctx context.Context) error, error) {
+	// debugValidation(prog)
 
 	runFuncs, err := deferFuncCalls(prog.FuncCalls, registry)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		go func() {
+			prog.Stop.Receive(ctx)
+			cancel() // normal termination
+		}()
+
+		funcsFinished := make(chan struct{})
+
+		go func() {
+			// runFuncs blocks until context is cancelled (by the stop port or by panic)
+			runFuncs(context.WithValue(ctx, "cancel", cancel)) //nolint:staticcheck // SA1029
+			close(funcsFinished)
+		}()
+
+		prog.Start.Send(
+			ctx,
+			NewStructMsg(nil, nil),
+		)
+
+		<-funcsFinished
+
+		return nil
+	}, nil
+}
+
+// RunFuncCalls is like Run but it doesn't send start message and doesn't wait for stop message.
+// Instead, function calls are started in background and run until ctx is done or cancel is called.
+// Cancel must cancel the ctx, functions call it on panic.
+// Returned channel is closed after all function calls are finished.
+func RunFuncCalls(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	funcCalls []FuncCall,
+	registry map[string]FuncCreator,
+) (<-chan struct{}, error) {
+	runFuncs, err := deferFuncCalls(funcCalls, registry)
+	if err != nil {
+		return nil, err
 	}
 
 	funcsFinished := make(chan struct{})
 
 	go func() {
-		// runFuncs blocks until context is cancelled (by the stop port or by panic)
 		runFuncs(context.WithValue(ctx, "cancel", cancel)) //nolint:staticcheck // SA1029
 		close(funcsFinished)
 	}()
 
-	prog.Start.Send(
-		ctx,
-		NewStructMsg(nil, nil),
-	)
-
-	<-funcsFinished
-
-	return nil
+	return funcsFinished, nil
 }
 
 func deferFuncCalls(
@@ -0,0 +1,23 @@
+package runtime
+
+import "context"
+
+// TestReporter receives failures of the programs that are executed as tests.
+// Node is the path of the node that failed, e.g. "assert" or "helper/assert".
+type TestReporter interface {
+	Fail(node string, msg string)
+}
+
+type testReporterKey struct{}
+
+// WithTestReporter returns context that carries given test reporter.
+// Functions that fail tests report to it instead of printing to stderr.
+func WithTestReporter(ctx context.Context, reporter TestReporter) context.Context {
+	return context.WithValue(ctx, testReporterKey{}, reporter)
+}
+
+// TestReporterFromContext returns test reporter carried by the context, if any.
+func TestReporterFromContext(ctx context.Context) (TestReporter, bool) {
+	reporter, ok := ctx.Value(testReporterKey{}).(TestReporter)
+	return reporter, ok
+}
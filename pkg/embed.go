@@ -0,0 +1,10 @@
+package pkg
+
+import "embed"
+
+// Efs contains public runtime API. It's embedded into generated Go modules
+// so native code of Neva modules can depend on it.
+//
+//nolint:golint
+//go:embed runtime/*.go
+var Efs embed.FS
@@ -0,0 +1,282 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nevalang/neva/internal/runtime"
+)
+
+var (
+	// StartAddr is address of the port that sends the first message when program starts.
+	StartAddr = PortSlotAddr{PortAddr: PortAddr{Path: "in", Port: "start"}} //nolint:gochecknoglobals
+	// StopAddr is address of the port that terminates the program when receives a message.
+	StopAddr = PortSlotAddr{PortAddr: PortAddr{Path: "out", Port: "stop"}} //nolint:gochecknoglobals
+)
+
+// Port returns address of the single port.
+func Port(path, port string) PortSlotAddr {
+	return PortSlotAddr{PortAddr: PortAddr{Path: path, Port: port}}
+}
+
+// Slot returns address of the array port slot.
+func Slot(path, port string, idx uint8) PortSlotAddr {
+	return PortSlotAddr{PortAddr: PortAddr{Path: path, Port: port}, Index: &idx}
+}
+
+// FuncCall describes call of the function from the registry
+// and addresses of the ports it reads from and writes to.
+type FuncCall struct {
+	Ref    string
+	In     []PortSlotAddr
+	Out    []PortSlotAddr
+	Config Msg
+}
+
+// Program describes dataflow program that can be executed by Run.
+// Use ProgramBuilder to create one.
+type Program struct {
+	funcCalls   []FuncCall
+	connections map[slotKey]slotKey // sender -> receiver
+}
+
+// slotKey is comparable version of PortSlotAddr.
+type slotKey struct {
+	PortAddr
+	idx   uint8
+	array bool
+}
+
+func newSlotKey(addr PortSlotAddr) slotKey {
+	if addr.Index == nil {
+		return slotKey{PortAddr: addr.PortAddr}
+	}
+	return slotKey{PortAddr: addr.PortAddr, idx: *addr.Index, array: true}
+}
+
+// ProgramBuilder builds Program from function calls and connections between their ports.
+// Program must have connections from StartAddr and to StopAddr.
+type ProgramBuilder struct {
+	funcCalls   []FuncCall
+	connections map[slotKey]slotKey
+	receivers   map[slotKey]struct{}
+	errs        []error
+}
+
+func NewProgramBuilder() *ProgramBuilder {
+	return &ProgramBuilder{
+		connections: map[slotKey]slotKey{},
+		receivers:   map[slotKey]struct{}{},
+	}
+}
+
+// Call adds function call to the program.
+func (b *ProgramBuilder) Call(ref string, in, out []PortSlotAddr, cfg Msg) *ProgramBuilder {
+	b.funcCalls = append(b.funcCalls, FuncCall{
+		Ref:    ref,
+		In:     in,
+		Out:    out,
+		Config: cfg,
+	})
+	return b
+}
+
+// Connect connects sender outport slot to receiver inport slot.
+// Each slot can only be used in one connection.
+func (b *ProgramBuilder) Connect(sender, receiver PortSlotAddr) *ProgramBuilder {
+	senderKey, receiverKey := newSlotKey(sender), newSlotKey(receiver)
+
+	if _, ok := b.connections[senderKey]; ok {
+		b.errs = append(b.errs, fmt.Errorf("sender is already connected: %v", formatSlotKey(senderKey)))
+		return b
+	}
+
+	if _, ok := b.receivers[receiverKey]; ok {
+		b.errs = append(b.errs, fmt.Errorf("receiver is already connected: %v", formatSlotKey(receiverKey)))
+		return b
+	}
+
+	b.connections[senderKey] = receiverKey
+	b.receivers[receiverKey] = struct{}{}
+
+	return b
+}
+
+// Build validates that every port is connected and returns the program.
+func (b *ProgramBuilder) Build() (Program, error) {
+	errs := append([]error{}, b.errs...)
+
+	if _, ok := b.connections[newSlotKey(StartAddr)]; !ok {
+		errs = append(errs, errors.New("start port is not connected"))
+	}
+
+	if _, ok := b.receivers[newSlotKey(StopAddr)]; !ok {
+		errs = append(errs, errors.New("stop port is not connected"))
+	}
+
+	for _, call := range b.funcCalls {
+		for _, addr := range call.In {
+			if _, ok := b.receivers[newSlotKey(addr)]; !ok {
+				errs = append(errs, fmt.Errorf("%v: inport is not connected: %v", call.Ref, formatSlotKey(newSlotKey(addr))))
+			}
+		}
+		for _, addr := range call.Out {
+			if _, ok := b.connections[newSlotKey(addr)]; !ok {
+				errs = append(errs, fmt.Errorf("%v: outport is not connected: %v", call.Ref, formatSlotKey(newSlotKey(addr))))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return Program{}, errors.Join(errs...)
+	}
+
+	connections := make(map[slotKey]slotKey, len(b.connections))
+	for k, v := range b.connections {
+		connections[k] = v
+	}
+
+	return Program{
+		funcCalls:   append([]FuncCall{}, b.funcCalls...),
+		connections: connections,
+	}, nil
+}
+
+// toRuntime creates channels for every connection and turns program into the form runtime can execute.
+func (p Program) toRuntime(interceptor Interceptor) (runtime.Program, error) {
+	chans := make(map[slotKey]chan runtime.OrderedMsg, len(p.connections)*2)
+	for sender, receiver := range p.connections {
+		ch := make(chan runtime.OrderedMsg)
+		chans[sender] = ch
+		chans[receiver] = ch
+	}
+
+	funcCalls := make([]runtime.FuncCall, 0, len(p.funcCalls))
+	for _, call := range p.funcCalls {
+		inports, err := buildInports(call.In, chans, interceptor)
+		if err != nil {
+			return runtime.Program{}, fmt.Errorf("%v: %w", call.Ref, err)
+		}
+
+		outports, err := buildOutports(call.Out, chans, interceptor)
+		if err != nil {
+			return runtime.Program{}, fmt.Errorf("%v: %w", call.Ref, err)
+		}
+
+		funcCalls = append(funcCalls, runtime.FuncCall{
+			Ref: call.Ref,
+			IO: runtime.IO{
+				In:  runtime.NewInports(inports),
+				Out: runtime.NewOutports(outports),
+			},
+			Config: call.Config,
+		})
+	}
+
+	return runtime.Program{
+		Start:     runtime.NewSingleOutport(StartAddr.PortAddr, interceptor, chans[newSlotKey(StartAddr)]),
+		Stop:      runtime.NewSingleInport(chans[newSlotKey(StopAddr)], StopAddr.PortAddr, interceptor),
+		FuncCalls: funcCalls,
+	}, nil
+}
+
+func buildInports(
+	addrs []PortSlotAddr,
+	chans map[slotKey]chan runtime.OrderedMsg,
+	interceptor Interceptor,
+) (map[string]runtime.Inport, error) {
+	groups, err := groupSlots(addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]runtime.Inport, len(groups))
+	for name, keys := range groups {
+		if !keys[0].array {
+			result[name] = runtime.NewInport(
+				nil,
+				runtime.NewSingleInport(chans[keys[0]], keys[0].PortAddr, interceptor),
+			)
+			continue
+		}
+
+		slots := make([]<-chan runtime.OrderedMsg, len(keys))
+		for i, key := range keys {
+			slots[i] = chans[key]
+		}
+
+		result[name] = runtime.NewInport(
+			runtime.NewArrayInport(slots, keys[0].PortAddr, interceptor),
+			nil,
+		)
+	}
+
+	return result, nil
+}
+
+func buildOutports(
+	addrs []PortSlotAddr,
+	chans map[slotKey]chan runtime.OrderedMsg,
+	interceptor Interceptor,
+) (map[string]runtime.Outport, error) {
+	groups, err := groupSlots(addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]runtime.Outport, len(groups))
+	for name, keys := range groups {
+		if !keys[0].array {
+			result[name] = runtime.NewOutport(
+				runtime.NewSingleOutport(keys[0].PortAddr, interceptor, chans[keys[0]]),
+				nil,
+			)
+			continue
+		}
+
+		slots := make([]chan<- runtime.OrderedMsg, len(keys))
+		for i, key := range keys {
+			slots[i] = chans[key]
+		}
+
+		result[name] = runtime.NewOutport(
+			nil,
+			runtime.NewArrayOutport(keys[0].PortAddr, interceptor, slots),
+		)
+	}
+
+	return result, nil
+}
+
+// groupSlots groups addresses by port name and sorts array slots by index.
+func groupSlots(addrs []PortSlotAddr) (map[string][]slotKey, error) {
+	groups := map[string][]slotKey{}
+	for _, addr := range addrs {
+		groups[addr.Port] = append(groups[addr.Port], newSlotKey(addr))
+	}
+
+	for name, keys := range groups {
+		sort.Slice(keys, func(i, j int) bool { return keys[i].idx < keys[j].idx })
+		for i, key := range keys {
+			if !key.array {
+				if len(keys) != 1 {
+					return nil, fmt.Errorf("single port is used more than once: %v", name)
+				}
+				continue
+			}
+			if int(key.idx) != i {
+				return nil, fmt.Errorf("array port slots must be used without holes: %v", name)
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+func formatSlotKey(key slotKey) string {
+	if key.array {
+		return fmt.Sprintf("%v:%v[%v]", key.Path, key.Port, key.idx)
+	}
+	return fmt.Sprintf("%v:%v", key.Path, key.Port)
+}
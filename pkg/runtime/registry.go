@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nevalang/neva/internal/runtime/funcs"
+)
+
+// Registry maps extern references (the ones used in #extern directive) to function creators.
+type Registry map[string]FuncCreator
+
+// Register adds function creator under given reference.
+// It returns error if the reference is already taken, use Override to replace existing function.
+func (r Registry) Register(ref string, creator FuncCreator) error {
+	if _, ok := r[ref]; ok {
+		return fmt.Errorf("function already registered: %v", ref)
+	}
+	r[ref] = creator
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (r Registry) MustRegister(ref string, creator FuncCreator) {
+	if err := r.Register(ref, creator); err != nil {
+		panic(err)
+	}
+}
+
+// Override adds function creator under given reference, replacing existing one if any.
+func (r Registry) Override(ref string, creator FuncCreator) {
+	r[ref] = creator
+}
+
+// NewRegistry returns registry with all the functions of the standard library.
+func NewRegistry() Registry {
+	return Registry(funcs.NewRegistry())
+}
+
+// FuncCreatorFunc is an adapter to allow the use of ordinary functions as function creators.
+type FuncCreatorFunc func(io IO, cfg Msg) (func(ctx context.Context), error)
+
+// Create calls f(io, cfg).
+func (f FuncCreatorFunc) Create(io IO, cfg Msg) (func(ctx context.Context), error) {
+	return f(io, cfg)
+}
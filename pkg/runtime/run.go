@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"context"
+
+	"github.com/nevalang/neva/internal/runtime"
+)
+
+// Option configures Run and Prepare.
+type Option func(*runOptions)
+
+type runOptions struct {
+	registry     Registry
+	interceptors []Interceptor
+}
+
+// WithRegistry sets registry to look up functions in.
+// By default registry of the standard library is used.
+func WithRegistry(registry Registry) Option {
+	return func(o *runOptions) {
+		o.registry = registry
+	}
+}
+
+// WithInterceptor adds interceptor that is called for every sent and received message.
+// Can be used several times, interceptors are called in the order they were added.
+func WithInterceptor(interceptor Interceptor) Option {
+	return func(o *runOptions) {
+		o.interceptors = append(o.interceptors, interceptor)
+	}
+}
+
+// Run executes program until it sends message to the stop port or ctx is done.
+func Run(ctx context.Context, prog Program, opts ...Option) error {
+	prepared, err := Prepare(prog, opts...)
+	if err != nil {
+		return err
+	}
+	return prepared.Run(ctx)
+}
+
+// Prepared is a program with its channels and function calls created but not started.
+// It's useful when start of the program must not include its construction, e.g. in benchmarks.
+type Prepared struct {
+	run func(context.Context) error
+}
+
+// Prepare constructs program so it can be executed later. Options are the same as for Run.
+func Prepare(prog Program, opts ...Option) (Prepared, error) {
+	var o runOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.registry == nil {
+		o.registry = NewRegistry()
+	}
+
+	var interceptor Interceptor
+	switch len(o.interceptors) {
+	case 0:
+		interceptor = ProdInterceptor{}
+	case 1:
+		interceptor = o.interceptors[0]
+	default:
+		interceptor = chainInterceptor(o.interceptors)
+	}
+
+	rprog, err := prog.toRuntime(interceptor)
+	if err != nil {
+		return Prepared{}, err
+	}
+
+	run, err := runtime.Prepare(rprog, o.registry)
+	if err != nil {
+		return Prepared{}, err
+	}
+
+	return Prepared{run: run}, nil
+}
+
+// Run executes prepared program like the Run function does. Prepared program can only be run once.
+func (p Prepared) Run(ctx context.Context) error {
+	return p.run(ctx)
+}
+
+// chainInterceptor passes message through all interceptors one by one.
+type chainInterceptor []Interceptor
+
+func (c chainInterceptor) Sent(addr PortSlotAddr, msg Msg) Msg {
+	for _, interceptor := range c {
+		msg = interceptor.Sent(addr, msg)
+	}
+	return msg
+}
+
+func (c chainInterceptor) Received(addr PortSlotAddr, msg Msg) Msg {
+	for _, interceptor := range c {
+		msg = interceptor.Received(addr, msg)
+	}
+	return msg
+}
@@ -0,0 +1,155 @@
+package runtime_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nevalang/neva/pkg/runtime"
+)
+
+// answer sends 42 for every received signal.
+var answer = runtime.FuncCreatorFunc(func(io runtime.IO, _ runtime.Msg) (func(context.Context), error) {
+	sigIn, err := io.In.Single("sig")
+	if err != nil {
+		return nil, err
+	}
+	resOut, err := io.Out.Single("res")
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) {
+		for {
+			if _, ok := sigIn.Receive(ctx); !ok {
+				return
+			}
+			if !resOut.Send(ctx, runtime.NewIntMsg(42)) {
+				return
+			}
+		}
+	}, nil
+})
+
+type countingInterceptor struct {
+	sent, received atomic.Int64
+}
+
+func (c *countingInterceptor) Sent(_ runtime.PortSlotAddr, msg runtime.Msg) runtime.Msg {
+	c.sent.Add(1)
+	return msg
+}
+
+func (c *countingInterceptor) Received(_ runtime.PortSlotAddr, msg runtime.Msg) runtime.Msg {
+	c.received.Add(1)
+	return msg
+}
+
+func TestRun(t *testing.T) {
+	registry := runtime.NewRegistry()
+	registry.MustRegister("answer", answer)
+
+	var got atomic.Value
+	registry.MustRegister("capture", runtime.FuncCreatorFunc(
+		func(io runtime.IO, _ runtime.Msg) (func(context.Context), error) {
+			dataIn, err := io.In.Single("data")
+			if err != nil {
+				return nil, err
+			}
+			resOut, err := io.Out.Single("res")
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) {
+				msg, ok := dataIn.Receive(ctx)
+				if !ok {
+					return
+				}
+				got.Store(msg)
+				resOut.Send(ctx, msg)
+			}, nil
+		},
+	))
+
+	prog, err := runtime.NewProgramBuilder().
+		Call("answer", []runtime.PortSlotAddr{runtime.Port("answer/in", "sig")}, []runtime.PortSlotAddr{runtime.Port("answer/out", "res")}, nil).
+		Call("int_inc", []runtime.PortSlotAddr{runtime.Port("inc/in", "data")}, []runtime.PortSlotAddr{runtime.Port("inc/out", "res")}, nil).
+		Call("capture", []runtime.PortSlotAddr{runtime.Port("capture/in", "data")}, []runtime.PortSlotAddr{runtime.Port("capture/out", "res")}, nil).
+		Connect(runtime.StartAddr, runtime.Port("answer/in", "sig")).
+		Connect(runtime.Port("answer/out", "res"), runtime.Port("inc/in", "data")).
+		Connect(runtime.Port("inc/out", "res"), runtime.Port("capture/in", "data")).
+		Connect(runtime.Port("capture/out", "res"), runtime.StopAddr).
+		Build()
+	require.NoError(t, err)
+
+	first, second := &countingInterceptor{}, &countingInterceptor{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = runtime.Run(
+		ctx,
+		prog,
+		runtime.WithRegistry(registry),
+		runtime.WithInterceptor(first),
+		runtime.WithInterceptor(second),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, runtime.NewIntMsg(43), got.Load())
+	require.Equal(t, first.sent.Load(), second.sent.Load())
+	require.NotZero(t, first.received.Load())
+}
+
+func TestPrepare(t *testing.T) {
+	var created atomic.Int64
+	registry := runtime.NewRegistry()
+	registry.MustRegister("answer", runtime.FuncCreatorFunc(
+		func(io runtime.IO, cfg runtime.Msg) (func(context.Context), error) {
+			created.Add(1)
+			return answer.Create(io, cfg)
+		},
+	))
+
+	prog, err := runtime.NewProgramBuilder().
+		Call("answer", []runtime.PortSlotAddr{runtime.Port("answer/in", "sig")}, []runtime.PortSlotAddr{runtime.Port("answer/out", "res")}, nil).
+		Connect(runtime.StartAddr, runtime.Port("answer/in", "sig")).
+		Connect(runtime.Port("answer/out", "res"), runtime.StopAddr).
+		Build()
+	require.NoError(t, err)
+
+	counter := &countingInterceptor{}
+
+	prepared, err := runtime.Prepare(prog, runtime.WithRegistry(registry), runtime.WithInterceptor(counter))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created.Load())
+	require.Zero(t, counter.sent.Load())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, prepared.Run(ctx))
+	require.Equal(t, int64(1), created.Load())
+	require.NotZero(t, counter.sent.Load())
+}
+
+func TestProgramBuilder_Errors(t *testing.T) {
+	_, err := runtime.NewProgramBuilder().
+		Call("int_inc", []runtime.PortSlotAddr{runtime.Port("inc/in", "data")}, []runtime.PortSlotAddr{runtime.Port("inc/out", "res")}, nil).
+		Connect(runtime.StartAddr, runtime.Port("inc/in", "data")).
+		Connect(runtime.StartAddr, runtime.Port("other/in", "data")).
+		Build()
+
+	require.ErrorContains(t, err, "sender is already connected: in:start")
+	require.ErrorContains(t, err, "stop port is not connected")
+	require.ErrorContains(t, err, "int_inc: outport is not connected: inc/out:res")
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := runtime.NewRegistry()
+	require.Error(t, registry.Register("int_inc", answer))
+	require.NoError(t, registry.Register("answer", answer))
+	require.Error(t, registry.Register("answer", answer))
+}
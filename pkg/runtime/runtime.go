@@ -0,0 +1,69 @@
+// Package runtime is a public API for embedding Nevalang runtime into Go programs.
+// It allows to describe programs in Go, extend the registry with custom functions
+// and run programs with custom interceptors.
+//
+// Types are aliases to the internal ones so values can be passed
+// between this package and generated code without conversions.
+package runtime
+
+import (
+	"github.com/nevalang/neva/internal/runtime"
+)
+
+// Messages
+type (
+	Msg        = runtime.Msg
+	BoolMsg    = runtime.BoolMsg
+	IntMsg     = runtime.IntMsg
+	FloatMsg   = runtime.FloatMsg
+	StringMsg  = runtime.StringMsg
+	ListMsg    = runtime.ListMsg
+	DictMsg    = runtime.DictMsg
+	StructMsg  = runtime.StructMsg
+	UnionMsg   = runtime.UnionMsg
+	OrderedMsg = runtime.OrderedMsg
+)
+
+// Functions
+type (
+	FuncCreator   = runtime.FuncCreator
+	IO            = runtime.IO
+	Inports       = runtime.Inports
+	Outports      = runtime.Outports
+	SingleInport  = runtime.SingleInport
+	ArrayInport   = runtime.ArrayInport
+	SingleOutport = runtime.SingleOutport
+	ArrayOutport  = runtime.ArrayOutport
+	SelectedMsg   = runtime.SelectedMsg
+)
+
+// Ports and interceptors
+type (
+	PortAddr         = runtime.PortAddr
+	PortSlotAddr     = runtime.PortSlotAddr
+	Interceptor      = runtime.Interceptor
+	ProdInterceptor  = runtime.ProdInterceptor
+	DebugInterceptor = runtime.DebugInterceptor
+)
+
+func NewBoolMsg(b bool) BoolMsg { return runtime.NewBoolMsg(b) }
+
+func NewIntMsg(n int64) IntMsg { return runtime.NewIntMsg(n) }
+
+func NewFloatMsg(n float64) FloatMsg { return runtime.NewFloatMsg(n) }
+
+func NewStringMsg(s string) StringMsg { return runtime.NewStringMsg(s) }
+
+func NewListMsg(v []Msg) ListMsg { return runtime.NewListMsg(v) }
+
+func NewDictMsg(d map[string]Msg) DictMsg { return runtime.NewDictMsg(d) }
+
+func NewStructMsg(names []string, fields []Msg) StructMsg {
+	return runtime.NewStructMsg(names, fields)
+}
+
+func NewUnionMsg(tag uint8, value Msg) UnionMsg { return runtime.NewUnionMsg(tag, value) }
+
+// NewDebugInterceptor returns interceptor that writes every sent and received message to a file.
+// Don't forget to call Open before running the program.
+func NewDebugInterceptor() *DebugInterceptor { return runtime.NewDebugInterceptor() }